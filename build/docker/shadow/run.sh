@@ -24,4 +24,30 @@ else
   /usr/sbin/shadow &
 fi
 
-/usr/sbin/sshd -D
\ No newline at end of file
+# count remaining sshd processes, each serving an established tunnel session
+sessions() {
+  grep -lx sshd /proc/[0-9]*/comm 2>/dev/null | wc -l
+}
+
+# stop accepting new connections, and wait for established sessions to finish within grace period
+drain() {
+  echo "Draining connections in ${KT_GRACE_PERIOD} seconds ..."
+  kill -15 "${sshd_pid}" 2>/dev/null
+  for _ in $(seq 1 "${KT_GRACE_PERIOD}"); do
+    if [ "$(sessions)" -eq 0 ]; then
+      break
+    fi
+    sleep 1
+  done
+  echo "Shadow exiting"
+  exit 0
+}
+
+if [ -n "${KT_GRACE_PERIOD}" ]; then
+  /usr/sbin/sshd -D &
+  sshd_pid=$!
+  trap drain TERM
+  wait "${sshd_pid}"
+else
+  /usr/sbin/sshd -D
+fi
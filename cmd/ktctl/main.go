@@ -41,6 +41,13 @@ func main() {
 			return cmd.Help()
 		},
 		Example: "ktctl <command> [command options]",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if opt.Get().Global.PrintConfig {
+				general.PrintConfig(cmd)
+				os.Exit(0)
+			}
+			return opt.ResolveSecretOptions(cmd)
+		},
 	}
 
 	rootCmd.AddCommand(command.NewConnectCommand())
@@ -48,19 +55,35 @@ func main() {
 	rootCmd.AddCommand(command.NewMeshCommand())
 	rootCmd.AddCommand(command.NewPreviewCommand())
 	rootCmd.AddCommand(command.NewForwardCommand())
+	rootCmd.AddCommand(command.NewBenchmarkCommand())
 	rootCmd.AddCommand(command.NewRecoverCommand())
+	rootCmd.AddCommand(command.NewPauseCommand())
+	rootCmd.AddCommand(command.NewResumeCommand())
 	rootCmd.AddCommand(command.NewCleanCommand())
 	rootCmd.AddCommand(command.NewConfigCommand())
 	rootCmd.AddCommand(command.NewBirdseyeCommand())
+	rootCmd.AddCommand(command.NewStatusCommand())
+	rootCmd.AddCommand(command.NewStopCommand())
 	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
 	rootCmd.SetUsageTemplate(general.UsageTemplate(false))
 	rootCmd.SilenceUsage = true
 	rootCmd.SilenceErrors = true
 	opt.SetOptions(rootCmd, rootCmd.PersistentFlags(), opt.Get().Global, opt.GlobalFlags())
 
+	// clean up before exit if command panics
+	defer util.RecoverPanic("command", nil)
+
 	// process will hang here
 	if err := rootCmd.Execute(); err != nil {
 		log.Error().Msgf("Exit: %s", err)
 	}
 	general.CleanupWorkspace()
+	if general.DeadlineExceeded() {
+		log.Warn().Msgf("Exit: deadline %s exceeded", opt.Get().Global.Deadline)
+		os.Exit(util.ExitCodeDeadline)
+	}
+	if general.TunnelLost() {
+		log.Error().Msgf("Exit: ssh tunnel lost")
+		os.Exit(util.ExitCodeTunnelLost)
+	}
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -46,8 +47,8 @@ func main() {
 
 func usage() {
 	log.Info().Msgf(`Usage: 
-router %s <service-name> <service-port> <custom-version>
-router %s <custom-version>
+router %s <service-name> <service-port> <custom-version> [fallback-status-codes] [cookie-mark] [weight]
+router %s <custom-version> [fallback-status-codes] [cookie-mark] [weight]
 router %s <custom-version>
 `, actionSetup, actionAdd, actionRemove)
 }
@@ -64,6 +65,22 @@ func setup(args []string) {
 		Header:   header,
 		Versions: []string{version},
 	}
+	if len(args) > 3 && args[3] != "" {
+		ktConf.Fallbacks = map[string][]string{version: strings.Split(args[3], ",")}
+	}
+	if len(args) > 4 && args[4] != "" {
+		ktConf.Cookies = map[string]string{version: args[4]}
+	}
+	if len(args) > 5 {
+		weight, err := parseWeight(args[5])
+		if err != nil {
+			log.Error().Err(err).Msgf("Invalid weight")
+			os.Exit(1)
+		}
+		if weight > 0 {
+			ktConf.Weights = map[string]int{version: weight}
+		}
+	}
 	err := router.WriteKtConf(&ktConf)
 	if err != nil {
 		log.Error().Err(err).Msgf("Write kt config failed")
@@ -79,17 +96,34 @@ func setup(args []string) {
 
 func add(args []string) {
 	header, version := splitVersionMark(args[0])
-	err := updateRoute(header, version, actionAdd)
+	fallback := ""
+	if len(args) > 1 {
+		fallback = args[1]
+	}
+	cookie := ""
+	if len(args) > 2 {
+		cookie = args[2]
+	}
+	weight := 0
+	if len(args) > 3 {
+		var err error
+		if weight, err = parseWeight(args[3]); err != nil {
+			log.Error().Err(err).Msgf("Invalid weight")
+			os.Exit(1)
+		}
+	}
+	err := updateRoute(header, version, fallback, cookie, weight, actionAdd)
 	if err != nil {
 		log.Error().Err(err).Msgf("Update route with add failed")
-		return
+		// let caller know the version is not added
+		os.Exit(1)
 	}
 	log.Info().Msgf("Route updated.")
 }
 
 func remove(args []string) {
 	header, version := splitVersionMark(args[0])
-	err := updateRoute(header, version, actionRemove)
+	err := updateRoute(header, version, "", "", 0, actionRemove)
 	if err != nil {
 		log.Error().Err(err).Msgf("Update route with remove failed" )
 		return
@@ -110,7 +144,19 @@ func getPorts(portsParameter string) [][]string {
 	return ports
 }
 
-func updateRoute(header, version, action string) error {
+// parseWeight percentage of requests without version header routed to the version
+func parseWeight(weight string) (int, error) {
+	if weight == "" {
+		return 0, nil
+	}
+	w, err := strconv.Atoi(weight)
+	if err != nil || w < 0 || w > 100 {
+		return 0, fmt.Errorf("weight should be a number between 0 and 100, but got '%s'", weight)
+	}
+	return w, nil
+}
+
+func updateRoute(header, version, fallback, cookie string, weight int, action string) error {
 	ktConf, err := router.ReadKtConf()
 	if err != nil {
 		return err
@@ -120,7 +166,28 @@ func updateRoute(header, version, action string) error {
 	}
 	switch action {
 	case actionAdd:
+		if weight > 0 && ktConf.WeightedVersion() != "" {
+			return fmt.Errorf("requests without header are already split to version '%s'", ktConf.WeightedVersion())
+		}
 		ktConf.Versions = append(ktConf.Versions, version)
+		if fallback != "" {
+			if ktConf.Fallbacks == nil {
+				ktConf.Fallbacks = make(map[string][]string)
+			}
+			ktConf.Fallbacks[version] = strings.Split(fallback, ",")
+		}
+		if cookie != "" {
+			if ktConf.Cookies == nil {
+				ktConf.Cookies = make(map[string]string)
+			}
+			ktConf.Cookies[version] = cookie
+		}
+		if weight > 0 {
+			if ktConf.Weights == nil {
+				ktConf.Weights = make(map[string]int)
+			}
+			ktConf.Weights[version] = weight
+		}
 	case actionRemove:
 		versions := ktConf.Versions
 		for i, v := range versions {
@@ -129,6 +196,9 @@ func updateRoute(header, version, action string) error {
 				break
 			}
 		}
+		delete(ktConf.Fallbacks, version)
+		delete(ktConf.Cookies, version)
+		delete(ktConf.Weights, version)
 	}
 	err = router.WriteKtConf(ktConf)
 	if err != nil {
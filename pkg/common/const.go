@@ -17,4 +17,6 @@ const (
 	EnvVarDnsProtocol = "KT_DNS_PROTOCOL"
 	// EnvVarLogLevel environment variable for shadow pod log level
 	EnvVarLogLevel = "KT_LOG_LEVEL"
+	// EnvVarGracePeriod environment variable for seconds of shadow pod draining connections before exit
+	EnvVarGracePeriod = "KT_GRACE_PERIOD"
 )
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/benchmark"
+	"github.com/alibaba/kt-connect/pkg/kt/command/forward"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/proxy"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// NewBenchmarkCommand return new benchmark command
+func NewBenchmarkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Measure latency and throughput of requests to a service via connect tunnel",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("name of service to benchmark is required")
+			} else if len(args) > 1 {
+				return fmt.Errorf("too many service names are spcified (%s), should be one", strings.Join(args, ","))
+			}
+			if opt.Get().Benchmark.Requests <= 0 || opt.Get().Benchmark.Concurrency <= 0 {
+				return fmt.Errorf("--requests and --concurrency should be positive")
+			}
+			if !strings.HasPrefix(opt.Get().Benchmark.Path, "/") {
+				return fmt.Errorf("--path should start with '/', but got '%s'", opt.Get().Benchmark.Path)
+			}
+			return general.Prepare()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Benchmark(args[0])
+		},
+		Example: "ktctl benchmark <service-name> [command options]",
+	}
+
+	cmd.SetUsageTemplate(general.UsageTemplate(true))
+	opt.SetOptions(cmd, cmd.Flags(), opt.Get().Benchmark, opt.BenchmarkFlags())
+	return cmd
+}
+
+// Benchmark send requests to service via connect tunnel, and via kubernetes port-forward as baseline
+func Benchmark(serviceName string) error {
+	if util.GetDaemonRunning(util.ComponentConnect) < 0 {
+		return fmt.Errorf("no connect process is running, please run 'ktctl connect' first")
+	}
+	svc, err := cluster.Ins().GetService(serviceName, opt.Get().Global.Namespace)
+	if err != nil {
+		return err
+	}
+	port, err := getBenchmarkPort(svc)
+	if err != nil {
+		return err
+	}
+	bm := opt.Get().Benchmark
+	dial, route, err := getTunnelDialer()
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Sending %d requests to service %s via %s with concurrency %d ...", bm.Requests, serviceName,
+		route, bm.Concurrency)
+	tunnelUrl := fmt.Sprintf("http://%s%s", net.JoinHostPort(svc.Spec.ClusterIP, fmt.Sprint(port)), bm.Path)
+	results := []*benchmark.Result{benchmark.Run("tunnel", tunnelUrl, bm.Requests, bm.Concurrency, dial)}
+
+	if !bm.SkipBaseline {
+		localPort, err2 := forward.RedirectService(serviceName, util.GetRandomTcpPort(), port)
+		if err2 != nil {
+			return err2
+		}
+		log.Info().Msgf("Sending %d requests to service %s via port-forward as baseline ...", bm.Requests, serviceName)
+		baselineUrl := fmt.Sprintf("http://%s%s", util.LocalEndpoint("", localPort), bm.Path)
+		results = append(results, benchmark.Run("baseline", baselineUrl, bm.Requests, bm.Concurrency,
+			(&net.Dialer{}).DialContext))
+	}
+
+	log.Info().Msg("---------------------------------------------------------------")
+	for _, r := range results {
+		log.Info().Msgf(" %s", r.Summary())
+		if r.Failures > 0 {
+			log.Warn().Msgf(" %d requests via %s failed, last error: %s", r.Failures, r.Route, r.LastError)
+		}
+	}
+	if len(results) > 1 && len(results[0].Latencies) > 0 && len(results[1].Latencies) > 0 {
+		log.Info().Msgf(" Tunnel overhead: p50 %s, p99 %s",
+			results[0].Percentile(50)-results[1].Percentile(50), results[0].Percentile(99)-results[1].Percentile(99))
+	}
+	log.Info().Msg("---------------------------------------------------------------")
+	return nil
+}
+
+// getBenchmarkPort port specified, or the only port of service
+func getBenchmarkPort(svc *coreV1.Service) (int, error) {
+	port := opt.Get().Benchmark.Port
+	for _, p := range svc.Spec.Ports {
+		if port <= 0 && len(svc.Spec.Ports) == 1 || int(p.Port) == port {
+			return int(p.Port), nil
+		}
+	}
+	if port <= 0 {
+		return 0, fmt.Errorf("service '%s' has multiple ports, must specify one with --port", svc.Name)
+	}
+	return 0, fmt.Errorf("port %d not available for service %s", port, svc.Name)
+}
+
+// getTunnelDialer dial via socks5 proxy if specified, otherwise rely on route to cluster set up by connect
+func getTunnelDialer() (func(ctx context.Context, network, address string) (net.Conn, error), string, error) {
+	socksAddr := opt.Get().Benchmark.Socks
+	if socksAddr == "" {
+		return (&net.Dialer{Timeout: 10 * time.Second}).DialContext, "connect tunnel", nil
+	}
+	dialer, err := proxy.SOCKS5("tcp", socksAddr, nil, proxy.Direct)
+	if err != nil {
+		return nil, "", err
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.(proxy.ContextDialer).DialContext(ctx, network, address)
+	}, fmt.Sprintf("socks5 proxy %s", socksAddr), nil
+}
@@ -0,0 +1,116 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// dialFunc establish connection to an address in the way of a route
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// Result statistics of requests sent via one route
+type Result struct {
+	Route     string
+	Requests  int
+	Failures  int
+	Bytes     int64
+	Elapsed   time.Duration
+	Latencies []time.Duration
+	LastError error
+}
+
+// Run send requests to url with specified concurrency, each request uses a new connection,
+// so that the cost of establishing connection via the route is included in its latency
+func Run(route, url string, requests, concurrency int, dial dialFunc) *Result {
+	client := &http.Client{
+		Transport: &http.Transport{DialContext: dial, DisableKeepAlives: true},
+		Timeout:   10 * time.Second,
+	}
+	result := &Result{Route: route, Requests: requests}
+	var lock sync.Mutex
+	tasks := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		tasks <- i
+	}
+	close(tasks)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range tasks {
+				latency, size, err := request(client, url)
+				lock.Lock()
+				if err != nil {
+					result.Failures++
+					result.LastError = err
+				} else {
+					result.Latencies = append(result.Latencies, latency)
+					result.Bytes += size
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	result.Elapsed = time.Since(start)
+	sort.Slice(result.Latencies, func(i, j int) bool { return result.Latencies[i] < result.Latencies[j] })
+	return result
+}
+
+// request send one request and read the whole response, any status is accepted since only the route is measured
+func request(client *http.Client, url string) (time.Duration, int64, error) {
+	start := time.Now()
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	size, err := io.Copy(ioutil.Discard, resp.Body)
+	if err != nil {
+		return 0, 0, err
+	}
+	return time.Since(start), size, nil
+}
+
+// Percentile latency at specified percent of sorted latencies, using nearest-rank method
+func (r *Result) Percentile(percent int) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	rank := (percent*len(r.Latencies) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return r.Latencies[rank-1]
+}
+
+// Throughput successful requests and bytes per second
+func (r *Result) Throughput() (float64, float64) {
+	if r.Elapsed <= 0 {
+		return 0, 0
+	}
+	seconds := r.Elapsed.Seconds()
+	return float64(len(r.Latencies)) / seconds, float64(r.Bytes) / seconds
+}
+
+// Summary one line description of the result
+func (r *Result) Summary() string {
+	rps, bps := r.Throughput()
+	return fmt.Sprintf("%-9s %d/%d succeeded, %.1f req/s, %.1f KB/s, p50 %s, p90 %s, p99 %s, max %s",
+		r.Route, len(r.Latencies), r.Requests, rps, bps/1024, round(r.Percentile(50)), round(r.Percentile(90)),
+		round(r.Percentile(99)), round(r.Percentile(100)))
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(100 * time.Microsecond)
+}
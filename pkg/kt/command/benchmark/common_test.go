@@ -0,0 +1,25 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResult_Percentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 200; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	r := &Result{Latencies: latencies, Elapsed: 2 * time.Second, Bytes: 2048}
+	require.Equal(t, 100*time.Millisecond, r.Percentile(50))
+	require.Equal(t, 180*time.Millisecond, r.Percentile(90))
+	require.Equal(t, 198*time.Millisecond, r.Percentile(99))
+	require.Equal(t, 200*time.Millisecond, r.Percentile(100))
+	require.Equal(t, time.Millisecond, r.Percentile(0))
+	rps, bps := r.Throughput()
+	require.Equal(t, 100.0, rps)
+	require.Equal(t, 1024.0, bps)
+	require.Equal(t, time.Duration(0), (&Result{}).Percentile(50))
+}
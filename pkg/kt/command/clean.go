@@ -19,6 +19,9 @@ func NewCleanCommand() *cobra.Command {
 			if len(args) > 0 {
 				return fmt.Errorf("too many options specified (%s)", strings.Join(args, ",") )
 			}
+			if olderThan, err := clean.ParseOlderThan(); err != nil || olderThan < 0 {
+				return fmt.Errorf("invalid value '%s' of --olderThan, should be a positive duration like 24h", opt.Get().Clean.OlderThan)
+			}
 			return general.Prepare()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -56,6 +59,7 @@ func isEmpty(r *clean.ResourceToClean) bool {
 		len(r.ConfigMapsToDelete) == 0 &&
 		len(r.DeploymentsToDelete) == 0 &&
 		len(r.DeploymentsToScale) == 0 &&
+		len(r.StatefulSetsToScale) == 0 &&
 		len(r.ServicesToDelete) == 0 &&
 		len(r.ServicesToUnlock) == 0 &&
 		len(r.ServicesToRecover) == 0
@@ -12,9 +12,12 @@ import (
 	"io/ioutil"
 	appV1 "k8s.io/api/apps/v1"
 	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ResourceToClean struct {
@@ -23,6 +26,7 @@ type ResourceToClean struct {
 	ConfigMapsToDelete  []string
 	DeploymentsToDelete []string
 	DeploymentsToScale  map[string]int32
+	StatefulSetsToScale map[string]int32
 	ServicesToRecover   []string
 	ServicesToUnlock   []string
 }
@@ -40,23 +44,53 @@ func CheckClusterResources() (*ResourceToClean, error) {
 		ConfigMapsToDelete:  make([]string, 0),
 		DeploymentsToDelete: make([]string, 0),
 		DeploymentsToScale:  make(map[string]int32),
+		StatefulSetsToScale: make(map[string]int32),
 		ServicesToRecover:   make([]string, 0),
 		ServicesToUnlock:    make([]string, 0),
 	}
 	for _, pod := range pods {
+		if !isSessionMatched(pod.Annotations) {
+			continue
+		}
 		analysisExpiredPods(pod, opt.Get().Clean.ThresholdInMinus, &resourceToClean)
 	}
 	for _, cf := range cfs {
+		if !isSessionMatched(cf.Annotations) {
+			continue
+		}
 		analysisExpiredConfigmaps(cf, opt.Get().Clean.ThresholdInMinus, &resourceToClean)
 	}
 	for _, app := range apps {
+		if !isSessionMatched(app.Annotations) {
+			continue
+		}
 		analysisExpiredDeployments(app, opt.Get().Clean.ThresholdInMinus, &resourceToClean)
 	}
 	for _, svc := range svcs {
+		if !isSessionMatched(svc.Annotations) {
+			continue
+		}
 		analysisExpiredServices(svc, opt.Get().Clean.ThresholdInMinus, &resourceToClean)
 	}
-	svcList, err := cluster.Ins().GetAllServiceInNamespace(opt.Get().Global.Namespace)
-	analysisLockAndOrphanServices(svcList.Items, &resourceToClean)
+	if opt.Get().Clean.Session == "" {
+		// origin services are not created by kt, thus have no session annotation
+		svcList, err := cluster.Ins().GetAllServiceInNamespace(opt.Get().Global.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		analysisLockAndOrphanServices(svcList.Items, &resourceToClean)
+		// origin workloads kept scaled down by exchange are not created by kt either
+		appList, err := cluster.Ins().GetAllDeploymentInNamespace(opt.Get().Global.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		analysisScaledDownDeployments(appList.Items, &resourceToClean)
+		stsList, err := cluster.Ins().GetAllStatefulSetInNamespace(opt.Get().Global.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		analysisScaledDownStatefulSets(stsList.Items, &resourceToClean)
+	}
 	return &resourceToClean, nil
 }
 
@@ -97,6 +131,15 @@ func TidyClusterResources(r *ResourceToClean) {
 			log.Info().Msgf(" * %s", name)
 		}
 	}
+	log.Info().Msgf("Recovering %d scaled statefulsets", len(r.StatefulSetsToScale))
+	for name, replica := range r.StatefulSetsToScale {
+		err := cluster.Ins().ScaleStatefulSetTo(name, opt.Get().Global.Namespace, &replica)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed to scale statefulset %s to %d", name, replica)
+		} else {
+			log.Info().Msgf(" * %s", name)
+		}
+	}
 	log.Info().Msgf("Deleting %d unavailing services", len(r.ServicesToDelete))
 	for _, name := range r.ServicesToDelete {
 		err := cluster.Ins().RemoveService(name, opt.Get().Global.Namespace)
@@ -143,6 +186,10 @@ func PrintClusterResourcesToClean(r *ResourceToClean) {
 	for name, replica := range r.DeploymentsToScale {
 		log.Info().Msgf(" * %s -> %d", name, replica)
 	}
+	log.Info().Msgf("Find %d exchanged statefulsets to recover:", len(r.StatefulSetsToScale))
+	for name, replica := range r.StatefulSetsToScale {
+		log.Info().Msgf(" * %s -> %d", name, replica)
+	}
 	log.Info().Msgf("Find %d unavailing service to delete:", len(r.ServicesToDelete))
 	for _, name := range r.ServicesToDelete {
 		log.Info().Msgf(" * %s", name)
@@ -160,6 +207,8 @@ func PrintClusterResourcesToClean(r *ResourceToClean) {
 func TidyLocalResources() {
 	log.Debug().Msg("Cleaning up unused pid files")
 	cleanPidFiles()
+	log.Debug().Msg("Cleaning up unused signal files")
+	cleanSignalFiles()
 	log.Debug().Msg("Cleaning up unused local rsa keys")
 	util.CleanRsaKeys()
 	log.Debug().Msg("Cleaning up background logs")
@@ -197,6 +246,24 @@ func cleanPidFiles() {
 	}
 }
 
+func cleanSignalFiles() {
+	for _, info := range general.ListSignalFiles() {
+		if info.Alive {
+			continue
+		}
+		if info.Namespace != "" {
+			log.Info().Msgf("Removing remnant signal file of %s %s in namespace %s (pid %d)",
+				info.Component, info.Service, info.Namespace, info.Pid)
+		} else {
+			log.Info().Msgf("Removing remnant signal file %s", filepath.Base(info.Path))
+		}
+		if err := os.Remove(info.Path); err != nil {
+			log.Error().Err(err).Msgf("Delete signal file %s failed", filepath.Base(info.Path))
+		}
+		_ = os.Remove(general.ReadyFilePath(info.Path))
+	}
+}
+
 func parseComponentAndPid(pidFileName string) (string, int) {
 	startPos := strings.LastIndex(pidFileName, "-")
 	endPos := strings.Index(pidFileName, ".")
@@ -212,11 +279,8 @@ func parseComponentAndPid(pidFileName string) (string, int) {
 }
 
 func analysisExpiredPods(pod coreV1.Pod, cleanThresholdInMinus int64, resourceToClean *ResourceToClean) {
-	lastHeartBeat := util.ParseTimestamp(pod.Annotations[util.KtLastHeartBeat])
-	if lastHeartBeat < 0 {
-		log.Debug().Msgf("Pod %s does no have heart beat annotation", pod.Name)
-	} else if isExpired(lastHeartBeat, cleanThresholdInMinus) {
-		log.Debug().Msgf(" * pod %s expired, lastHeartBeat: %d ", pod.Name, lastHeartBeat)
+	if isUnavailing("Pod", pod.ObjectMeta, cleanThresholdInMinus) {
+		log.Debug().Msgf(" * pod %s expired, lastHeartBeat: %s ", pod.Name, pod.Annotations[util.KtLastHeartBeat])
 		if pod.DeletionTimestamp == nil {
 			resourceToClean.PodsToDelete = append(resourceToClean.PodsToDelete, pod.Name)
 		}
@@ -225,29 +289,20 @@ func analysisExpiredPods(pod coreV1.Pod, cleanThresholdInMinus int64, resourceTo
 }
 
 func analysisExpiredConfigmaps(cf coreV1.ConfigMap, cleanThresholdInMinus int64, resourceToClean *ResourceToClean) {
-	lastHeartBeat := util.ParseTimestamp(cf.Annotations[util.KtLastHeartBeat])
-	if lastHeartBeat < 0 {
-		log.Debug().Msgf("Configmap %s does no have heart beat annotation", cf.Name)
-	} else if isExpired(lastHeartBeat, cleanThresholdInMinus) {
+	if isUnavailing("Configmap", cf.ObjectMeta, cleanThresholdInMinus) {
 		resourceToClean.ConfigMapsToDelete = append(resourceToClean.ConfigMapsToDelete, cf.Name)
 	}
 }
 
 func analysisExpiredDeployments(app appV1.Deployment, cleanThresholdInMinus int64, resourceToClean *ResourceToClean) {
-	lastHeartBeat := util.ParseTimestamp(app.Annotations[util.KtLastHeartBeat])
-	if lastHeartBeat < 0 {
-		log.Debug().Msgf("Deployment %s does no have heart beat annotation", app.Name)
-	} else if isExpired(lastHeartBeat, cleanThresholdInMinus) {
+	if isUnavailing("Deployment", app.ObjectMeta, cleanThresholdInMinus) {
 		resourceToClean.DeploymentsToDelete = append(resourceToClean.DeploymentsToDelete, app.Name)
 		analysisConfigAnnotation(app.Labels[util.KtRole], util.String2Map(app.Annotations[util.KtConfig]), resourceToClean)
 	}
 }
 
 func analysisExpiredServices(svc coreV1.Service, cleanThresholdInMinus int64, resourceToClean *ResourceToClean) {
-	lastHeartBeat := util.ParseTimestamp(svc.Annotations[util.KtLastHeartBeat])
-	if lastHeartBeat < 0 {
-		log.Debug().Msgf("Service %s does no have heart beat annotation", svc.Name)
-	} else if isExpired(lastHeartBeat, cleanThresholdInMinus) {
+	if isUnavailing("Service", svc.ObjectMeta, cleanThresholdInMinus) {
 		resourceToClean.ServicesToDelete = append(resourceToClean.ServicesToDelete, svc.Name)
 	}
 }
@@ -276,6 +331,30 @@ func analysisLockAndOrphanServices(svcs []coreV1.Service, resourceToClean *Resou
 	}
 }
 
+func analysisScaledDownDeployments(apps []appV1.Deployment, resourceToClean *ResourceToClean) {
+	for _, app := range apps {
+		if app.Annotations == nil || app.Spec.Replicas == nil || *app.Spec.Replicas > 0 {
+			continue
+		}
+		replica, _ := strconv.ParseInt(app.Annotations[util.KtReplicas], 10, 32)
+		if replica > 0 {
+			resourceToClean.DeploymentsToScale[app.Name] = int32(replica)
+		}
+	}
+}
+
+func analysisScaledDownStatefulSets(stsList []appV1.StatefulSet, resourceToClean *ResourceToClean) {
+	for _, sts := range stsList {
+		if sts.Annotations == nil || sts.Spec.Replicas == nil || *sts.Spec.Replicas > 0 {
+			continue
+		}
+		replica, _ := strconv.ParseInt(sts.Annotations[util.KtReplicas], 10, 32)
+		if replica > 0 {
+			resourceToClean.StatefulSetsToScale[sts.Name] = int32(replica)
+		}
+	}
+}
+
 func analysisConfigAnnotation(role string, config map[string]string, resourceToClean *ResourceToClean) {
 	log.Debug().Msgf("   role %s, config: %v", role, config)
 	// scale exchange
@@ -283,7 +362,11 @@ func analysisConfigAnnotation(role string, config map[string]string, resourceToC
 		replica, _ := strconv.ParseInt(config["replicas"], 10, 32)
 		app := config["app"]
 		if replica > 0 && app != "" {
-			resourceToClean.DeploymentsToScale[app] = int32(replica)
+			if config["kind"] == general.KindStatefulSet {
+				resourceToClean.StatefulSetsToScale[app] = int32(replica)
+			} else {
+				resourceToClean.DeploymentsToScale[app] = int32(replica)
+			}
 		}
 	}
 	// auto mesh and selector exchange
@@ -313,6 +396,47 @@ func isRouterPodExist(svcName, namespace string) bool {
 	return err == nil
 }
 
+func isSessionMatched(annotations map[string]string) bool {
+	return opt.Get().Clean.Session == "" || annotations[util.KtSession] == opt.Get().Clean.Session
+}
+
+// isOwnerAlive check whether the ktctl process created a resource is still running, replaceable in test
+var isOwnerAlive = util.IsOwnerAlive
+
+// isUnavailing resource is no longer kept alive by any ktctl instance, and older than '--olderThan' if specified
+func isUnavailing(kind string, meta metav1.ObjectMeta, cleanThresholdInMinus int64) bool {
+	if isOwnerAlive(meta.Annotations[util.KtOwner]) {
+		log.Debug().Msgf("%s %s belongs to a running ktctl instance", kind, meta.Name)
+		return false
+	}
+	olderThan, _ := ParseOlderThan()
+	lastHeartBeat := util.ParseTimestamp(meta.Annotations[util.KtLastHeartBeat])
+	if lastHeartBeat < 0 {
+		if olderThan <= 0 {
+			log.Debug().Msgf("%s %s does no have heart beat annotation", kind, meta.Name)
+			return false
+		}
+		// never reported alive, judge by its age only
+		return util.GetTime()-meta.CreationTimestamp.Unix() > olderThan
+	}
+	if !isExpired(lastHeartBeat, cleanThresholdInMinus) {
+		return false
+	}
+	return olderThan <= 0 || util.GetTime()-lastHeartBeat > olderThan
+}
+
+// ParseOlderThan get seconds of '--olderThan' option, 0 if not specified
+func ParseOlderThan() (int64, error) {
+	if opt.Get().Clean.OlderThan == "" {
+		return 0, nil
+	}
+	age, err := time.ParseDuration(opt.Get().Clean.OlderThan)
+	if err != nil {
+		return 0, err
+	}
+	return int64(age.Seconds()), nil
+}
+
 func isExpired(lastHeartBeat, cleanThresholdInMinus int64) bool {
 	return util.GetTime() - lastHeartBeat > cleanThresholdInMinus*60
 }
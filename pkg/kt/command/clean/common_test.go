@@ -1,7 +1,14 @@
 package clean
 
 import (
+	"strconv"
 	"testing"
+	"time"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_toPid(t *testing.T) {
@@ -18,3 +25,39 @@ func Test_toPid(t *testing.T) {
 		t.Errorf("unmatch %d", pid)
 	}
 }
+
+func Test_isUnavailing(t *testing.T) {
+	now := util.GetTime()
+	meta := func(heartBeat, created int64, owner string) metav1.ObjectMeta {
+		annotations := map[string]string{util.KtOwner: owner}
+		if heartBeat > 0 {
+			annotations[util.KtLastHeartBeat] = strconv.FormatInt(heartBeat, 10)
+		}
+		return metav1.ObjectMeta{Name: "kt-test", Annotations: annotations,
+			CreationTimestamp: metav1.NewTime(time.Unix(created, 0))}
+	}
+	cases := []struct {
+		olderThan string
+		meta      metav1.ObjectMeta
+		expected  bool
+	}{
+		{"", meta(now-60, now-3600, "remote/1"), false},
+		{"", meta(now-1200, now-3600, "remote/1"), true},
+		{"", meta(0, now-3600, "remote/1"), false},
+		{"1h", meta(now-1200, now-7200, "remote/1"), false},
+		{"1h", meta(now-4000, now-7200, "remote/1"), true},
+		{"1h", meta(now-60, now-90000, "remote/1"), false},
+		{"1h", meta(0, now-7200, "remote/1"), true},
+		{"1h", meta(0, now-60, "remote/1"), false},
+		{"1h", meta(now-4000, now-7200, util.GetOwner()), false},
+	}
+	isOwnerAlive = func(owner string) bool {
+		return owner == util.GetOwner()
+	}
+	defer func() { isOwnerAlive = util.IsOwnerAlive }()
+	for i, c := range cases {
+		opt.Get().Clean.OlderThan = c.olderThan
+		require.Equal(t, c.expected, isUnavailing("Pod", c.meta, 15), "case %d", i)
+	}
+	opt.Get().Clean.OlderThan = ""
+}
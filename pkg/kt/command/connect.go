@@ -3,8 +3,6 @@ package command
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"time"
 
 	"strings"
 
@@ -27,12 +25,18 @@ func NewConnectCommand() *cobra.Command {
 			if len(args) > 0 {
 				return fmt.Errorf("too many options specified (%s)", strings.Join(args, ","))
 			}
+			if opt.Get().Global.ValidateOnly {
+				return general.Prepare()
+			}
 			if err := preCheck(); err != nil {
 				return err
 			}
 			return general.Prepare()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opt.Get().Global.ValidateOnly {
+				return validateConnect()
+			}
 			return Connect()
 		},
 		Example: "ktctl connect [command options]",
@@ -45,7 +49,10 @@ func NewConnectCommand() *cobra.Command {
 
 // Connect setup vpn to kubernetes cluster
 func Connect() error {
-	ch, err := general.SetupProcess(util.ComponentConnect)
+	if opt.Get().Global.DryRun {
+		return fmt.Errorf("dry run is not supported by connect command")
+	}
+	ctx, ch, err := general.SetupProcess(util.ComponentConnect)
 	if err != nil {
 		return err
 	}
@@ -55,66 +62,83 @@ func Connect() error {
 	}
 
 	// Setup signal file watcher
-	signalFile := filepath.Join(os.TempDir(), fmt.Sprintf("ktctl-connect-signal-%d", os.Getpid()))
-	go watchConnectSignalFile(signalFile, ch)
+	signalFile := general.SignalFilePath(util.ComponentConnect, "")
+	go general.WatchSignalFile(signalFile, "", ch)
+	pipeName := general.WatchControlPipe(util.ComponentConnect, ch)
 
+	connect.ResolveMode()
 	log.Info().Msgf("Using %s mode", opt.Get().Connect.Mode)
-	if opt.Get().Connect.Mode == util.ConnectModeTun2Socks {
-		err = connect.ByTun2Socks()
-	} else if opt.Get().Connect.Mode == util.ConnectModeShuttle {
-		err = connect.BySshuttle()
-	} else {
-		err = fmt.Errorf("invalid connect mode: '%s', supportted mode are %s, %s", opt.Get().Connect.Mode,
-			util.ConnectModeTun2Socks, util.ConnectModeShuttle)
-	}
+	err = general.RunSetup(ctx, func() error {
+		if err := connect.CheckModePrerequisites(opt.Get().Connect.Mode); err != nil {
+			return fmt.Errorf("connect mode %s is unavailable, %s", opt.Get().Connect.Mode, err.Error())
+		} else if opt.Get().Connect.Mode == util.ConnectModeTun2Socks {
+			return connect.ByTun2Socks()
+		} else if opt.Get().Connect.Mode == util.ConnectModeSocks5 {
+			return connect.BySocks5()
+		} else if opt.Get().Connect.Mode == util.ConnectModeShuttle {
+			return connect.BySshuttle()
+		}
+		return nil
+	})
 	if err != nil {
 		// Clean up signal file
 		os.RemoveAll(signalFile)
+		if opt.Get().Connect.Reconnect && general.ReconnectCount() > 0 && general.IsRetryableSetupError(err) &&
+			!general.DeadlineExceeded() {
+			// cluster may still be unavailable when reconnecting, keep trying
+			general.Reconnect(err.Error())
+		}
 		return err
 	}
+	if opt.Get().Connect.Reconnect {
+		if count := general.ReconnectCount(); count > 0 {
+			log.Info().Msgf("Connection re-established (reconnect cycle %d)", count)
+		}
+		go connect.SuperviseConnection()
+	}
 	log.Info().Msg("---------------------------------------------------------------")
 	log.Info().Msgf(" All looks good, now you can access to resources in the kubernetes cluster")
 	log.Info().Msg("---------------------------------------------------------------")
 
-	if util.IsWindows() {
-		log.Info().Msgf("You can stop the connection by creating a signal file:")
-		log.Info().Msgf("PowerShell:   \"stop\" | Out-File -FilePath %s -Encoding ASCII", signalFile)
-		log.Info().Msgf("Command Prompt: echo stop > %s", signalFile)
-	} else {
-		log.Info().Msgf("You can stop the connection by creating a signal file: echo stop > %s", signalFile)
-	}
+	general.PrintSetupResult(general.NewSetupResult(nil, opt.Get().Connect.Mode, ""), signalFile, pipeName)
+	general.PrintStopHint("connection", signalFile, pipeName)
 
 	// watch background process, clean the workspace and exit if background process occur exception
-	s := <-ch
-	log.Info().Msgf("Terminal signal is %s", s)
+	general.WaitForStop(ctx, ch)
 
 	// Clean up signal file
 	os.RemoveAll(signalFile)
 	return nil
 }
 
-func watchConnectSignalFile(signalFile string, ch chan os.Signal) {
-	// Create the signal file to indicate connect is ready
-	os.Create(signalFile)
-
-	for {
-		time.Sleep(1 * time.Second)
-
-		// Check if signal file contains "stop"
-		if content, err := os.ReadFile(signalFile); err == nil {
-			if strings.TrimSpace(string(content)) == "stop" {
-				// Send interrupt signal to the main routine
-				ch <- os.Interrupt
-				return
-			}
-		}
-	}
-}
-
 func preCheck() error {
-	if err := checkPermissionAndOptions(); err != nil {
+	if err := checkAdminPermission(); err != nil {
 		return err
 	}
+	if err := checkConnectOptions(); err != nil {
+		return err
+	}
+	return checkConnectRunning()
+}
+
+func validateConnect() error {
+	return general.RunChecks([]general.Check{
+		{Name: "Connect options", Run: checkConnectOptions},
+		{Name: "Administrator privilege", Run: checkAdminPermission},
+		{Name: "Connect mode prerequisites", Run: func() error {
+			connect.ResolveMode()
+			return connect.CheckModePrerequisites(opt.Get().Connect.Mode)
+		}},
+		{Name: "Running connect process", Run: checkConnectRunning},
+		{Name: "Permissions", Run: func() error {
+			return general.CheckPermissions(append(general.ShadowPermissions(),
+				cluster.PermissionRule{Verb: "list", Resource: "pods"},
+				cluster.PermissionRule{Verb: "list", Resource: "services"}))
+		}},
+	})
+}
+
+func checkConnectRunning() error {
 	if pid := util.GetDaemonRunning(util.ComponentConnect); pid > 0 {
 		return fmt.Errorf("another connect process already running at %d, exiting", pid)
 	}
@@ -138,15 +162,40 @@ func silenceCleanup() {
 	}
 }
 
-func checkPermissionAndOptions() error {
+func checkAdminPermission() error {
+	if opt.Get().Connect.Mode == util.ConnectModeSocks5 || opt.Get().Connect.Mode == util.ConnectModeAuto {
+		// socks5 mode needs no privilege, auto mode falls back to socks5 without privilege
+		return nil
+	}
 	if !util.IsRunAsAdmin() {
 		if util.IsWindows() {
 			return fmt.Errorf("permission declined, please re-run connect command as Administrator")
 		}
 		return fmt.Errorf("permission declined, please re-run connect command with 'sudo'")
 	}
+	return nil
+}
+
+func checkConnectOptions() error {
+	if !util.Contains([]string{util.ConnectModeTun2Socks, util.ConnectModeSocks5, util.ConnectModeShuttle,
+		util.ConnectModeAuto}, opt.Get().Connect.Mode) {
+		return fmt.Errorf("invalid connect mode: '%s', supportted mode are %s, %s, %s, %s", opt.Get().Connect.Mode,
+			util.ConnectModeTun2Socks, util.ConnectModeSocks5, util.ConnectModeShuttle, util.ConnectModeAuto)
+	}
 	if opt.Get().Connect.Mode == util.ConnectModeTun2Socks && opt.Get().Connect.DnsMode == util.DnsModePodDns {
 		return fmt.Errorf("dns mode '%s' is not available for connect mode '%s'", util.DnsModePodDns, util.ConnectModeTun2Socks)
 	}
+	if opt.Get().Connect.PodDomain && !strings.HasPrefix(opt.Get().Connect.DnsMode, util.DnsModeLocalDns) {
+		return fmt.Errorf("'--podDomain' is only available in dns mode '%s'", util.DnsModeLocalDns)
+	}
+	if opt.Get().Connect.PodDomain && opt.Get().Connect.DisablePodIp {
+		return fmt.Errorf("'--podDomain' cannot be used with '--disablePodIp', pod ip would be unreachable")
+	}
+	if opt.Get().Connect.Fwmark < 0 {
+		return fmt.Errorf("fwmark should not be negative, but got %d", opt.Get().Connect.Fwmark)
+	}
+	if opt.Get().Connect.Fwmark > 0 && !util.FwmarkSupported {
+		log.Warn().Msgf("Option '--fwmark' is only supported on linux, ignored")
+	}
 	return nil
 }
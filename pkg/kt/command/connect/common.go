@@ -35,6 +35,11 @@ func setupDns(shadowPodName, shadowPodIp string) error {
 			return err
 		}
 		watchServicesAndPods(opt.Get().Global.Namespace, svcToIp, headlessPods, true)
+		if opt.Get().Connect.PodDomain {
+			if err := setupPodDomains(opt.Get().Global.Namespace); err != nil {
+				return err
+			}
+		}
 
 		forwardedPodPort := util.GetRandomTcpPort()
 		if _, err := transmission.SetupPortForwardToLocal(shadowPodName, common.StandardDnsPort, forwardedPodPort); err != nil {
@@ -0,0 +1,67 @@
+package connect
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshuttle"
+	"github.com/alibaba/kt-connect/pkg/kt/service/tun"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"os/exec"
+)
+
+// ResolveMode choose the most capable connect mode available when 'auto' mode is specified
+func ResolveMode() {
+	if opt.Get().Connect.Mode != util.ConnectModeAuto {
+		return
+	}
+	err := CheckModePrerequisites(util.ConnectModeTun2Socks)
+	if err == nil && opt.Get().Connect.DnsMode == util.DnsModePodDns {
+		err = fmt.Errorf("dns mode '%s' is not available", util.DnsModePodDns)
+	}
+	if err == nil {
+		opt.Get().Connect.Mode = util.ConnectModeTun2Socks
+	} else if err2 := CheckModePrerequisites(util.ConnectModeShuttle); err2 == nil {
+		log.Debug().Msgf("Connect mode %s unavailable: %s", util.ConnectModeTun2Socks, err.Error())
+		opt.Get().Connect.Mode = util.ConnectModeShuttle
+	} else {
+		log.Debug().Msgf("Connect mode %s unavailable: %s", util.ConnectModeTun2Socks, err.Error())
+		log.Debug().Msgf("Connect mode %s unavailable: %s", util.ConnectModeShuttle, err2.Error())
+		opt.Get().Connect.Mode = util.ConnectModeSocks5
+	}
+	log.Info().Msgf("Auto selected %s mode", opt.Get().Connect.Mode)
+}
+
+// CheckModePrerequisites check privilege and tools required by specified connect mode
+func CheckModePrerequisites(mode string) error {
+	switch mode {
+	case util.ConnectModeTun2Socks:
+		if !util.IsRunAsAdmin() {
+			return fmt.Errorf("administrator privilege is required to create tun device")
+		}
+		if opt.Get().Connect.DisableTunDevice {
+			return nil
+		}
+		return tun.Ins().CheckContext()
+	case util.ConnectModeShuttle:
+		if util.IsWindows() {
+			return fmt.Errorf("sshuttle is not supported on windows")
+		}
+		if opt.Get().Connect.PacPort > 0 || opt.Get().Connect.HttpProxyPort > 0 {
+			return fmt.Errorf("--pacPort and --httpProxyPort require local proxy, which is not available in sshuttle mode")
+		}
+		if !util.IsRunAsAdmin() {
+			return fmt.Errorf("administrator privilege is required to modify firewall rules")
+		}
+		if !util.CanRun(sshuttle.Ins().Version()) {
+			if _, err := exec.LookPath("pip3"); err != nil {
+				return fmt.Errorf("sshuttle is not installed, and 'pip3' is not found to install it")
+			}
+		}
+		return nil
+	case util.ConnectModeSocks5, util.ConnectModeAuto:
+		return nil
+	}
+	return fmt.Errorf("invalid connect mode: '%s', supportted mode are %s, %s, %s, %s", mode,
+		util.ConnectModeTun2Socks, util.ConnectModeSocks5, util.ConnectModeShuttle, util.ConnectModeAuto)
+}
@@ -0,0 +1,98 @@
+package connect
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// pacContent current proxy auto-config script served to browser
+var pacContent string
+var pacLock sync.RWMutex
+
+// setupPacServer serve proxy auto-config file which sends cluster hosts to socks5 proxy,
+// and regenerate it when services in namespace changed
+func setupPacServer() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", opt.Get().Connect.PacPort))
+	if err != nil {
+		return fmt.Errorf("failed to serve pac file on port %d: %s", opt.Get().Connect.PacPort, err)
+	}
+	refreshPac()
+	namespace := opt.Get().Global.Namespace
+	refresh := func(svc *coreV1.Service) {
+		refreshPac()
+	}
+	go cluster.Ins().WatchService("", namespace, refresh, refresh, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/proxy.pac", func(w http.ResponseWriter, r *http.Request) {
+		pacLock.RLock()
+		defer pacLock.RUnlock()
+		w.Header().Set("Content-Type", "application/x-ns-proxy-autoconfig")
+		_, _ = w.Write([]byte(pacContent))
+	})
+	util.GoWithRecover("pac server", func() {
+		if err2 := http.Serve(listener, mux); err2 != nil {
+			log.Warn().Err(err2).Msgf("Pac server stopped")
+		}
+	})
+	log.Info().Msgf("Proxy auto-config file is available at http://localhost:%d/proxy.pac", opt.Get().Connect.PacPort)
+	return nil
+}
+
+// refreshPac regenerate pac content with current services and cluster ip ranges
+func refreshPac() {
+	svcToIp, _ := getServiceHosts(opt.Get().Global.Namespace, false)
+	hosts := make([]string, 0, len(svcToIp))
+	for host := range svcToIp {
+		hosts = append(hosts, host)
+	}
+	cidrs, _ := cluster.Ins().ClusterCidr(opt.Get().Global.Namespace)
+	if opt.Get().Connect.IncludeIps != "" {
+		cidrs = append(cidrs, strings.Split(opt.Get().Connect.IncludeIps, ",")...)
+	}
+	content := generatePac(fmt.Sprintf("%s:%d", opt.Get().Connect.ProxyAddr, opt.Get().Connect.ProxyPort),
+		opt.Get().Connect.ClusterDomain, hosts, cidrs)
+	pacLock.Lock()
+	pacContent = content
+	pacLock.Unlock()
+	log.Debug().Msgf("Pac file updated with %d hosts and %d ip ranges", len(hosts), len(cidrs))
+}
+
+// generatePac create proxy auto-config script, requests to specified hosts, cluster domain
+// and ip ranges go to socks5 proxy, others go direct
+func generatePac(proxyAddr, clusterDomain string, hosts, cidrs []string) string {
+	sort.Strings(hosts)
+	var sb strings.Builder
+	sb.WriteString("function FindProxyForURL(url, host) {\n")
+	sb.WriteString(fmt.Sprintf("  var proxy = \"SOCKS5 %s; SOCKS %s\";\n", proxyAddr, proxyAddr))
+	sb.WriteString(fmt.Sprintf("  if (dnsDomainIs(host, \".svc.%s\")) return proxy;\n", clusterDomain))
+	for _, h := range hosts {
+		sb.WriteString(fmt.Sprintf("  if (host == \"%s\") return proxy;\n", h))
+	}
+	sb.WriteString("  if (/^[0-9.]+$/.test(host)) {\n")
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil || ipNet.IP.To4() == nil {
+			// single ip or ipv6 range is not supported by isInNet()
+			if ip := net.ParseIP(strings.TrimSpace(cidr)); ip != nil && ip.To4() != nil {
+				sb.WriteString(fmt.Sprintf("    if (host == \"%s\") return proxy;\n", ip.String()))
+			}
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("    if (isInNet(host, \"%s\", \"%s\")) return proxy;\n",
+			ipNet.IP.String(), net.IP(ipNet.Mask).String()))
+	}
+	sb.WriteString("  }\n")
+	sb.WriteString("  return \"DIRECT\";\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,21 @@
+package connect
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_generatePac(t *testing.T) {
+	pac := generatePac("127.0.0.1:2223", "cluster.local", []string{"tomcat.default", "tomcat"},
+		[]string{"10.96.0.0/16", "172.16.0.1", "fd00::/108", "bad"})
+	require.True(t, strings.HasPrefix(pac, "function FindProxyForURL(url, host) {"))
+	require.Contains(t, pac, `var proxy = "SOCKS5 127.0.0.1:2223; SOCKS 127.0.0.1:2223";`)
+	require.Contains(t, pac, `dnsDomainIs(host, ".svc.cluster.local")`)
+	require.Less(t, strings.Index(pac, `host == "tomcat"`), strings.Index(pac, `host == "tomcat.default"`))
+	require.Contains(t, pac, `isInNet(host, "10.96.0.0", "255.255.0.0")`)
+	require.Contains(t, pac, `host == "172.16.0.1"`)
+	require.NotContains(t, pac, "fd00")
+	require.NotContains(t, pac, "bad")
+	require.True(t, strings.HasSuffix(pac, "  return \"DIRECT\";\n}\n"))
+}
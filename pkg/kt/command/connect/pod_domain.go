@@ -0,0 +1,121 @@
+package connect
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/service/dns"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	labelApi "k8s.io/apimachinery/pkg/labels"
+)
+
+// podDomainWatcher keep domains of pods selected by services in a namespace, in '<pod>.<service>.<namespace>' format
+type podDomainWatcher struct {
+	namespace string
+	lock      sync.Mutex
+	services  map[string]coreV1.Service
+	podIps    map[string]string
+	podLabels map[string]map[string]string
+}
+
+// setupPodDomains resolve domain of each pod behind services in namespace to ip of the pod,
+// and keep them in sync with pods and services
+func setupPodDomains(namespace string) error {
+	w := &podDomainWatcher{
+		namespace: namespace,
+		services:  map[string]coreV1.Service{},
+		podIps:    map[string]string{},
+		podLabels: map[string]map[string]string{},
+	}
+	services, err := cluster.Ins().GetAllServiceInNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	for _, svc := range services.Items {
+		w.services[svc.Name] = svc
+	}
+	pods, err := cluster.Ins().GetPodsByLabel(map[string]string{}, namespace)
+	if err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		w.setPod(&pods.Items[i])
+	}
+	w.publish()
+	log.Info().Msgf("Pods of services in namespace %s can be accessed by '<pod>.<service>.%s'", namespace, namespace)
+
+	setupTime := time.Now().Unix()
+	go cluster.Ins().WatchService("", namespace, func(svc *coreV1.Service) {
+		// ignore add service event during watch setup
+		if time.Now().Unix()-setupTime > 3 {
+			w.update(func() { w.services[svc.Name] = *svc })
+		}
+	}, func(svc *coreV1.Service) {
+		w.update(func() { delete(w.services, svc.Name) })
+	}, func(svc *coreV1.Service) {
+		w.update(func() { w.services[svc.Name] = *svc })
+	})
+	go cluster.Ins().WatchPod("", namespace, func(pod *coreV1.Pod) {
+		w.update(func() { w.setPod(pod) })
+	}, func(pod *coreV1.Pod) {
+		w.update(func() { w.removePod(pod.Name) })
+	}, func(pod *coreV1.Pod) {
+		w.update(func() { w.setPod(pod) })
+	})
+	return nil
+}
+
+func (w *podDomainWatcher) update(change func()) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	change()
+	w.publish()
+}
+
+func (w *podDomainWatcher) setPod(pod *coreV1.Pod) {
+	if pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+		w.removePod(pod.Name)
+		return
+	}
+	w.podIps[pod.Name] = pod.Status.PodIP
+	w.podLabels[pod.Name] = pod.Labels
+}
+
+func (w *podDomainWatcher) removePod(name string) {
+	delete(w.podIps, name)
+	delete(w.podLabels, name)
+}
+
+// publish let local dns resolve domains of current pods
+func (w *podDomainWatcher) publish() {
+	dns.SetPodDomains(w.namespace, getPodDomains(w.namespace, w.services, w.podIps, w.podLabels))
+}
+
+// getPodDomains generate short and full domains of each pod of each service, domain to pod ip
+func getPodDomains(namespace string, services map[string]coreV1.Service, podIps map[string]string,
+	podLabels map[string]map[string]string) map[string]string {
+	domains := make(map[string]string)
+	for _, svc := range services {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labelApi.SelectorFromSet(svc.Spec.Selector)
+		for podName, ip := range podIps {
+			if !selector.Matches(labelApi.Set(podLabels[podName])) {
+				continue
+			}
+			host := strings.ToLower(fmt.Sprintf("%s.%s", podName, svc.Name))
+			if namespace == opt.Get().Global.Namespace {
+				domains[host+"."] = ip
+			}
+			domains[fmt.Sprintf("%s.%s.", host, namespace)] = ip
+			domains[fmt.Sprintf("%s.%s.svc.%s.", host, namespace, opt.Get().Connect.ClusterDomain)] = ip
+		}
+	}
+	return domains
+}
@@ -0,0 +1,34 @@
+package connect
+
+import (
+	"testing"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getPodDomains(t *testing.T) {
+	opt.Get().Global.Namespace = "default"
+	opt.Get().Connect.ClusterDomain = "cluster.local"
+	services := map[string]coreV1.Service{
+		"tomcat": {ObjectMeta: metav1.ObjectMeta{Name: "tomcat"},
+			Spec: coreV1.ServiceSpec{Selector: map[string]string{"app": "tomcat"}}},
+		"external": {ObjectMeta: metav1.ObjectMeta{Name: "external"}},
+	}
+	podIps := map[string]string{"tomcat-7d8f-x2kzn": "10.1.0.5", "nginx-5c9d-abcde": "10.1.0.6"}
+	podLabels := map[string]map[string]string{
+		"tomcat-7d8f-x2kzn": {"app": "tomcat", "version": "v1"},
+		"nginx-5c9d-abcde":  {"app": "nginx"},
+	}
+	require.Equal(t, map[string]string{
+		"tomcat-7d8f-x2kzn.tomcat.":                           "10.1.0.5",
+		"tomcat-7d8f-x2kzn.tomcat.default.":                   "10.1.0.5",
+		"tomcat-7d8f-x2kzn.tomcat.default.svc.cluster.local.": "10.1.0.5",
+	}, getPodDomains("default", services, podIps, podLabels))
+	require.Equal(t, map[string]string{
+		"tomcat-7d8f-x2kzn.tomcat.dev.":                   "10.1.0.5",
+		"tomcat-7d8f-x2kzn.tomcat.dev.svc.cluster.local.": "10.1.0.5",
+	}, getPodDomains("dev", services, podIps, podLabels))
+}
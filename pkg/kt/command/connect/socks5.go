@@ -0,0 +1,34 @@
+package connect
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/common"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/transmission"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+)
+
+// BySocks5 only setup a local socks5 proxy, neither tun device nor local dns is touched
+func BySocks5() error {
+	podIP, podName, privateKeyPath, err := getOrCreateShadow()
+	if err != nil {
+		return err
+	}
+
+	localSshPort := util.GetRandomTcpPort()
+	if _, err = transmission.SetupPortForwardToLocal(podName, common.StandardSshPort, localSshPort); err != nil {
+		return err
+	}
+	tunnelPodName, tunnelSshPort = podName, localSshPort
+	if err = startSocks5Connection(podIP, privateKeyPath, localSshPort, true); err != nil {
+		return err
+	}
+	if opt.Get().Connect.PacPort > 0 {
+		if err = setupPacServer(); err != nil {
+			return err
+		}
+	}
+	// use socks5h scheme to let domain names resolved by cluster side
+	showSetupSocksMessage(fmt.Sprintf("socks5h://%s:%d", opt.Get().Connect.ProxyAddr, opt.Get().Connect.ProxyPort))
+	return nil
+}
@@ -25,6 +25,7 @@ func BySshuttle() error {
 	if _, err = transmission.SetupPortForwardToLocal(podName, common.StandardSshPort, localSshPort); err != nil {
 		return err
 	}
+	tunnelPodName, tunnelSshPort = podName, localSshPort
 
 	req := &sshuttle.SSHVPNRequest{
 		LocalSshPort:           localSshPort,
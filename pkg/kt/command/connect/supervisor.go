@@ -0,0 +1,87 @@
+package connect
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// tunnelPodName name of shadow pod the tunnel connected to
+var tunnelPodName string
+
+// tunnelSshPort local port forwarded to ssh port of shadow pod
+var tunnelSshPort int
+
+const supervisorInterval = 10 * time.Second
+
+// maxTunnelFailures consecutive failed checks before the connection is re-established
+const maxTunnelFailures = 3
+
+// SuperviseConnection check tunnel to shadow pod periodically, re-establish the connection when it keeps failing,
+// stop supervising when the failure is permanent
+func SuperviseConnection() {
+	failures := 0
+	for {
+		time.Sleep(supervisorInterval)
+		cause, permanent := checkTunnel()
+		if cause == "" {
+			failures = 0
+			continue
+		}
+		if permanent {
+			log.Error().Msgf("Tunnel check failed permanently (%s), stop reconnecting", cause)
+			return
+		}
+		failures++
+		log.Debug().Msgf("Tunnel check failed (%d/%d): %s", failures, maxTunnelFailures, cause)
+		if failures >= maxTunnelFailures {
+			general.Reconnect(cause)
+			return
+		}
+	}
+}
+
+// checkTunnel return cause of failure, or empty string if tunnel is healthy
+func checkTunnel() (string, bool) {
+	pod, err := cluster.Ins().GetPod(tunnelPodName, opt.Get().Global.Namespace)
+	if err != nil {
+		if k8sErrors.IsUnauthorized(err) || k8sErrors.IsForbidden(err) {
+			return err.Error(), true
+		}
+		if k8sErrors.IsNotFound(err) {
+			return fmt.Sprintf("shadow pod %s is gone", tunnelPodName), false
+		}
+		return fmt.Sprintf("failed to get shadow pod %s: %s", tunnelPodName, err), false
+	}
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != coreV1.PodRunning {
+		return fmt.Sprintf("shadow pod %s is not running", tunnelPodName), false
+	}
+	return checkSshBanner(fmt.Sprintf("127.0.0.1:%d", tunnelSshPort), 5*time.Second), false
+}
+
+// checkSshBanner ssh server at the other side of port forward should greet first
+func checkSshBanner(address string, timeout time.Duration) string {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return fmt.Sprintf("port forward of shadow pod unavailable: %s", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	banner, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Sprintf("no response from ssh server of shadow pod: %s", err)
+	}
+	if !strings.HasPrefix(banner, "SSH-") {
+		return "unexpected response from ssh server of shadow pod"
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package connect
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSshBanner(t *testing.T) {
+	cases := []struct {
+		greeting string
+		healthy  bool
+	}{
+		{"SSH-2.0-OpenSSH_8.8\r\n", true},
+		{"HTTP/1.1 400 Bad Request\r\n", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		go func(greeting string) {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte(greeting))
+			_ = conn.Close()
+		}(c.greeting)
+		cause := checkSshBanner(listener.Addr().String(), time.Second)
+		require.Equal(t, c.healthy, cause == "", "greeting %q: %s", c.greeting, cause)
+		_ = listener.Close()
+	}
+}
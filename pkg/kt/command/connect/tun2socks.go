@@ -29,9 +29,15 @@ func ByTun2Socks() error {
 	if _, err = transmission.SetupPortForwardToLocal(podName, common.StandardSshPort, localSshPort); err != nil {
 		return err
 	}
+	tunnelPodName, tunnelSshPort = podName, localSshPort
 	if err = startSocks5Connection(podIP, privateKeyPath, localSshPort, true); err != nil {
 		return err
 	}
+	if opt.Get().Connect.PacPort > 0 {
+		if err = setupPacServer(); err != nil {
+			return err
+		}
+	}
 
 	if opt.Get().Connect.DisableTunDevice {
 		if util.IsWindows() {
@@ -83,15 +89,29 @@ func setupTunRoute() error {
 	return nil
 }
 
+// socksReconnector track reconnect attempts of socks proxy since first connect
+var socksReconnector *sshchannel.Reconnector
+
 func startSocks5Connection(podIP, privateKey string, localSshPort int, isInitConnect bool) error {
 	var res = make(chan error)
 	var ticker *time.Ticker
 	sshAddress := fmt.Sprintf("%s:%d", common.LocalhostIp6, localSshPort)
 	socks5Address := fmt.Sprintf("%s:%d", opt.Get().Connect.ProxyAddr, opt.Get().Connect.ProxyPort)
+	httpProxyAddress := ""
+	if opt.Get().Connect.HttpProxyPort > 0 {
+		httpProxyAddress = fmt.Sprintf("%s:%d", opt.Get().Connect.ProxyAddr, opt.Get().Connect.HttpProxyPort)
+	}
 	gone := false
+	if socksReconnector == nil {
+		socksReconnector = sshchannel.NewReconnector("Socks proxy")
+	}
 	go func() {
+		defer util.RecoverPanic("socks proxy", func() {
+			_ = startSocks5Connection(podIP, privateKey, localSshPort, false)
+		})
 		// will hang here if not error happen
-		err := sshchannel.Ins().StartSocks5Proxy(privateKey, sshAddress, socks5Address)
+		connectedAt := time.Now()
+		err := sshchannel.Ins().StartSocks5Proxy(privateKey, sshAddress, socks5Address, httpProxyAddress)
 		if !gone {
 			res <-err
 		}
@@ -99,8 +119,10 @@ func startSocks5Connection(podIP, privateKey string, localSshPort int, isInitCon
 		if ticker != nil {
 			ticker.Stop()
 		}
-		time.Sleep(10 * time.Second)
-		log.Debug().Msgf("Socks proxy reconnecting ...")
+		if !socksReconnector.Wait(err, connectedAt) {
+			return
+		}
+		log.Info().Msgf("Socks proxy reconnecting ...")
 		_ = startSocks5Connection(podIP, privateKey, localSshPort, false)
 	}()
 	select {
@@ -112,6 +134,9 @@ func startSocks5Connection(podIP, privateKey string, localSshPort int, isInitCon
 	case <-time.After(1 * time.Second):
 		ticker = setupSocks5HeartBeat(podIP, socks5Address)
 		log.Info().Msgf("Socks proxy established")
+		if isInitConnect && httpProxyAddress != "" {
+			log.Info().Msgf("Http proxy established, set HTTPS_PROXY and HTTP_PROXY to http://%s", httpProxyAddress)
+		}
 		gone = true
 		return nil
 	}
@@ -1,16 +1,17 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"time"
 
 	"strings"
 
 	"github.com/alibaba/kt-connect/pkg/kt/command/exchange"
 	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	"github.com/alibaba/kt-connect/pkg/kt/command/mesh"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -22,17 +23,31 @@ func NewExchangeCommand() *cobra.Command {
 		Use:   "exchange",
 		Short: "Redirect all requests of specified kubernetes service to local",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) == 0 {
-				return fmt.Errorf("name of service to exchange is required")
-			} else if len(args) > 1 {
-				return fmt.Errorf("too many service names are spcified (%s), should be one", strings.Join(args, ","))
+			if len(args) == 0 && opt.Get().Exchange.Selector == "" {
+				return fmt.Errorf("name of service to exchange is required, or use '--selector' to select services by label")
+			} else if len(args) > 0 && opt.Get().Exchange.Selector != "" {
+				return fmt.Errorf("service names and '--selector' cannot be specified together")
 			}
-			return general.Prepare()
+			if err := general.Prepare(); err != nil {
+				return err
+			}
+			return general.LoadServiceProfile(cmd, args)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return Exchange(args[0])
+			if opt.Get().Exchange.Selector != "" {
+				var err error
+				if args, err = exchange.ResolveSelectedServices(); err != nil {
+					return err
+				}
+			}
+			if opt.Get().Global.ValidateOnly {
+				return validateExchange(args)
+			}
+			return Exchange(args)
 		},
-		Example: "ktctl exchange <service-name> [command options]",
+		Example: "ktctl exchange <service-name> [command options]\n" +
+			"ktctl exchange <service-name> <service-name> [<service-name> ...] [command options]  (selector mode only)\n" +
+			"ktctl exchange --selector <label-selector> [command options]",
 	}
 
 	cmd.SetUsageTemplate(general.UsageTemplate(true))
@@ -41,83 +56,224 @@ func NewExchangeCommand() *cobra.Command {
 }
 
 //Exchange exchange kubernetes workload
-func Exchange(resourceName string) error {
-	ch, err := general.SetupProcess(util.ComponentExchange)
+func Exchange(resourceNames []string) error {
+	ctx, ch, err := general.SetupProcess(util.ComponentExchange)
 	if err != nil {
 		return err
 	}
 
-	if opt.Get().Exchange.SkipPortChecking {
-		if port := util.FindBrokenLocalPort(opt.Get().Exchange.Expose); port != "" {
-			return fmt.Errorf("no application is running on port %s", port)
+	// multiple services share one shadow pod and signal file
+	resourceName := strings.Join(resourceNames, ",")
+	if err = exchange.CheckTargetKinds(resourceNames); err != nil {
+		return err
+	}
+	if err = exchange.CheckMultipleTargets(resourceNames); err != nil {
+		return err
+	}
+	if err = exchange.ResolveExposeFrom(resourceName); err != nil {
+		return err
+	}
+	if err = exchange.ExtractPathRoutes(); err != nil {
+		return err
+	}
+
+	if err = general.CheckLocalAddr(opt.Get().Exchange.LocalAddr); err != nil {
+		return err
+	}
+	if !opt.Get().Exchange.SkipPortChecking {
+		if err = general.CheckLocalPorts(opt.Get().Exchange.Expose, opt.Get().Exchange.LocalAddr); err != nil {
+			return err
 		}
 	}
 
 	// Setup signal file watcher
-	signalFile := filepath.Join(os.TempDir(), fmt.Sprintf("ktctl-exchange-signal-%d", os.Getpid()))
-	go watchExchangeSignalFile(signalFile, ch)
+	signalFile := general.SignalFilePath(util.ComponentExchange, resourceName)
+	go general.WatchSignalFile(signalFile, resourceName, ch)
+	pipeName := general.WatchControlPipe(util.ComponentExchange, ch)
 
-	log.Info().Msgf("Using %s mode", opt.Get().Exchange.Mode)
-	if opt.Get().Exchange.Mode == util.ExchangeModeScale {
-		err = exchange.ByScale(resourceName)
-	} else if opt.Get().Exchange.Mode == util.ExchangeModeEphemeral {
-		err = exchange.ByEphemeralContainer(resourceName)
-	} else if opt.Get().Exchange.Mode == util.ExchangeModeSelector {
-		err = exchange.BySelector(resourceName)
-	} else {
-		err = fmt.Errorf("invalid exchange method '%s', supportted are %s, %s, %s", opt.Get().Exchange.Mode,
-			util.ExchangeModeSelector, util.ExchangeModeScale, util.ExchangeModeEphemeral)
+	err = checkExchangeOptions()
+	for _, name := range resourceNames {
+		if err == nil {
+			err = exchange.CheckTargetAnnotation(name)
+		}
+	}
+	if err == nil {
+		// must be the last step before any change applied to cluster
+		err = exchange.RequestApproval(resourceName)
 	}
+	if err != nil {
+		os.RemoveAll(signalFile)
+		return err
+	}
+	if opt.Get().Exchange.LocalRateLimit > 0 {
+		sshchannel.SetupLocalRateLimit(opt.Get().Exchange.LocalRateLimit,
+			opt.Get().Exchange.OverloadAction == util.OverloadActionShed)
+	}
+	if exchange.LocalReadinessEnabled() {
+		exchange.SetupLocalReadiness()
+	}
+	exchange.SetupPathRoutes()
+	if opt.Get().Exchange.TlsTerminate {
+		_, realName := toTypeAndName(resourceName)
+		if err = general.SetupTlsTermination(realName, opt.Get().Exchange.TlsCert, opt.Get().Exchange.TlsKey); err != nil {
+			os.RemoveAll(signalFile)
+			return err
+		}
+	}
+
+	// Redirected mesh routes must be restored before shadow pod and service get cleaned up
+	general.RegisterCleanupHook(mesh.RestoreRoutes)
+	if opt.Get().Exchange.AuditFile != "" {
+		general.SetRouteRestoreCheck(mesh.UnrestoredRoutes)
+	}
+
+	log.Info().Msgf("Using %s mode", opt.Get().Exchange.Mode)
+	var result *general.SetupResult
+	err = general.RunSetupWithTimeout(ctx, util.ComponentExchange, func(ctx context.Context) error {
+		return general.RetrySetup(ctx, opt.Get().Exchange.SetupRetries, func() error {
+			var setupErr error
+			result, setupErr = exchangeByMode(resourceNames)
+			return setupErr
+		}, mesh.RestoreRoutes)
+	})
 	if err != nil {
 		// Clean up signal file
 		os.RemoveAll(signalFile)
 		return err
 	}
-	resourceType, realName := toTypeAndName(resourceName)
+	if opt.Get().Global.DryRun {
+		os.RemoveAll(signalFile)
+		general.PrintDryRunSummary(result)
+		return nil
+	}
 	log.Info().Msg("---------------------------------------------------------------")
-	log.Info().Msgf(" Now all request to %s '%s' will be redirected to local", resourceType, realName)
+	for _, name := range resourceNames {
+		resourceType, realName := toTypeAndName(name)
+		log.Info().Msgf(" Now all request to %s '%s' will be redirected to local", resourceType, realName)
+	}
 	log.Info().Msg("---------------------------------------------------------------")
-
-	if util.IsWindows() {
-		log.Info().Msgf("You can stop the exchange by creating a signal file:")
-		log.Info().Msgf("PowerShell:   \"stop\" | Out-File -FilePath %s -Encoding ASCII", signalFile)
-		log.Info().Msgf("Command Prompt: echo stop > %s", signalFile)
-	} else {
-		log.Info().Msgf("You can stop the exchange by creating a signal file: echo stop > %s", signalFile)
+	if opt.Get().Exchange.ExecProbe {
+		if err = exchange.Probe(resourceName); err != nil {
+			log.Error().Msgf("Probe failed, %s", err.Error())
+		}
 	}
 
+	exchange.SetupPause()
+	general.MarkReady(result, signalFile, pipeName)
+	general.PrintSetupResult(result, signalFile, pipeName)
+	general.PrintStopHint("exchange", signalFile, pipeName)
+
 	// watch background process, clean the workspace and exit if background process occur exception
-	s := <-ch
-	log.Info().Msgf("Terminal Signal is %s", s)
+	general.WaitForStop(ctx, ch)
+	general.WaitConnectionsDrained(opt.Get().Exchange.DrainTimeout)
 
 	// Clean up signal file
 	os.RemoveAll(signalFile)
 	return nil
 }
 
-func toTypeAndName(name string) (string, string) {
-	parts := strings.Split(name, "/")
-	if len(parts) > 1 {
-		return parts[0], parts[1]
-	} else {
-		return "service", parts[0]
+func exchangeByMode(resourceNames []string) (*general.SetupResult, error) {
+	resourceName := resourceNames[0]
+	if len(resourceNames) > 1 {
+		// only selector mode is allowed, already checked
+		return exchange.ByMultipleSelector(resourceNames)
+	} else if opt.Get().Exchange.Mode == util.ExchangeModeScale {
+		return exchange.ByScale(resourceName)
+	} else if opt.Get().Exchange.Mode == util.ExchangeModeEphemeral {
+		return exchange.ByEphemeralContainer(resourceName)
+	} else if resourceType, _ := toTypeAndName(resourceName); resourceType == general.KindPod &&
+		opt.Get().Exchange.Mode == util.ExchangeModeSelector {
+		return exchange.ByPodEndpoint(resourceName)
+	} else if opt.Get().Exchange.Mode == util.ExchangeModeSelector && opt.Get().Exchange.NoShadow {
+		return exchange.ByDirectEndpoint(resourceName)
+	} else if opt.Get().Exchange.Mode == util.ExchangeModeSelector {
+		return exchange.BySelector(resourceName)
 	}
+	return nil, fmt.Errorf("invalid exchange method '%s', supportted are %s, %s, %s", opt.Get().Exchange.Mode,
+		util.ExchangeModeSelector, util.ExchangeModeScale, util.ExchangeModeEphemeral)
 }
 
-func watchExchangeSignalFile(signalFile string, ch chan os.Signal) {
-	// Create the signal file to indicate exchange is ready
-	os.Create(signalFile)
+func validateExchange(resourceNames []string) error {
+	resourceName := strings.Join(resourceNames, ",")
+	return general.RunChecks([]general.Check{
+		{Name: "Exchange options", Run: func() error {
+			if err := exchange.CheckTargetKinds(resourceNames); err != nil {
+				return err
+			}
+			if err := exchange.CheckMultipleTargets(resourceNames); err != nil {
+				return err
+			}
+			if err := exchange.ResolveExposeFrom(resourceName); err != nil {
+				return err
+			}
+			if err := exchange.ExtractPathRoutes(); err != nil {
+				return err
+			}
+			if !util.Contains([]string{util.ExchangeModeSelector, util.ExchangeModeScale, util.ExchangeModeEphemeral},
+				opt.Get().Exchange.Mode) {
+				return fmt.Errorf("invalid exchange method '%s', supportted are %s, %s, %s", opt.Get().Exchange.Mode,
+					util.ExchangeModeSelector, util.ExchangeModeScale, util.ExchangeModeEphemeral)
+			}
+			if err := checkExchangeOptions(); err != nil {
+				return err
+			}
+			if err := general.CheckLocalAddr(opt.Get().Exchange.LocalAddr); err != nil {
+				return err
+			}
+			return general.CheckExposePorts(opt.Get().Exchange.Expose)
+		}},
+		{Name: "Local ports", Run: func() error {
+			if opt.Get().Exchange.SkipPortChecking {
+				return nil
+			}
+			return general.CheckLocalPorts(opt.Get().Exchange.Expose, opt.Get().Exchange.LocalAddr)
+		}},
+		{Name: "Target resource", Run: func() error {
+			if err := exchange.CheckTargets(resourceNames); err != nil {
+				return err
+			}
+			for _, name := range resourceNames {
+				if err := exchange.CheckTargetAnnotation(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{Name: "Permissions", Run: func() error {
+			return general.CheckPermissions(exchange.Permissions(resourceNames))
+		}},
+	})
+}
 
-	for {
-		time.Sleep(1 * time.Second)
+// exchangeOptionChecks checks of exchange options, shared by exchange and its validation
+var exchangeOptionChecks = []func() error{
+	exchange.CheckRateLimit,
+	exchange.CheckNoShadow,
+	exchange.CheckPassthrough,
+	exchange.CheckLocalReadiness,
+	exchange.CheckTlsTerminate,
+	exchange.CheckSharedShadow,
+	exchange.CheckRequireAnnotation,
+	exchange.CheckRamp,
+	exchange.CheckPathRoutes,
+	exchange.CheckApproval,
+}
 
-		// Check if signal file contains "stop"
-		if content, err := os.ReadFile(signalFile); err == nil {
-			if strings.TrimSpace(string(content)) == "stop" {
-				// Send interrupt signal to the main routine
-				ch <- os.Interrupt
-				return
-			}
+// checkExchangeOptions run exchange option checks in order, stop at the first failure
+func checkExchangeOptions() error {
+	for _, check := range exchangeOptionChecks {
+		if err := check(); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// toTypeAndName kind and name of target, kind is 'service' if not specified
+func toTypeAndName(name string) (string, string) {
+	resourceType, realName, err := general.ParseResourceName(name)
+	if err != nil {
+		return "", name
+	}
+	return resourceType, realName
 }
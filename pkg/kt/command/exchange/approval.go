@@ -0,0 +1,90 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ApprovalRequest operation details posted to approval webhook
+type ApprovalRequest struct {
+	Operation   string    `json:"operation"`
+	User        string    `json:"user"`
+	KubeContext string    `json:"kubeContext,omitempty"`
+	Namespace   string    `json:"namespace"`
+	Target      string    `json:"target"`
+	Mode        string    `json:"mode"`
+	Expose      string    `json:"expose"`
+	RequestedAt time.Time `json:"requestedAt"`
+}
+
+// ApprovalResponse decision returned by approval webhook
+type ApprovalResponse struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason"`
+}
+
+// RequestApproval post exchange details to approval webhook, and wait for its decision,
+// return error unless the exchange is approved
+func RequestApproval(resourceName string) error {
+	ex := opt.Get().Exchange
+	if ex.ApprovalWebhook == "" {
+		return nil
+	}
+	if ex.SkipApproval {
+		log.Warn().Msgf("Approval of exchanging '%s' is SKIPPED by user '%s' with --skipApproval",
+			resourceName, util.GetLocalUserName())
+		return nil
+	}
+	if opt.Get().Global.DryRun {
+		log.Info().Msgf("Dry run, approval request skipped")
+		return nil
+	}
+	body, err := json.Marshal(&ApprovalRequest{
+		Operation:   util.ComponentExchange,
+		User:        util.GetLocalUserName(),
+		KubeContext: opt.Get().Global.Context,
+		Namespace:   opt.Get().Global.Namespace,
+		Target:      resourceName,
+		Mode:        ex.Mode,
+		Expose:      ex.Expose,
+		RequestedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Waiting for approval of exchanging '%s' (timeout %ds) ...", resourceName, ex.ApprovalTimeout)
+	client := &http.Client{Timeout: time.Duration(ex.ApprovalTimeout) * time.Second}
+	resp, err := client.Post(ex.ApprovalWebhook, "application/json", bytes.NewReader(body))
+	if uErr, ok := err.(*url.Error); ok {
+		// error of url package contains the url, which may carry credential
+		err = uErr.Err
+	}
+	if err != nil {
+		return fmt.Errorf("exchange is not approved, failed to get decision from approval webhook: %s", err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("exchange is not approved, failed to read response of approval webhook: %s", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("exchange is not approved, approval webhook returned status %d", resp.StatusCode)
+	}
+	decision := &ApprovalResponse{}
+	if err = json.Unmarshal(content, decision); err != nil {
+		return fmt.Errorf("exchange is not approved, invalid response of approval webhook: %s", err)
+	}
+	if !decision.Approved {
+		return fmt.Errorf("exchange is denied: %s", decision.Reason)
+	}
+	log.Info().Msgf("Exchange approved %s", decision.Reason)
+	return nil
+}
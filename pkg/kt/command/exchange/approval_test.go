@@ -0,0 +1,37 @@
+package exchange
+
+import (
+	"encoding/json"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestApproval(t *testing.T) {
+	response := `{"approved": false, "reason": "not in release window"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &ApprovalRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		require.Equal(t, "exchange", req.Operation)
+		require.Equal(t, "tomcat", req.Target)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	opt.Get().Exchange.ApprovalWebhook = server.URL
+	opt.Get().Exchange.ApprovalTimeout = 5
+	defer func() {
+		opt.Get().Exchange.ApprovalWebhook = ""
+	}()
+	err := RequestApproval("tomcat")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not in release window")
+
+	response = `{"approved": true}`
+	require.NoError(t, RequestApproval("tomcat"))
+
+	response = `not json`
+	require.Error(t, RequestApproval("tomcat"))
+}
@@ -0,0 +1,113 @@
+package exchange
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	"strings"
+)
+
+// ByDirectEndpoint let endpoints of service point to local address, without creating shadow pod
+func ByDirectEndpoint(resourceName string) (*general.SetupResult, error) {
+	// Get service to exchange
+	svc, err := general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if port := util.FindInvalidRemotePort(opt.Get().Exchange.Expose, general.GetTargetPorts(svc)); port != "" {
+		return nil, fmt.Errorf("target port %s not exists in service %s", port, svc.Name)
+	}
+	subsets, err := getLocalEndpointSubsets(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock service to avoid conflict, must be first step
+	svc, err = general.LockService(svc.Name, opt.Get().Global.Namespace, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer general.UnlockService(svc.Name, opt.Get().Global.Namespace)
+
+	if err = checkServiceNotOccupied(svc); err != nil {
+		return nil, err
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service '%s' has no selector, cannot exchange without shadow pod", svc.Name)
+	}
+
+	if opt.Get().Exchange.SkipReachableCheck || opt.Get().Global.DryRun {
+		log.Info().Msgf("Skipped checking whether local address is reachable from cluster")
+	} else if err = checkLocalReachable(subsets[0]); err != nil {
+		return nil, err
+	}
+
+	// Remove selector of target service, so that its endpoints are no longer managed by cluster
+	opt.Store.Origin = svc.Name
+	if err = general.UpdateServiceSelector(svc.Name, opt.Get().Global.Namespace, nil); err != nil {
+		return nil, err
+	}
+	if err = cluster.Ins().SetServiceEndpoints(svc.Name, opt.Get().Global.Namespace, subsets); err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Endpoints of service %s point to %s directly", svc.Name, subsets[0].Addresses[0].IP)
+	return general.NewSetupResult([]string{svc.Name}, util.ExchangeModeSelector, opt.Get().Exchange.Expose), nil
+}
+
+// getLocalEndpointSubsets generate endpoint subsets pointing to local ports
+func getLocalEndpointSubsets(svc *coreV1.Service) ([]coreV1.EndpointSubset, error) {
+	localIp := opt.Get().Exchange.LocalIp
+	if localIp == "" {
+		var err error
+		if localIp, err = util.GetOutboundIp(util.ExtractHostIp(opt.Store.RestConfig.Host)); err != nil {
+			return nil, fmt.Errorf("failed to detect local ip, please specify it with --localIp: %s", err)
+		}
+		log.Info().Msgf("Using local ip %s", localIp)
+	}
+	targetPorts := general.GetTargetPorts(svc)
+	var ports []coreV1.EndpointPort
+	for _, exposePort := range strings.Split(opt.Get().Exchange.Expose, ",") {
+		localPort, remotePort, err := util.ParsePortMapping(exposePort)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range svc.Spec.Ports {
+			if p.TargetPort.IntValue() == remotePort || targetPorts[remotePort] == p.TargetPort.String() {
+				ports = append(ports, coreV1.EndpointPort{Name: p.Name, Port: int32(localPort), Protocol: p.Protocol})
+			}
+		}
+	}
+	return []coreV1.EndpointSubset{{
+		Addresses: []coreV1.EndpointAddress{{IP: localIp}},
+		Ports:     ports,
+	}}, nil
+}
+
+// checkLocalReachable connect local ports from a temporary pod in cluster
+func checkLocalReachable(subset coreV1.EndpointSubset) error {
+	ip := subset.Addresses[0].IP
+	log.Info().Msgf("Checking whether %s is reachable from cluster", ip)
+	checkerPodName := fmt.Sprintf("%s%s", util.RectifierPodPrefix, strings.ToLower(util.RandomString(5)))
+	if _, err := cluster.Ins().CreateRectifierPod(checkerPodName); err != nil {
+		return err
+	}
+	defer func() {
+		if err := cluster.Ins().RemovePod(checkerPodName, opt.Get().Global.Namespace); err != nil {
+			log.Debug().Err(err).Msgf("Failed to remove pod %s", checkerPodName)
+		}
+	}()
+	for _, p := range subset.Ports {
+		_, stderr, err := cluster.Ins().ExecInPod(util.DefaultContainer, checkerPodName, opt.Get().Global.Namespace,
+			"timeout", "3", "bash", "-c", fmt.Sprintf("</dev/tcp/%s/%d", ip, p.Port))
+		if err != nil {
+			return fmt.Errorf("local address %s:%d is unreachable from cluster, use --skipReachableCheck to force "+
+				"exchange without shadow: %s", ip, p.Port, strings.TrimSpace(err.Error()+" "+stderr))
+		}
+	}
+	log.Info().Msgf("Local address %s is reachable from cluster", ip)
+	return nil
+}
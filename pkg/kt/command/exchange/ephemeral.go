@@ -15,10 +15,13 @@ import (
 	"time"
 )
 
-func ByEphemeralContainer(resourceName string) error {
+func ByEphemeralContainer(resourceName string) (*general.SetupResult, error) {
 	log.Warn().Msgf("Experimental feature. It just works on kubernetes above v1.23, and it can NOT work with istio.")
 
 	pods, err := getPodsOfResource(resourceName, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, pod := range pods {
 		if pod.Status.Phase != coreV1.PodRunning {
@@ -27,22 +30,28 @@ func ByEphemeralContainer(resourceName string) error {
 		}
 		privateKey, err2 := createEphemeralContainer(util.KtExchangeContainer, pod.Name)
 		if err2 != nil {
-			return err2
+			return nil, err2
 		}
 
 		// record data
 		opt.Store.Shadow = util.Append(opt.Store.Shadow, pod.Name)
+		if opt.Get().Global.DryRun {
+			// no container is added, so nothing to forward
+			continue
+		}
 
-		localSSHPort, err2 := transmission.ForwardPodToLocal(opt.Get().Exchange.Expose, pod.Name, privateKey)
+		localSSHPort, err2 := transmission.ForwardPodToLocal(opt.Get().Exchange.Expose, pod.Name, privateKey,
+			opt.Get().Exchange.LocalAddr)
 		if err2 != nil {
-			return err2
+			return nil, err2
 		}
 		err = exchangeWithEphemeralContainer(opt.Get().Exchange.Expose, localSSHPort, privateKey)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	return nil
+	_, name, _ := general.ParseResourceName(resourceName)
+	return general.NewSetupResult([]string{name}, util.ExchangeModeEphemeral, opt.Get().Exchange.Expose), nil
 }
 
 
@@ -53,23 +62,43 @@ func getPodsOfResource(resourceName, namespace string) ([]coreV1.Pod, error) {
 	}
 
 	switch resourceType {
-	case "pod":
-		pod, err := cluster.Ins().GetPod(name, namespace)
+	case general.KindPod:
+		pod, err := getExchangeablePod(name, namespace)
 		if err != nil {
 			return nil, err
-		} else {
-			return []coreV1.Pod{*pod}, nil
 		}
-	case "svc":
-		fallthrough
-	case "service":
+		return []coreV1.Pod{*pod}, nil
+	case general.KindService:
 		return getPodsOfService(name, namespace)
+	case general.KindDeployment, general.KindStatefulSet, general.KindDaemonSet:
+		workload, err := general.GetWorkload(resourceType, name, namespace)
+		if err != nil {
+			return nil, err
+		}
+		pods, err := cluster.Ins().GetPodsByLabel(workload.Selector, namespace)
+		if err != nil {
+			return nil, err
+		}
+		return pods.Items, nil
 	}
 	return nil, fmt.Errorf("invalid resource type: %s", resourceType)
 }
 
+func getExchangeablePod(podName, namespace string) (*coreV1.Pod, error) {
+	pod, svcs, err := getPodWithServices(podName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(pod.OwnerReferences) == 0 {
+		// exchanged pod will be deleted during cleanup, only pods managed by controller would be recreated
+		return nil, fmt.Errorf("pod '%s' is not managed by any controller, it cannot be restored after exchange", podName)
+	}
+	log.Info().Msgf("Only traffic to pod '%s' of service '%s' will be exchanged", podName, svcs[0].Name)
+	return pod, nil
+}
+
 func getPodsOfService(serviceName, namespace string) ([]coreV1.Pod, error) {
-	svc, err := cluster.Ins().GetService(serviceName, namespace)
+	svc, err := general.GetServiceWithRetry(serviceName, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -85,8 +114,8 @@ func createEphemeralContainer(containerName, podName string) (string, error) {
 
 	envs := make(map[string]string)
 	privateKey, err := cluster.Ins().AddEphemeralContainer(containerName, podName, envs)
-	if err != nil {
-		return "", err
+	if err != nil || opt.Get().Global.DryRun {
+		return privateKey, err
 	}
 
 	for i := 0; i < 10; i++ {
@@ -142,7 +171,8 @@ func exchangeWithEphemeralContainer(exposePorts string, localSSHPort int, privat
 		return err
 	}
 
-	if err = transmission.ForwardRemotePortsViaSshTunnel(exposePorts, localSSHPort, privateKey); err != nil {
+	if err = transmission.ForwardRemotePortsViaSshTunnel(exposePorts, localSSHPort, privateKey,
+		opt.Get().Exchange.LocalAddr); err != nil {
 		return err
 	}
 
@@ -0,0 +1,168 @@
+package exchange
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	appV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestSeparator separator line of documents in a yaml file
+var manifestSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// ResolveExposeFrom derive ports to expose from service or deployment manifest, each port mapped 1:1 to local
+func ResolveExposeFrom(resourceName string) error {
+	ex := opt.Get().Exchange
+	if ex.ExposeFrom == "" {
+		if ex.Expose == "" && ex.AutoExpose {
+			port, err := general.DetectExposePort()
+			if err != nil {
+				return err
+			}
+			ex.Expose = port
+		}
+		if ex.Expose == "" {
+			return fmt.Errorf("either --expose, --exposeFrom or --autoExpose is required")
+		}
+		return nil
+	}
+	if ex.Expose != "" {
+		return fmt.Errorf("--expose and --exposeFrom cannot be used together")
+	}
+	content, err := ioutil.ReadFile(ex.ExposeFrom)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest %s: %s", ex.ExposeFrom, err)
+	}
+	ports, namedPorts, err := parseManifestPorts(content)
+	if err != nil {
+		return fmt.Errorf("invalid manifest %s: %s", ex.ExposeFrom, err)
+	}
+	if ports, err = reconcileWithLiveService(resourceName, ports, namedPorts); err != nil {
+		return err
+	}
+	var exposePorts []string
+	for _, p := range ports {
+		exposePorts = append(exposePorts, strconv.Itoa(p))
+	}
+	ex.Expose = strings.Join(exposePorts, ",")
+	log.Info().Msgf("Exposing port %s derived from %s", ex.Expose, ex.ExposeFrom)
+	return nil
+}
+
+// parseManifestPorts get tcp target ports of first service, or container ports of first deployment in manifest,
+// named target ports of service are returned separately
+func parseManifestPorts(content []byte) ([]int, []string, error) {
+	for _, doc := range manifestSeparator.Split(string(content), -1) {
+		var meta metav1.TypeMeta
+		if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+			return nil, nil, err
+		}
+		switch meta.Kind {
+		case "Service":
+			var svc coreV1.Service
+			if err := yaml.Unmarshal([]byte(doc), &svc); err != nil {
+				return nil, nil, err
+			}
+			return getServiceManifestPorts(&svc)
+		case "Deployment":
+			var app appV1.Deployment
+			if err := yaml.Unmarshal([]byte(doc), &app); err != nil {
+				return nil, nil, err
+			}
+			return getDeploymentManifestPorts(&app)
+		}
+	}
+	return nil, nil, fmt.Errorf("no Service or Deployment found")
+}
+
+func getServiceManifestPorts(svc *coreV1.Service) ([]int, []string, error) {
+	var ports []int
+	var namedPorts []string
+	for _, p := range svc.Spec.Ports {
+		if p.Protocol == coreV1.ProtocolUDP || p.Protocol == coreV1.ProtocolSCTP {
+			continue
+		}
+		switch {
+		case p.TargetPort.Type == intstr.String:
+			namedPorts = append(namedPorts, p.TargetPort.StrVal)
+		case p.TargetPort.IntValue() > 0:
+			ports = appendPort(ports, p.TargetPort.IntValue())
+		default:
+			// target port defaults to same as port
+			ports = appendPort(ports, int(p.Port))
+		}
+	}
+	if len(ports) == 0 && len(namedPorts) == 0 {
+		return nil, nil, fmt.Errorf("service '%s' has no tcp port", svc.Name)
+	}
+	return ports, namedPorts, nil
+}
+
+func getDeploymentManifestPorts(app *appV1.Deployment) ([]int, []string, error) {
+	var ports []int
+	for _, c := range app.Spec.Template.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Protocol != coreV1.ProtocolUDP && p.Protocol != coreV1.ProtocolSCTP {
+				ports = appendPort(ports, int(p.ContainerPort))
+			}
+		}
+	}
+	if len(ports) == 0 {
+		return nil, nil, fmt.Errorf("deployment '%s' has no tcp container port", app.Name)
+	}
+	return ports, nil, nil
+}
+
+// reconcileWithLiveService resolve named ports with live service, and warn about ports not declared in manifest
+func reconcileWithLiveService(resourceName string, ports []int, namedPorts []string) ([]int, error) {
+	if opt.Get().Exchange.Mode != util.ExchangeModeSelector {
+		if len(namedPorts) > 0 {
+			return nil, fmt.Errorf("named target port %v can only be resolved in %s mode",
+				namedPorts, util.ExchangeModeSelector)
+		}
+		return ports, nil
+	}
+	svc, err := general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	livePorts := general.GetTargetPorts(svc)
+	for _, name := range namedPorts {
+		found := false
+		for p, n := range livePorts {
+			if n == name {
+				ports = appendPort(ports, p)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("named target port '%s' not found in service %s", name, svc.Name)
+		}
+	}
+	for p := range livePorts {
+		if !util.Contains(ports, p) {
+			log.Warn().Msgf("Target port %d of service %s is not declared in manifest, it will not be exchanged", p, svc.Name)
+		}
+	}
+	sort.Ints(ports)
+	return ports, nil
+}
+
+func appendPort(ports []int, port int) []int {
+	if util.Contains(ports, port) {
+		return ports
+	}
+	return append(ports, port)
+}
@@ -0,0 +1,58 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseManifestPorts(t *testing.T) {
+	ports, namedPorts, err := parseManifestPorts([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: tomcat-config
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: tomcat
+spec:
+  ports:
+  - port: 80
+    targetPort: 8080
+  - port: 8443
+  - port: 9090
+    targetPort: metrics
+  - port: 53
+    protocol: UDP
+`))
+	require.NoError(t, err)
+	require.Equal(t, []int{8080, 8443}, ports)
+	require.Equal(t, []string{"metrics"}, namedPorts)
+
+	ports, namedPorts, err = parseManifestPorts([]byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: tomcat
+spec:
+  template:
+    spec:
+      containers:
+      - name: tomcat
+        ports:
+        - containerPort: 8080
+        - containerPort: 8080
+        - containerPort: 9090
+      - name: sidecar
+        ports:
+        - containerPort: 15000
+`))
+	require.NoError(t, err)
+	require.Equal(t, []int{8080, 9090, 15000}, ports)
+	require.Empty(t, namedPorts)
+
+	_, _, err = parseManifestPorts([]byte("apiVersion: v1\nkind: ConfigMap\n"))
+	require.Error(t, err)
+	_, _, err = parseManifestPorts([]byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: empty\n"))
+	require.Error(t, err)
+}
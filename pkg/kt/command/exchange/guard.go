@@ -0,0 +1,91 @@
+package exchange
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+)
+
+// parseRequireAnnotation split value of '--requireAnnotation' into key and value, value is empty if any value accepted
+func parseRequireAnnotation(requireAnnotation string) (string, string, error) {
+	parts := strings.SplitN(requireAnnotation, "=", 2)
+	key := strings.TrimSpace(parts[0])
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid annotation key '%s': %s", key, strings.Join(errs, ", "))
+	}
+	if len(parts) == 1 {
+		return key, "", nil
+	}
+	return key, strings.TrimSpace(parts[1]), nil
+}
+
+// CheckRequireAnnotation verify option of required annotation
+func CheckRequireAnnotation() error {
+	if opt.Get().Exchange.RequireAnnotation == "" {
+		return nil
+	}
+	_, _, err := parseRequireAnnotation(opt.Get().Exchange.RequireAnnotation)
+	return err
+}
+
+// CheckTargetAnnotation refuse to exchange target unless all services of it have the required annotation
+func CheckTargetAnnotation(resourceName string) error {
+	if opt.Get().Exchange.RequireAnnotation == "" {
+		return nil
+	}
+	key, value, err := parseRequireAnnotation(opt.Get().Exchange.RequireAnnotation)
+	if err != nil {
+		return err
+	}
+	svcs, err := getTargetServices(resourceName)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, svc := range svcs {
+		if !hasAnnotation(svc, key, value) {
+			return fmt.Errorf("service '%s' is not annotated with '%s', which is required to exchange",
+				svc.Name, opt.Get().Exchange.RequireAnnotation)
+		}
+		names = append(names, svc.Name)
+	}
+	log.Info().Msgf("Service %s eligible to exchange by annotation '%s'", strings.Join(names, ", "),
+		opt.Get().Exchange.RequireAnnotation)
+	return nil
+}
+
+// getTargetServices services whose traffic would be exchanged, for pod it is all services selecting the pod
+func getTargetServices(resourceName string) ([]coreV1.Service, error) {
+	resourceType, name, err := general.ParseResourceName(resourceName)
+	if err != nil {
+		return nil, err
+	}
+	if resourceType != general.KindPod {
+		svc, err2 := general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
+		if err2 != nil {
+			return nil, err2
+		}
+		return []coreV1.Service{*svc}, nil
+	}
+	pod, err := cluster.Ins().GetPod(name, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	svcs, err := cluster.Ins().GetServicesBySelector(pod.Labels, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	} else if len(svcs) == 0 {
+		return nil, fmt.Errorf("pod '%s' does not belong to any service", name)
+	}
+	return svcs, nil
+}
+
+func hasAnnotation(svc coreV1.Service, key, value string) bool {
+	v, exists := svc.Annotations[key]
+	return exists && (value == "" || v == value)
+}
@@ -0,0 +1,34 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_parseRequireAnnotation(t *testing.T) {
+	cases := map[string][]string{
+		"debug.company.com/exchangeable=true": {"debug.company.com/exchangeable", "true"},
+		"exchangeable":                        {"exchangeable", ""},
+		" team = a ":                          {"team", "a"},
+	}
+	for input, expected := range cases {
+		key, value, err := parseRequireAnnotation(input)
+		require.Nil(t, err)
+		require.Equal(t, expected, []string{key, value}, "annotation of '%s' incorrect", input)
+	}
+	for _, input := range []string{"", "=true", "a/b/c=true", "-a"} {
+		_, _, err := parseRequireAnnotation(input)
+		require.NotNil(t, err, "'%s' should be invalid", input)
+	}
+}
+
+func Test_hasAnnotation(t *testing.T) {
+	svc := coreV1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"exchangeable": "true"}}}
+	require.True(t, hasAnnotation(svc, "exchangeable", ""))
+	require.True(t, hasAnnotation(svc, "exchangeable", "true"))
+	require.False(t, hasAnnotation(svc, "exchangeable", "false"))
+	require.False(t, hasAnnotation(svc, "other", ""))
+}
@@ -0,0 +1,170 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// CheckMultipleTargets verify options when more than one service is exchanged by the same process
+func CheckMultipleTargets(resourceNames []string) error {
+	if len(resourceNames) < 2 {
+		return nil
+	}
+	ex := opt.Get().Exchange
+	if ex.Mode != util.ExchangeModeSelector {
+		// each target of scale and ephemeral mode needs its own shadow pod, and its state to recover is kept per process
+		return fmt.Errorf("exchanging multiple services is only supported in %s mode, "+
+			"please exchange them with one command each in %s mode", util.ExchangeModeSelector, ex.Mode)
+	}
+	if ex.NoShadow || ex.SharedShadow != "" || ex.PassthroughPorts || ex.Ramp != "" || ex.ExposeFrom != "" ||
+		ex.FallbackOnOverload || ex.ExecProbe || ex.TlsTerminate {
+		return fmt.Errorf("--noShadow, --sharedShadow, --passthroughPorts, --ramp, --exposeFrom, --fallbackOnOverload, " +
+			"--execProbe and --tlsTerminate cannot be used when exchanging multiple services")
+	}
+	names := map[string]bool{}
+	for _, resourceName := range resourceNames {
+		resourceType, name, err := general.ParseResourceName(resourceName)
+		if err != nil {
+			return err
+		}
+		if resourceType == general.KindPod {
+			return fmt.Errorf("single pod '%s' cannot be exchanged together with other services", name)
+		}
+		if names[resourceName] {
+			return fmt.Errorf("'%s' is specified more than once", resourceName)
+		}
+		names[resourceName] = true
+	}
+	return nil
+}
+
+// CheckTargets verify each target exists, and ports of multiple services can be exchanged together
+func CheckTargets(resourceNames []string) error {
+	if len(resourceNames) == 1 {
+		return CheckTarget(resourceNames[0])
+	}
+	var svcs []*coreV1.Service
+	for _, resourceName := range resourceNames {
+		svc, err := general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
+		if err != nil {
+			return err
+		}
+		svcs = append(svcs, svc)
+	}
+	_, err := combineTargetPorts(svcs)
+	return err
+}
+
+// ByMultipleSelector let all specified services select one shadow pod, which forwards ports of each service to local,
+// all services are checked before any change applied, so that a missing service leaves cluster untouched
+func ByMultipleSelector(resourceNames []string) (*general.SetupResult, error) {
+	var svcs []*coreV1.Service
+	for _, resourceName := range resourceNames {
+		svc, err := general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		// Lock services to avoid conflict, must be first step
+		if svc, err = general.LockService(svc.Name, opt.Get().Global.Namespace, 0); err != nil {
+			return nil, err
+		}
+		defer general.UnlockService(svc.Name, opt.Get().Global.Namespace)
+		if err = checkServiceNotOccupied(svc); err != nil {
+			return nil, err
+		}
+		if len(svc.Spec.Selector) == 0 {
+			return nil, fmt.Errorf("service '%s' has no selector, cannot be exchanged together with other services", svc.Name)
+		}
+		svcs = append(svcs, svc)
+	}
+	targetPorts, err := combineTargetPorts(svcs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create shadow pod
+	shadowName := svcs[0].Name + util.ExchangePodInfix + strings.ToLower(util.RandomString(5))
+	shadowLabels := map[string]string{
+		util.KtRole:   util.RoleExchangeShadow,
+		util.KtTarget: util.RandomString(20),
+	}
+	var svcNames []string
+	for _, svc := range svcs {
+		svcNames = append(svcNames, svc.Name)
+	}
+	annotation := map[string]string{
+		util.KtConfig: fmt.Sprintf("service=%s", strings.Join(svcNames, ",")),
+	}
+	if err = general.CreateShadowAndInbound(shadowName, opt.Get().Exchange.Expose,
+		shadowLabels, annotation, targetPorts); err != nil {
+		return nil, err
+	}
+
+	// Let target services select shadow pod, services already updated are recovered by cleanup if any one fails
+	for _, svc := range svcs {
+		opt.Store.Origin = util.Append(opt.Store.Origin, svc.Name)
+		if err = general.UpdateServiceSelector(svc.Name, opt.Get().Global.Namespace, shadowLabels); err != nil {
+			return nil, err
+		}
+	}
+	return general.NewSetupResult(svcNames, util.ExchangeModeSelector, opt.Get().Exchange.Expose), nil
+}
+
+// combineTargetPorts merge target ports of services, which must not overlap since they are served by one shadow pod,
+// each expose port should target one of them, and each service should have at least one port exposed
+func combineTargetPorts(svcs []*coreV1.Service) (map[int]string, error) {
+	combined := map[int]string{}
+	owners := map[int]string{}
+	for _, svc := range svcs {
+		targetPorts := general.GetTargetPorts(svc)
+		for _, other := range svcs {
+			if other == svc {
+				break
+			}
+			if overlapped := overlappedPorts(targetPorts, general.GetTargetPorts(other)); len(overlapped) > 0 {
+				return nil, fmt.Errorf("port %v is targeted by both service %s and %s, cannot be exchanged together",
+					overlapped, other.Name, svc.Name)
+			}
+		}
+		for p, name := range targetPorts {
+			combined[p] = name
+			owners[p] = svc.Name
+		}
+	}
+	if port := util.FindInvalidRemotePort(opt.Get().Exchange.Expose, combined); port != "" {
+		return nil, fmt.Errorf("target port %s not exists in any service to exchange", port)
+	}
+	exposed := map[string]bool{}
+	for _, exposePort := range strings.Split(opt.Get().Exchange.Expose, ",") {
+		if _, remotePort, _, err := util.ParseExposePort(exposePort); err == nil {
+			exposed[owners[remotePort]] = true
+		}
+	}
+	for _, svc := range svcs {
+		if !exposed[svc.Name] {
+			return nil, fmt.Errorf("no port of service %s is specified in --expose, its target ports are %s",
+				svc.Name, joinPorts(general.GetTargetPorts(svc)))
+		}
+	}
+	return combined, nil
+}
+
+func joinPorts(ports map[int]string) string {
+	var sorted []int
+	for p := range ports {
+		sorted = append(sorted, p)
+	}
+	sort.Ints(sorted)
+	var items []string
+	for _, p := range sorted {
+		items = append(items, strconv.Itoa(p))
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,42 @@
+package exchange
+
+import (
+	"testing"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func Test_combineTargetPorts(t *testing.T) {
+	defer func(expose string) { opt.Get().Exchange.Expose = expose }(opt.Get().Exchange.Expose)
+	svcOf := func(name string, targetPorts ...int) *coreV1.Service {
+		svc := &coreV1.Service{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, p := range targetPorts {
+			svc.Spec.Ports = append(svc.Spec.Ports, coreV1.ServicePort{Port: 80, TargetPort: intstr.FromInt(p)})
+		}
+		return svc
+	}
+	svcA, svcB := svcOf("a", 8080), svcOf("b", 9090, 9091)
+
+	opt.Get().Exchange.Expose = "8080,7000:9090"
+	ports, err := combineTargetPorts([]*coreV1.Service{svcA, svcB})
+	require.NoError(t, err)
+	require.Equal(t, map[int]string{8080: "kt-8080", 9090: "kt-9090", 9091: "kt-9091"}, ports)
+
+	opt.Get().Exchange.Expose = "8080"
+	_, err = combineTargetPorts([]*coreV1.Service{svcA, svcB})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no port of service b")
+
+	opt.Get().Exchange.Expose = "8080,9090,7000"
+	_, err = combineTargetPorts([]*coreV1.Service{svcA, svcB})
+	require.Error(t, err)
+
+	opt.Get().Exchange.Expose = "8080,9090"
+	_, err = combineTargetPorts([]*coreV1.Service{svcA, svcB, svcOf("c", 9090)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "port [9090] is targeted by both service b and c")
+}
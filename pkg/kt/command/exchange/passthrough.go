@@ -0,0 +1,107 @@
+package exchange
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	"sort"
+	"strings"
+)
+
+// getPassthroughPorts ports of target not in expose list
+func getPassthroughPorts(targetPorts []int) ([]int, error) {
+	exposed := map[int]bool{}
+	for _, exposePort := range strings.Split(opt.Get().Exchange.Expose, ",") {
+		_, remotePort, err := util.ParsePortMapping(exposePort)
+		if err != nil {
+			return nil, err
+		}
+		exposed[remotePort] = true
+	}
+	var ports []int
+	for _, p := range targetPorts {
+		if !exposed[p] {
+			ports = append(ports, p)
+		}
+	}
+	sort.Ints(ports)
+	return ports, nil
+}
+
+// setupPassthrough let connections to specified ports of shadow pod go to original pods,
+// return ports to be listened by shadow pod
+func setupPassthrough(ports []int, hosts []string) string {
+	exposePorts := opt.Get().Exchange.Expose
+	for _, p := range ports {
+		sshchannel.SetPassthrough(p, hosts)
+		exposePorts = fmt.Sprintf("%s,%d:%d", exposePorts, p, p)
+	}
+	log.Info().Msgf("Connections to port %v will pass through to original pods %v", ports, hosts)
+	return exposePorts
+}
+
+// passthroughForService pass ports of service not exchanged through to pods currently selected by it
+func passthroughForService(svc *coreV1.Service, targetPorts map[int]string) (string, error) {
+	var allPorts []int
+	for p := range targetPorts {
+		allPorts = append(allPorts, p)
+	}
+	ports, err := getPassthroughPorts(allPorts)
+	if err != nil || len(ports) == 0 {
+		return opt.Get().Exchange.Expose, err
+	}
+	hosts, err := getRunningPodIps(svc.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	if len(hosts) == 0 && !opt.Get().Global.DryRun {
+		return "", fmt.Errorf("no running pod of service '%s' to pass port %v through", svc.Name, ports)
+	}
+	return setupPassthrough(ports, hosts), nil
+}
+
+// passthroughForWorkload create a copy of original pod which is not selected by any service,
+// and pass ports of workload not exchanged through to it
+func passthroughForWorkload(workload *general.Workload) (string, error) {
+	var allPorts []int
+	for _, c := range workload.Template.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Protocol != coreV1.ProtocolUDP {
+				allPorts = append(allPorts, int(p.ContainerPort))
+			}
+		}
+	}
+	ports, err := getPassthroughPorts(allPorts)
+	if err != nil || len(ports) == 0 {
+		return opt.Get().Exchange.Expose, err
+	}
+	podName := workload.Name + util.PassthroughPodInfix + strings.ToLower(util.RandomString(5))
+	log.Info().Msgf("Creating passthrough pod %s for port %v", podName, ports)
+	opt.Store.Passthrough = podName
+	pod, err := cluster.Ins().CreatePassthroughPod(podName, map[string]string{util.KtRole: util.RolePassthrough},
+		map[string]string{util.KtConfig: fmt.Sprintf("app=%s", workload.Name)}, workload.Template.Spec)
+	if err != nil {
+		return "", err
+	}
+	return setupPassthrough(ports, []string{pod.Status.PodIP}), nil
+}
+
+// getRunningPodIps get ips of running pods with specified labels
+func getRunningPodIps(selector map[string]string) ([]string, error) {
+	pods, err := cluster.Ins().GetPodsByLabel(selector, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == coreV1.PodRunning && pod.Status.PodIP != "" && pod.DeletionTimestamp == nil {
+			hosts = append(hosts, pod.Status.PodIP)
+		}
+	}
+	return hosts, nil
+}
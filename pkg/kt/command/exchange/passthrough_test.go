@@ -0,0 +1,28 @@
+package exchange
+
+import (
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_getPassthroughPorts(t *testing.T) {
+	tests := []struct {
+		expose      string
+		targetPorts []int
+		want        []int
+	}{
+		{"8080", []int{8080}, nil},
+		{"8080", []int{9090, 8080, 7070}, []int{7070, 9090}},
+		{"18080:8080,9090", []int{9090, 8080, 7070}, []int{7070}},
+	}
+	for _, tt := range tests {
+		opt.Get().Exchange.Expose = tt.expose
+		ports, err := getPassthroughPorts(tt.targetPorts)
+		require.Nil(t, err)
+		require.Equal(t, tt.want, ports, "passthrough ports of %s incorrect", tt.expose)
+	}
+	opt.Get().Exchange.Expose = "abc"
+	_, err := getPassthroughPorts([]int{8080})
+	require.NotNil(t, err)
+}
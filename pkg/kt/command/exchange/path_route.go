@@ -0,0 +1,127 @@
+package exchange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+)
+
+// pathRoutes local port of each path prefix specified in '--expose', e.g. '/api:8081'
+var pathRoutes map[string]int
+
+// ExtractPathRoutes separate path routes from ports in '--expose', e.g. '8080/http,/api:8081,/web:8082'
+func ExtractPathRoutes() error {
+	exposePorts, routes, err := parsePathRoutes(opt.Get().Exchange.Expose)
+	if err != nil {
+		return err
+	}
+	opt.Get().Exchange.Expose = exposePorts
+	pathRoutes = routes
+	return nil
+}
+
+// CheckPathRoutes verify path routes are applied to one http port, and all their local ports are listened
+func CheckPathRoutes() error {
+	if len(pathRoutes) == 0 {
+		return nil
+	}
+	ex := opt.Get().Exchange
+	if ex.NoShadow {
+		return fmt.Errorf("path routes in --expose require shadow pod, cannot be used with --noShadow")
+	}
+	if ex.LocalRateLimit > 0 || opt.Get().Global.PreserveSourceIp != "" {
+		return fmt.Errorf("path routes in --expose cannot be used with --localRateLimit or --preserveSourceIp")
+	}
+	if _, _, err := getPathRoutedPort(); err != nil {
+		return err
+	}
+	if ex.SkipPortChecking {
+		return nil
+	}
+	var ports []string
+	for _, port := range pathRoutes {
+		ports = append(ports, strconv.Itoa(port))
+	}
+	return general.CheckLocalPorts(strings.Join(ports, ","), ex.LocalAddr)
+}
+
+// SetupPathRoutes dispatch requests of the http port to local ports of path routes
+func SetupPathRoutes() {
+	if len(pathRoutes) == 0 {
+		return
+	}
+	localPort, remotePort, _ := getPathRoutedPort()
+	sshchannel.SetupPathRoutes(remotePort, opt.Get().Exchange.LocalAddr, localPort, pathRoutes)
+	for prefix, port := range pathRoutes {
+		log.Info().Msgf("Requests to port %d with path prefix %s will be forwarded to local port %d", remotePort, prefix, port)
+	}
+}
+
+// getPathRoutedPort the only http port in '--expose', which path routes apply to
+func getPathRoutedPort() (int, int, error) {
+	localPort, remotePort := -1, -1
+	for _, exposePort := range strings.Split(opt.Get().Exchange.Expose, ",") {
+		lp, rp, protocol, err := util.ParseExposePort(exposePort)
+		if err != nil {
+			return -1, -1, err
+		}
+		if protocol != util.ExposeProtocolHttp {
+			continue
+		}
+		if remotePort > 0 {
+			return -1, -1, fmt.Errorf("path routes in --expose require exactly one port with '/%s' protocol",
+				util.ExposeProtocolHttp)
+		}
+		localPort, remotePort = lp, rp
+	}
+	if remotePort < 0 {
+		return -1, -1, fmt.Errorf("path routes in --expose only apply to http service, please mark the port with '/%s', "+
+			"e.g. 8080/%s,/api:8081", util.ExposeProtocolHttp, util.ExposeProtocolHttp)
+	}
+	return localPort, remotePort, nil
+}
+
+// parsePathRoutes split '--expose' to ports and path routes in '<prefix>:<localPort>' format
+func parsePathRoutes(expose string) (string, map[string]int, error) {
+	var exposePorts []string
+	routes := map[string]int{}
+	for _, item := range strings.Split(expose, ",") {
+		if !strings.HasPrefix(item, "/") {
+			exposePorts = append(exposePorts, item)
+			continue
+		}
+		pos := strings.LastIndex(item, ":")
+		if pos < 0 {
+			return "", nil, fmt.Errorf("invalid path route '%s', should be in '<prefix>:<localPort>' format", item)
+		}
+		prefix := item[:pos]
+		port, err := strconv.Atoi(item[pos+1:])
+		if err != nil || port <= 0 || port > 65535 {
+			return "", nil, fmt.Errorf("invalid local port '%s' of path route '%s'", item[pos+1:], item)
+		}
+		// '/api' and '/api/' match same requests
+		normalized := strings.TrimSuffix(prefix, "/")
+		if normalized == "" {
+			return "", nil, fmt.Errorf("path route '%s' matches all requests, use the local port of http port instead", item)
+		}
+		for existing := range routes {
+			if strings.TrimSuffix(existing, "/") == normalized {
+				return "", nil, fmt.Errorf("path prefix '%s' and '%s' are ambiguous", existing, prefix)
+			}
+		}
+		routes[prefix] = port
+	}
+	if len(exposePorts) == 0 {
+		return "", nil, fmt.Errorf("no port to expose, path routes should come with an http port, e.g. 8080/http,/api:8081")
+	}
+	if len(routes) == 0 {
+		routes = nil
+	}
+	return strings.Join(exposePorts, ","), routes, nil
+}
@@ -0,0 +1,25 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parsePathRoutes(t *testing.T) {
+	exposePorts, routes, err := parsePathRoutes("8080/http,/api:8081,/web/:8082,9090")
+	require.NoError(t, err)
+	require.Equal(t, "8080/http,9090", exposePorts)
+	require.Equal(t, map[string]int{"/api": 8081, "/web/": 8082}, routes)
+
+	exposePorts, routes, err = parsePathRoutes("7001,8080:80/http")
+	require.NoError(t, err)
+	require.Equal(t, "7001,8080:80/http", exposePorts)
+	require.Nil(t, routes)
+
+	for _, expose := range []string{"8080/http,/api", "8080/http,/api:abc", "8080/http,/:8081",
+		"8080/http,/api:8081,/api/:8082", "/api:8081"} {
+		_, _, err = parsePathRoutes(expose)
+		require.Error(t, err, expose)
+	}
+}
@@ -0,0 +1,59 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+)
+
+// SetupPause let exchange be paused and resumed at runtime, only selector mode with shadow pod is supported,
+// and only when one service is exchanged
+func SetupPause() {
+	ex := opt.Get().Exchange
+	if ex.Mode != util.ExchangeModeSelector || ex.NoShadow || opt.Store.Origin == "" ||
+		strings.Contains(opt.Store.Origin, ",") {
+		return
+	}
+	general.RegisterControlHandler(general.CommandPause, pauseExchange)
+	general.RegisterControlHandler(general.CommandResume, resumeExchange)
+}
+
+// pauseExchange route all traffic to pods currently matching original selector of service, shadow pod is kept
+func pauseExchange() error {
+	svc, err := cluster.Ins().GetService(opt.Store.Origin, opt.Get().Global.Namespace)
+	if err != nil {
+		return err
+	}
+	var selector map[string]string
+	if err = json.Unmarshal([]byte(svc.Annotations[util.KtSelector]), &selector); err != nil {
+		return fmt.Errorf("failed to get original selector of service %s: %s", svc.Name, err)
+	}
+	hosts, err := getRunningPodIps(selector)
+	if err != nil {
+		return err
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("no running original pod of service %s to route traffic back to", svc.Name)
+	}
+	if !sshchannel.Pause(hosts) {
+		return fmt.Errorf("exchange is already paused")
+	}
+	log.Info().Msgf("Exchange paused, all requests to service %s go to original pods %v", svc.Name, hosts)
+	return nil
+}
+
+// resumeExchange route traffic to local again
+func resumeExchange() error {
+	if !sshchannel.Resume() {
+		return fmt.Errorf("exchange is not paused")
+	}
+	log.Info().Msgf("Exchange resumed, requests to service %s go to local again", opt.Store.Origin)
+	return nil
+}
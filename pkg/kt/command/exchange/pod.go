@@ -0,0 +1,168 @@
+package exchange
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"strings"
+)
+
+// ByPodEndpoint replace address of specified pod in endpoints of its service with a shadow pod,
+// so that only traffic would have reached that pod is redirected to local
+func ByPodEndpoint(resourceName string) (*general.SetupResult, error) {
+	_, podName, err := general.ParseResourceName(resourceName)
+	if err != nil {
+		return nil, err
+	}
+	pod, svcs, err := getPodWithServices(podName, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(svcs) > 1 {
+		log.Warn().Msgf("Pod '%s' is selected by %d services, only traffic via service '%s' will be exchanged",
+			podName, len(svcs), svcs[0].Name)
+	}
+	if pod.Status.PodIP == "" {
+		return nil, fmt.Errorf("pod '%s' has no ip address yet (%s)", podName, pod.Status.Phase)
+	}
+	svc := &svcs[0]
+	if port := util.FindInvalidRemotePort(opt.Get().Exchange.Expose, general.GetTargetPorts(svc)); port != "" {
+		return nil, fmt.Errorf("target port %s not exists in service %s", port, svc.Name)
+	}
+
+	// Lock service to avoid conflict, must be first step
+	svc, err = general.LockService(svc.Name, opt.Get().Global.Namespace, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer general.UnlockService(svc.Name, opt.Get().Global.Namespace)
+
+	if err = checkServiceNotOccupied(svc); err != nil {
+		return nil, err
+	}
+	originSubsets, err := cluster.Ins().GetServiceEndpoints(svc.Name, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if _, found := replacePodAddress(originSubsets, pod.Status.PodIP, coreV1.EndpointAddress{}); !found {
+		return nil, fmt.Errorf("pod '%s' is not an endpoint of service '%s'", podName, svc.Name)
+	}
+
+	// Create shadow pod
+	shadowName := podName + util.ExchangePodInfix + strings.ToLower(util.RandomString(5))
+	shadowLabels := map[string]string{
+		util.KtRole:   util.RoleExchangeShadow,
+		util.KtTarget: util.RandomString(20),
+	}
+	annotation := map[string]string{
+		util.KtConfig: fmt.Sprintf("service=%s", svc.Name),
+	}
+	if err = general.CreateShadowAndInbound(shadowName, opt.Get().Exchange.Expose,
+		shadowLabels, annotation, general.GetTargetPorts(svc)); err != nil {
+		return nil, err
+	}
+
+	// Remove selector of target service, so that its endpoints are no longer managed by cluster
+	opt.Store.Origin = svc.Name
+	if err = general.UpdateServiceSelector(svc.Name, opt.Get().Global.Namespace, nil); err != nil {
+		return nil, err
+	}
+	if opt.Get().Global.DryRun {
+		log.Info().Msgf("Address of pod %s in endpoints of service %s would be replaced by shadow pod", podName, svc.Name)
+		return general.NewSetupResult([]string{svc.Name}, util.ExchangeModeSelector, opt.Get().Exchange.Expose), nil
+	}
+	shadowAddress, err := getShadowAddress(shadowLabels)
+	if err != nil {
+		return nil, err
+	}
+	subsets, _ := replacePodAddress(originSubsets, pod.Status.PodIP, shadowAddress)
+	// Original addresses must be put back before service selector get restored, in case any pod is not ready yet
+	general.RegisterCleanupHook(func() {
+		if err2 := cluster.Ins().SetServiceEndpoints(svc.Name, opt.Get().Global.Namespace, originSubsets); err2 != nil {
+			log.Warn().Err(err2).Msgf("Failed to restore endpoints of service %s", svc.Name)
+		}
+	})
+	if err = cluster.Ins().SetServiceEndpoints(svc.Name, opt.Get().Global.Namespace, subsets); err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Address %s of pod %s in endpoints of service %s is replaced by shadow pod %s",
+		pod.Status.PodIP, podName, svc.Name, shadowAddress.TargetRef.Name)
+	return general.NewSetupResult([]string{svc.Name}, util.ExchangeModeSelector, opt.Get().Exchange.Expose), nil
+}
+
+// getPodWithServices get specified pod and services selecting it
+func getPodWithServices(podName, namespace string) (*coreV1.Pod, []coreV1.Service, error) {
+	pod, err := cluster.Ins().GetPod(podName, namespace)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("pod '%s' is not found in namespace %s", podName, namespace)
+		}
+		return nil, nil, err
+	}
+	svcs, err := cluster.Ins().GetServicesBySelector(pod.Labels, namespace)
+	if err != nil {
+		return nil, nil, err
+	} else if len(svcs) == 0 {
+		return nil, nil, fmt.Errorf("pod '%s' does not belong to any service", podName)
+	}
+	return pod, svcs, nil
+}
+
+// getShadowAddress endpoint address of created shadow pod, shadow may be created by deployment, so look it up via label
+func getShadowAddress(shadowLabels map[string]string) (coreV1.EndpointAddress, error) {
+	pods, err := cluster.Ins().GetPodsByLabel(shadowLabels, opt.Get().Global.Namespace)
+	if err != nil {
+		return coreV1.EndpointAddress{}, err
+	}
+	for _, p := range pods.Items {
+		if p.Status.PodIP != "" && p.DeletionTimestamp == nil {
+			return coreV1.EndpointAddress{
+				IP: p.Status.PodIP,
+				TargetRef: &coreV1.ObjectReference{
+					Kind:      "Pod",
+					Name:      p.Name,
+					Namespace: p.Namespace,
+					UID:       p.UID,
+				},
+			}, nil
+		}
+	}
+	return coreV1.EndpointAddress{}, fmt.Errorf("shadow pod has no ip address")
+}
+
+// replacePodAddress copy of subsets with address of specified pod replaced, and whether that pod was found
+func replacePodAddress(subsets []coreV1.EndpointSubset, podIp string, address coreV1.EndpointAddress) ([]coreV1.EndpointSubset, bool) {
+	found := false
+	var replaced []coreV1.EndpointSubset
+	for _, subset := range subsets {
+		s := *subset.DeepCopy()
+		s.Addresses, s.NotReadyAddresses = nil, nil
+		inSubset := false
+		for _, a := range subset.Addresses {
+			if a.IP == podIp {
+				inSubset = true
+			} else {
+				s.Addresses = append(s.Addresses, a)
+			}
+		}
+		for _, a := range subset.NotReadyAddresses {
+			if a.IP == podIp {
+				inSubset = true
+			} else {
+				s.NotReadyAddresses = append(s.NotReadyAddresses, a)
+			}
+		}
+		if inSubset {
+			// shadow pod is ready once created, whatever state the replaced pod was in
+			s.Addresses = append(s.Addresses, address)
+			found = true
+		}
+		replaced = append(replaced, s)
+	}
+	return replaced, found
+}
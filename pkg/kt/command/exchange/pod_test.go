@@ -0,0 +1,47 @@
+package exchange
+
+import (
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func Test_replacePodAddress(t *testing.T) {
+	ports := []coreV1.EndpointPort{{Name: "http", Port: 8080}}
+	shadow := coreV1.EndpointAddress{IP: "10.0.0.9"}
+	subsets := []coreV1.EndpointSubset{
+		{
+			Addresses:         []coreV1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+			NotReadyAddresses: []coreV1.EndpointAddress{{IP: "10.0.0.3"}},
+			Ports:             ports,
+		},
+	}
+	tests := []struct {
+		podIp    string
+		found    bool
+		ready    []string
+		notReady []string
+	}{
+		{"10.0.0.1", true, []string{"10.0.0.2", "10.0.0.9"}, []string{"10.0.0.3"}},
+		{"10.0.0.3", true, []string{"10.0.0.1", "10.0.0.2", "10.0.0.9"}, nil},
+		{"10.0.0.4", false, []string{"10.0.0.1", "10.0.0.2"}, []string{"10.0.0.3"}},
+	}
+	for _, tt := range tests {
+		replaced, found := replacePodAddress(subsets, tt.podIp, shadow)
+		require.Equal(t, tt.found, found, "whether %s found incorrect", tt.podIp)
+		require.Len(t, replaced, 1)
+		require.Equal(t, tt.ready, ipsOf(replaced[0].Addresses), "ready addresses after replacing %s", tt.podIp)
+		require.Equal(t, tt.notReady, ipsOf(replaced[0].NotReadyAddresses), "not ready addresses after replacing %s", tt.podIp)
+		require.Equal(t, ports, replaced[0].Ports)
+	}
+	// original subsets should not be modified
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, ipsOf(subsets[0].Addresses))
+}
+
+func ipsOf(addresses []coreV1.EndpointAddress) []string {
+	var ips []string
+	for _, a := range addresses {
+		ips = append(ips, a.IP)
+	}
+	return ips
+}
@@ -0,0 +1,43 @@
+package exchange
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	coreV1 "k8s.io/api/core/v1"
+	"strings"
+)
+
+// Probe verify requests to exchanged service are redirected to local
+func Probe(resourceName string) error {
+	if opt.Get().Exchange.Mode == util.ExchangeModeEphemeral {
+		return fmt.Errorf("probe is not supported in %s mode", util.ExchangeModeEphemeral)
+	} else if opt.Store.RoutingBackend != "" {
+		// shadow pod is not in mesh, its request would not follow mesh routes
+		return fmt.Errorf("probe is not supported when exchanging via mesh routes")
+	}
+	var svc *coreV1.Service
+	var err error
+	if opt.Get().Exchange.Mode == util.ExchangeModeSelector {
+		// service selector already points to shadow pod, cannot be looked up via deployment labels anymore
+		svc, err = cluster.Ins().GetService(opt.Store.Origin, opt.Get().Global.Namespace)
+	} else {
+		svc, err = general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
+	}
+	if err != nil {
+		return err
+	}
+	localPort, remotePort, err := util.ParsePortMapping(strings.Split(opt.Get().Exchange.Expose, ",")[0])
+	if err != nil {
+		return err
+	}
+	targetPorts := general.GetTargetPorts(svc)
+	for _, p := range svc.Spec.Ports {
+		if p.TargetPort.IntValue() == remotePort || targetPorts[remotePort] == p.TargetPort.String() {
+			return general.ExecProbe(opt.Store.Shadow, svc.Name, int(p.Port), localPort)
+		}
+	}
+	return fmt.Errorf("no port of service %s targets port %d", svc.Name, remotePort)
+}
@@ -0,0 +1,137 @@
+package exchange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+)
+
+// rampStepInterval interval of checking local app health and raising its traffic share
+const rampStepInterval = 10 * time.Second
+
+// rampMaxFailures consecutive failed health checks before ramp aborted
+const rampMaxFailures = 3
+
+// CheckRamp verify options of gradual traffic shift
+func CheckRamp() error {
+	ex := opt.Get().Exchange
+	if ex.Ramp == "" {
+		return nil
+	}
+	if ex.Mode != util.ExchangeModeSelector {
+		return fmt.Errorf("--ramp is only supported in %s mode", util.ExchangeModeSelector)
+	}
+	if ex.NoShadow {
+		return fmt.Errorf("--ramp requires shadow pod, cannot be used with --noShadow")
+	}
+	_, _, _, err := parseRamp(ex.Ramp)
+	return err
+}
+
+// setupRamp let only the starting share of connections go to local, the rest go to original pods
+func setupRamp(serviceName string, selector map[string]string) error {
+	start, _, _, err := parseRamp(opt.Get().Exchange.Ramp)
+	if err != nil {
+		return err
+	}
+	hosts, err := getRunningPodIps(selector)
+	if err != nil {
+		return err
+	}
+	if len(hosts) == 0 && !opt.Get().Global.DryRun {
+		return fmt.Errorf("no running pod of service '%s' to share traffic with, cannot ramp", serviceName)
+	}
+	sshchannel.SetupTrafficRamp(start, hosts)
+	return nil
+}
+
+// startRamp raise traffic share of local app step by step, hold it while local app is unhealthy,
+// and send all traffic back to original pods if local app keeps unhealthy
+func startRamp() {
+	start, end, duration, _ := parseRamp(opt.Get().Exchange.Ramp)
+	log.Info().Msgf("Ramping traffic to local from %d%% to %d%% in %s", start, end, duration)
+	var elapsed time.Duration
+	failures := 0
+	for {
+		time.Sleep(rampStepInterval)
+		if healthy, reason := checkLocalHealth(); !healthy {
+			failures++
+			if failures >= rampMaxFailures {
+				sshchannel.SetTrafficPercent(0)
+				log.Error().Msgf("Local app is unhealthy (%s), ramp aborted, all traffic goes back to original pods", reason)
+				return
+			}
+			log.Warn().Msgf("Local app is unhealthy (%s), holding traffic share", reason)
+			continue
+		}
+		failures = 0
+		elapsed += rampStepInterval
+		percent := rampPercent(start, end, elapsed, duration)
+		sshchannel.SetTrafficPercent(percent)
+		if percent >= end {
+			log.Info().Msgf("Ramp finished, %d%% of traffic goes to local", percent)
+			return
+		}
+		log.Info().Msgf("%d%% of traffic goes to local", percent)
+	}
+}
+
+// checkLocalHealth all local ports are listened, and respond to ready path if specified
+func checkLocalHealth() (bool, string) {
+	ex := opt.Get().Exchange
+	timeout := time.Duration(ex.LocalReadyTimeout) * time.Second
+	for _, exposePort := range strings.Split(ex.Expose, ",") {
+		localPort, _, protocol, err := util.ParseExposePort(exposePort)
+		if err != nil {
+			return false, err.Error()
+		}
+		path := ""
+		switch {
+		case protocol == util.ExposeProtocolHttp:
+			path = ex.LocalReadyPath
+		case protocol == "" && ex.LocalReadyPath != "":
+			protocol, path = util.ExposeProtocolHttp, ex.LocalReadyPath
+		case protocol == "":
+			protocol = util.ExposeProtocolTcp
+		}
+		if ready, reason := sshchannel.CheckLocalReady(ex.LocalAddr, localPort, protocol, path, timeout); !ready {
+			return false, reason
+		}
+	}
+	return true, ""
+}
+
+// parseRamp parse ramp option in '<start>:<end>:<duration>' format, e.g. '10:100:5m'
+func parseRamp(ramp string) (int, int, time.Duration, error) {
+	parts := strings.Split(ramp, ":")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid ramp '%s', should be in '<start>:<end>:<duration>' format, e.g. 10:100:5m", ramp)
+	}
+	start, err := strconv.Atoi(parts[0])
+	if err != nil || start < 1 || start > 100 {
+		return 0, 0, 0, fmt.Errorf("invalid start percent '%s' of ramp, should between 1 and 100", parts[0])
+	}
+	end, err := strconv.Atoi(parts[1])
+	if err != nil || end < start || end > 100 {
+		return 0, 0, 0, fmt.Errorf("invalid end percent '%s' of ramp, should between %d and 100", parts[1], start)
+	}
+	duration, err := time.ParseDuration(parts[2])
+	if err != nil || duration <= 0 {
+		return 0, 0, 0, fmt.Errorf("invalid duration '%s' of ramp, should be a positive duration like 5m", parts[2])
+	}
+	return start, end, duration, nil
+}
+
+// rampPercent traffic share of local app after specified time of ramping
+func rampPercent(start, end int, elapsed, duration time.Duration) int {
+	if elapsed >= duration {
+		return end
+	}
+	return start + int(int64(end-start)*int64(elapsed)/int64(duration))
+}
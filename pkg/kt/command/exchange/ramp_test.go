@@ -0,0 +1,27 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseRamp(t *testing.T) {
+	start, end, duration, err := parseRamp("10:100:5m")
+	require.NoError(t, err)
+	require.Equal(t, 10, start)
+	require.Equal(t, 100, end)
+	require.Equal(t, 5*time.Minute, duration)
+	for _, ramp := range []string{"10:100", "0:100:5m", "50:20:5m", "10:101:5m", "10:100:0s", "a:100:5m", "10:100:5"} {
+		_, _, _, err = parseRamp(ramp)
+		require.Error(t, err, ramp)
+	}
+}
+
+func Test_rampPercent(t *testing.T) {
+	require.Equal(t, 10, rampPercent(10, 100, 0, 5*time.Minute))
+	require.Equal(t, 55, rampPercent(10, 100, 150*time.Second, 5*time.Minute))
+	require.Equal(t, 100, rampPercent(10, 100, 5*time.Minute, 5*time.Minute))
+	require.Equal(t, 100, rampPercent(10, 100, 6*time.Minute, 5*time.Minute))
+}
@@ -0,0 +1,54 @@
+package exchange
+
+import (
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"strings"
+	"time"
+)
+
+// LocalReadinessEnabled whether any expose port is gated by readiness of local app
+func LocalReadinessEnabled() bool {
+	if opt.Get().Exchange.NoShadow {
+		return false
+	}
+	if opt.Get().Exchange.LocalReadyPath != "" {
+		return true
+	}
+	for _, exposePort := range strings.Split(opt.Get().Exchange.Expose, ",") {
+		if _, _, protocol, err := util.ParseExposePort(exposePort); err == nil && protocol != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupLocalReadiness gate each expose port with readiness check of its protocol,
+// ports without protocol share the http probe of '--localReadyPath' on first of them
+func SetupLocalReadiness() {
+	ex := opt.Get().Exchange
+	timeout := time.Duration(ex.LocalReadyTimeout) * time.Second
+	defaultLocalPort := -1
+	var defaultRemotePorts []int
+	for _, exposePort := range strings.Split(ex.Expose, ",") {
+		localPort, remotePort, protocol, err := util.ParseExposePort(exposePort)
+		if err != nil {
+			continue
+		}
+		switch {
+		case protocol == util.ExposeProtocolHttp:
+			sshchannel.SetupLocalReadiness([]int{remotePort}, ex.LocalAddr, localPort, protocol, ex.LocalReadyPath, timeout)
+		case protocol != "":
+			sshchannel.SetupLocalReadiness([]int{remotePort}, ex.LocalAddr, localPort, protocol, "", timeout)
+		case ex.LocalReadyPath != "":
+			if defaultLocalPort < 0 {
+				defaultLocalPort = localPort
+			}
+			defaultRemotePorts = append(defaultRemotePorts, remotePort)
+		}
+	}
+	if len(defaultRemotePorts) > 0 {
+		sshchannel.SetupLocalReadiness(defaultRemotePorts, ex.LocalAddr, defaultLocalPort, "", ex.LocalReadyPath, timeout)
+	}
+}
@@ -0,0 +1,74 @@
+package exchange
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	"github.com/alibaba/kt-connect/pkg/kt/command/mesh"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	coreV1 "k8s.io/api/core/v1"
+	"strings"
+)
+
+// byRouteRedirect exchange service without selector, by letting mesh routes of it point to shadow service
+func byRouteRedirect(svc *coreV1.Service) error {
+	redirectors := mesh.GetRouteRedirectors()
+	if len(redirectors) == 0 {
+		return fmt.Errorf("service '%s' has no selector, and no route resource of %s or %s is installed, "+
+			"cannot apply exchange", svc.Name, util.RoutingBackendIstio, util.RoutingBackendGatewayApi)
+	}
+
+	// Create shadow pod
+	shadowName := svc.Name + util.ExchangePodInfix + strings.ToLower(util.RandomString(5))
+	shadowLabels := map[string]string{
+		util.KtRole:   util.RoleExchangeShadow,
+		util.KtTarget: util.RandomString(20),
+	}
+	annotation := map[string]string{
+		util.KtConfig: fmt.Sprintf("service=%s", svc.Name),
+	}
+	if err := general.CreateShadowAndInbound(shadowName, opt.Get().Exchange.Expose,
+		shadowLabels, annotation, general.GetTargetPorts(svc)); err != nil {
+		return err
+	}
+
+	// Create shadow service with same ports as target service
+	ports := make(map[int]int)
+	for _, p := range svc.Spec.Ports {
+		if p.TargetPort.IntValue() > 0 {
+			ports[int(p.Port)] = p.TargetPort.IntValue()
+		} else {
+			ports[int(p.Port)] = int(p.Port)
+		}
+	}
+	if _, err := cluster.Ins().CreateService(&cluster.SvcMetaAndSpec{
+		Meta: &cluster.ResourceMeta{
+			Name:        shadowName,
+			Namespace:   opt.Get().Global.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		External:  false,
+		Ports:     ports,
+		Selectors: shadowLabels,
+	}); err != nil {
+		return err
+	}
+	opt.Store.Service = shadowName
+
+	// Let routes of target service point to shadow service
+	count := 0
+	for _, redirector := range redirectors {
+		c, err := redirector.Redirect(svc, shadowName)
+		count += c
+		if err != nil {
+			return err
+		}
+	}
+	if count == 0 {
+		return fmt.Errorf("service '%s' has no selector, and is not routed by any VirtualService or HTTPRoute, "+
+			"nothing to exchange", svc.Name)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package exchange
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+)
+
+// ResolveSelectedServices list services matching '--selector' in current namespace, they are exchanged together
+// like services specified by name, confirmation via '--yes' is required when too many services matched
+func ResolveSelectedServices() ([]string, error) {
+	selector := opt.Get().Exchange.Selector
+	labels, err := parseServiceSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	svcList, err := cluster.Ins().GetServicesByLabel(labels, opt.Get().Global.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, svc := range svcList.Items {
+		names = append(names, svc.Name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no service matches selector '%s' in namespace %s", selector, opt.Get().Global.Namespace)
+	}
+	sort.Strings(names)
+	log.Info().Msgf("Services matching selector '%s': %s", selector, strings.Join(names, ", "))
+	if err = confirmSelectedServices(names, opt.Get().Exchange.Yes); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// parseServiceSelector convert label selector in 'key=value[,key=value]' format to map
+func parseServiceSelector(selector string) (map[string]string, error) {
+	labels := util.String2Map(selector)
+	if len(labels) != len(strings.Split(selector, ",")) {
+		return nil, fmt.Errorf("invalid selector '%s', should be in 'key=value[,key=value]' format", selector)
+	}
+	return labels, nil
+}
+
+// confirmSelectedServices refuse exchanging more services than threshold unless confirmed
+func confirmSelectedServices(names []string, confirmed bool) error {
+	if len(names) > util.MaxUnconfirmedSelectedServices && !confirmed {
+		return fmt.Errorf("%d services matched, more than %d services are only exchanged with '--yes'",
+			len(names), util.MaxUnconfirmedSelectedServices)
+	}
+	return nil
+}
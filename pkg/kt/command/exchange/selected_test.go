@@ -0,0 +1,24 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseServiceSelector(t *testing.T) {
+	labels, err := parseServiceSelector("team=payments,tier=backend")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "payments", "tier": "backend"}, labels)
+	for _, input := range []string{"", "team", "team=a,tier", "=a"} {
+		_, err = parseServiceSelector(input)
+		require.Error(t, err, "'%s' should be invalid", input)
+	}
+}
+
+func Test_confirmSelectedServices(t *testing.T) {
+	require.NoError(t, confirmSelectedServices([]string{"a", "b"}, false))
+	many := []string{"a", "b", "c", "d", "e", "f"}
+	require.Error(t, confirmSelectedServices(many, false))
+	require.NoError(t, confirmSelectedServices(many, true))
+}
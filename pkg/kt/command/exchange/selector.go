@@ -4,37 +4,48 @@ import (
 	"fmt"
 	"github.com/alibaba/kt-connect/pkg/kt/command/general"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
 	"strings"
 )
 
-func BySelector(resourceName string) error {
+func BySelector(resourceName string) (*general.SetupResult, error) {
 	// Get service to exchange
 	svc, err := general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if port := util.FindInvalidRemotePort(opt.Get().Exchange.Expose, general.GetTargetPorts(svc)); port != "" {
-		return fmt.Errorf("target port %s not exists in service %s", port, svc.Name)
+		return nil, fmt.Errorf("target port %s not exists in service %s", port, svc.Name)
 	}
 
 	// Lock service to avoid conflict, must be first step
 	svc, err = general.LockService(svc.Name, opt.Get().Global.Namespace, 0);
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer general.UnlockService(svc.Name, opt.Get().Global.Namespace)
 
-	if svc.Annotations != nil && svc.Annotations[util.KtSelector] != "" {
-		if svc.Spec.Selector[util.KtRole] == util.RoleExchangeShadow {
-			return fmt.Errorf("service '%s' is already exchanging by another user%s, cannot apply exchange",
-				svc.Name, general.GetOccupiedUser(svc.Spec.Selector))
-		} else if svc.Spec.Selector[util.KtRole] == util.RoleRouter {
-			return fmt.Errorf("another user is meshing service '%s', cannot apply exchange", svc.Name)
-		} else {
-			log.Warn().Msgf("Service '%s' has %s annotation, but either selecting shadow or router pod", svc.Name, util.KtSelector)
-			return fmt.Errorf("service '%s' in invalid status, please manually remove %s annotation before exchange", svc.Name, util.KtSelector)
+	if err = checkServiceNotOccupied(svc); err != nil {
+		return nil, err
+	}
+	if len(svc.Spec.Selector) == 0 {
+		if opt.Get().Exchange.PassthroughPorts {
+			return nil, fmt.Errorf("service '%s' has no selector, --passthroughPorts is not supported", svc.Name)
+		}
+		log.Info().Msgf("Service %s has no selector, redirecting its mesh routes", svc.Name)
+		if err = byRouteRedirect(svc); err != nil {
+			return nil, err
+		}
+		return general.NewSetupResult([]string{svc.Name}, util.ExchangeModeSelector, opt.Get().Exchange.Expose), nil
+	}
+	targetPorts := general.GetTargetPorts(svc)
+	exposePorts := opt.Get().Exchange.Expose
+	if opt.Get().Exchange.PassthroughPorts {
+		if exposePorts, err = passthroughForService(svc, targetPorts); err != nil {
+			return nil, err
 		}
 	}
 
@@ -44,19 +55,84 @@ func BySelector(resourceName string) error {
 		util.KtRole:   util.RoleExchangeShadow,
 		util.KtTarget: util.RandomString(20),
 	}
+	if key := opt.Get().Exchange.SharedShadow; key != "" {
+		shadowName, shadowLabels = sharedShadowOf(key)
+		if err = checkSharedShadowPorts(svc, shadowName, shadowLabels); err != nil {
+			return nil, err
+		}
+	}
 	annotation := map[string]string{
 		util.KtConfig: fmt.Sprintf("service=%s", svc.Name),
 	}
-	if err = general.CreateShadowAndInbound(shadowName, opt.Get().Exchange.Expose,
-		shadowLabels, annotation, general.GetTargetPorts(svc)); err != nil {
-		return err
+	if err = general.CreateShadowAndInbound(shadowName, exposePorts,
+		shadowLabels, annotation, targetPorts); err != nil {
+		return nil, err
+	}
+
+	if opt.Get().Exchange.FallbackOnOverload {
+		setupOverloadFallback(svc.Spec.Selector)
+	}
+	if LocalReadinessEnabled() {
+		setupReadinessFallback(svc.Spec.Selector)
+	}
+	if opt.Get().Exchange.Ramp != "" {
+		if err = setupRamp(svc.Name, svc.Spec.Selector); err != nil {
+			return nil, err
+		}
 	}
 
 	// Let target service select shadow pod
 	opt.Store.Origin = svc.Name
 	if err = general.UpdateServiceSelector(svc.Name, opt.Get().Global.Namespace, shadowLabels); err != nil {
-		return err
+		return nil, err
+	}
+	if opt.Get().Exchange.Ramp != "" && !opt.Get().Global.DryRun {
+		go startRamp()
 	}
 
+	return general.NewSetupResult([]string{svc.Name}, util.ExchangeModeSelector, exposePorts), nil
+}
+
+// checkServiceNotOccupied make sure service is not being exchanged or meshed
+func checkServiceNotOccupied(svc *coreV1.Service) error {
+	if svc.Annotations != nil && svc.Annotations[util.KtSelector] != "" {
+		if svc.Spec.Selector[util.KtRole] == util.RoleExchangeShadow {
+			return fmt.Errorf("service '%s' is already exchanging by another user%s, cannot apply exchange",
+				svc.Name, general.GetOccupiedUser(svc.Spec.Selector))
+		} else if svc.Spec.Selector[util.KtRole] == util.RoleRouter {
+			return fmt.Errorf("another user is meshing service '%s', cannot apply exchange", svc.Name)
+		} else if len(svc.Spec.Selector) == 0 {
+			return fmt.Errorf("service '%s' is already exchanging by another user without shadow pod, cannot apply exchange",
+				svc.Name)
+		} else {
+			log.Warn().Msgf("Service '%s' has %s annotation, but either selecting shadow or router pod", svc.Name, util.KtSelector)
+			return fmt.Errorf("service '%s' in invalid status, please manually remove %s annotation before exchange", svc.Name, util.KtSelector)
+		}
+	}
 	return nil
 }
+
+// setupReadinessFallback let connections go to original pods while local app is not ready
+func setupReadinessFallback(selector map[string]string) {
+	hosts, err := getRunningPodIps(selector)
+	if err != nil || len(hosts) == 0 {
+		log.Warn().Msgf("No running original pod, requests will be rejected while local app is not ready")
+		return
+	}
+	sshchannel.SetReadinessFallback(hosts)
+}
+
+// setupOverloadFallback let connections shed by local rate limit go to original pods
+func setupOverloadFallback(selector map[string]string) {
+	hosts, err := getRunningPodIps(selector)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to get original pods, shed connections will be dropped")
+		return
+	}
+	if len(hosts) == 0 {
+		log.Warn().Msgf("No running original pod, shed connections will be dropped")
+		return
+	}
+	log.Info().Msgf("Connections shed by local rate limit will fallback to original pods %v", hosts)
+	sshchannel.SetOverloadFallback(hosts)
+}
@@ -0,0 +1,76 @@
+package exchange
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	coreV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// sharedShadowPrefix name prefix of shadow pod shared by exchanges with same key
+const sharedShadowPrefix = "kt-exchange-shared-"
+
+// isValidSharedKey key is used as part of pod name and label value
+func isValidSharedKey(key string) bool {
+	ok, err := regexp.MatchString("^[a-z0-9]([a-z0-9-]{0,38}[a-z0-9])?$", key)
+	return err == nil && ok
+}
+
+// sharedShadowOf name and labels of shadow pod shared by exchanges with specified key
+func sharedShadowOf(key string) (string, map[string]string) {
+	return sharedShadowPrefix + key, map[string]string{
+		util.KtRole:   util.RoleExchangeShadow,
+		util.KtTarget: sharedShadowPrefix + key,
+	}
+}
+
+// checkSharedShadowPorts make sure service has no target port already used by other services selecting the shared shadow,
+// since connections to all of them arrive at the same pod
+func checkSharedShadowPorts(svc *coreV1.Service, shadowName string, shadowLabels map[string]string) error {
+	if _, err := cluster.Ins().GetPod(shadowName, opt.Get().Global.Namespace); k8sErrors.IsNotFound(err) {
+		// first exchange of the key, shadow pod will be created with ports of this service
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, p := range svc.Spec.Ports {
+		if p.TargetPort.Type == intstr.String {
+			return fmt.Errorf("target port '%s' of service %s is named, which cannot be resolved by existing shared shadow pod %s",
+				p.TargetPort.StrVal, svc.Name, shadowName)
+		}
+	}
+	svcs, err := cluster.Ins().GetServicesBySelector(shadowLabels, opt.Get().Global.Namespace)
+	if err != nil {
+		return err
+	}
+	targetPorts := general.GetTargetPorts(svc)
+	for _, other := range svcs {
+		if other.Name == svc.Name {
+			continue
+		}
+		if overlapped := overlappedPorts(targetPorts, general.GetTargetPorts(&other)); len(overlapped) > 0 {
+			return fmt.Errorf("port %v of service %s is already used by service %s in shared shadow pod %s",
+				overlapped, svc.Name, other.Name, shadowName)
+		}
+	}
+	return nil
+}
+
+// overlappedPorts ports exist in both port sets, sorted
+func overlappedPorts(ports, others map[int]string) []int {
+	overlapped := make([]int, 0)
+	for p := range ports {
+		if _, exists := others[p]; exists {
+			overlapped = append(overlapped, p)
+		}
+	}
+	sort.Ints(overlapped)
+	return overlapped
+}
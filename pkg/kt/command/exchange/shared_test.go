@@ -0,0 +1,22 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_isValidSharedKey(t *testing.T) {
+	for _, key := range []string{"a", "team-a", "order2pay", "0-1"} {
+		require.True(t, isValidSharedKey(key), "'%s' should be valid", key)
+	}
+	for _, key := range []string{"", "-a", "a-", "Team", "a_b", "a.b", "a1234567890123456789012345678901234567890"} {
+		require.False(t, isValidSharedKey(key), "'%s' should be invalid", key)
+	}
+}
+
+func Test_overlappedPorts(t *testing.T) {
+	require.Empty(t, overlappedPorts(map[int]string{80: "kt-80"}, map[int]string{8080: "kt-8080"}))
+	require.Equal(t, []int{80, 443}, overlappedPorts(map[int]string{443: "https", 80: "kt-80", 90: "kt-90"},
+		map[int]string{80: "kt-80", 443: "kt-443", 8080: "kt-8080"}))
+}
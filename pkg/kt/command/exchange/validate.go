@@ -0,0 +1,259 @@
+package exchange
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// CheckTarget verify target resource exists and contains all ports to expose, without changing anything
+func CheckTarget(resourceName string) error {
+	namespace := opt.Get().Global.Namespace
+	switch opt.Get().Exchange.Mode {
+	case util.ExchangeModeEphemeral:
+		pods, err := getPodsOfResource(resourceName, namespace)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			return fmt.Errorf("no pod of '%s' to exchange", resourceName)
+		}
+		return nil
+	case util.ExchangeModeScale:
+		workload, err := general.GetWorkloadByResourceName(resourceName, namespace)
+		if err != nil {
+			return err
+		}
+		// kind decides permission required for scaling
+		opt.Store.OriginKind = workload.Kind
+		return general.CheckScalable(workload)
+	}
+	if resourceType, name, err := general.ParseResourceName(resourceName); err == nil && resourceType == general.KindPod {
+		_, svcs, err2 := getPodWithServices(name, namespace)
+		if err2 != nil {
+			return err2
+		}
+		if port := util.FindInvalidRemotePort(opt.Get().Exchange.Expose, general.GetTargetPorts(&svcs[0])); port != "" {
+			return fmt.Errorf("target port %s not exists in service %s", port, svcs[0].Name)
+		}
+		return nil
+	}
+	svc, err := general.GetServiceByResourceName(resourceName, namespace)
+	if err != nil {
+		return err
+	}
+	if port := util.FindInvalidRemotePort(opt.Get().Exchange.Expose, general.GetTargetPorts(svc)); port != "" {
+		return fmt.Errorf("target port %s not exists in service %s", port, svc.Name)
+	}
+	return nil
+}
+
+// CheckTargetKinds verify type of each target is supported by exchange mode, before looking up any resource
+func CheckTargetKinds(resourceNames []string) error {
+	for _, resourceName := range resourceNames {
+		resourceType, name, err := general.ParseResourceName(resourceName)
+		if err != nil {
+			return err
+		}
+		if resourceType != general.KindService && resourceType != general.KindPod && !general.IsWorkloadKind(resourceType) {
+			return fmt.Errorf("invalid resource type '%s', supported are %s, %s, %s, %s and %s", resourceType,
+				general.KindService, general.KindPod, general.KindDeployment, general.KindStatefulSet, general.KindDaemonSet)
+		}
+		if resourceType == general.KindDaemonSet && opt.Get().Exchange.Mode == util.ExchangeModeScale {
+			return general.CheckScalable(&general.Workload{Kind: resourceType, Name: name})
+		}
+		if resourceType == general.KindPod {
+			if err = checkPodTargetOptions(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkPodTargetOptions verify options of exchanging single pod, whose address is replaced in service endpoints
+func checkPodTargetOptions() error {
+	ex := opt.Get().Exchange
+	if ex.Mode == util.ExchangeModeScale {
+		return fmt.Errorf("exchanging single pod is not supported in %s mode", util.ExchangeModeScale)
+	}
+	if ex.Mode != util.ExchangeModeSelector {
+		return nil
+	}
+	if ex.NoShadow || ex.SharedShadow != "" || ex.PassthroughPorts || ex.Ramp != "" || ex.FallbackOnOverload ||
+		LocalReadinessEnabled() {
+		return fmt.Errorf("--noShadow, --sharedShadow, --passthroughPorts, --ramp, --fallbackOnOverload and " +
+			"local readiness check are not supported when exchanging single pod")
+	}
+	return nil
+}
+
+// CheckRateLimit verify local rate limit options
+func CheckRateLimit() error {
+	ex := opt.Get().Exchange
+	if ex.LocalRateLimit < 0 {
+		return fmt.Errorf("local rate limit should not be negative, but got %d", ex.LocalRateLimit)
+	}
+	if ex.OverloadAction != util.OverloadActionQueue && ex.OverloadAction != util.OverloadActionShed {
+		return fmt.Errorf("invalid overload action '%s', supportted are %s, %s", ex.OverloadAction,
+			util.OverloadActionQueue, util.OverloadActionShed)
+	}
+	if ex.FallbackOnOverload {
+		if ex.LocalRateLimit == 0 || ex.OverloadAction != util.OverloadActionShed {
+			return fmt.Errorf("--fallbackOnOverload requires --localRateLimit with --overloadAction %s",
+				util.OverloadActionShed)
+		}
+		if ex.Mode != util.ExchangeModeSelector {
+			return fmt.Errorf("--fallbackOnOverload is only supported in %s mode", util.ExchangeModeSelector)
+		}
+	}
+	return nil
+}
+
+// CheckNoShadow verify options of exchange without shadow pod
+func CheckNoShadow() error {
+	ex := opt.Get().Exchange
+	if !ex.NoShadow {
+		return nil
+	}
+	if ex.Mode != util.ExchangeModeSelector {
+		return fmt.Errorf("--noShadow is only supported in %s mode", util.ExchangeModeSelector)
+	}
+	if ex.LocalIp != "" && net.ParseIP(ex.LocalIp) == nil {
+		return fmt.Errorf("invalid local ip '%s'", ex.LocalIp)
+	}
+	if ex.LocalRateLimit > 0 || ex.ExecProbe || opt.Get().Global.PreserveSourceIp != "" {
+		return fmt.Errorf("--localRateLimit, --execProbe and --preserveSourceIp require shadow pod, " +
+			"cannot be used with --noShadow")
+	}
+	return nil
+}
+
+// CheckPassthrough verify options of passing not exchanged ports through to original pods
+func CheckPassthrough() error {
+	ex := opt.Get().Exchange
+	if !ex.PassthroughPorts {
+		return nil
+	}
+	if ex.Mode != util.ExchangeModeSelector && ex.Mode != util.ExchangeModeScale {
+		return fmt.Errorf("--passthroughPorts is only supported in %s and %s mode",
+			util.ExchangeModeSelector, util.ExchangeModeScale)
+	}
+	if ex.NoShadow {
+		return fmt.Errorf("--passthroughPorts requires shadow pod, cannot be used with --noShadow")
+	}
+	return nil
+}
+
+// CheckApproval verify options of approval webhook
+func CheckApproval() error {
+	ex := opt.Get().Exchange
+	if ex.ApprovalWebhook == "" {
+		if ex.SkipApproval {
+			return fmt.Errorf("--skipApproval is only applicable when --approvalWebhook is specified")
+		}
+		return nil
+	}
+	if u, err := url.ParseRequestURI(ex.ApprovalWebhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		// value not printed, it may come from a secret reference
+		return fmt.Errorf("approval webhook should be a http or https url")
+	}
+	if ex.ApprovalTimeout <= 0 {
+		return fmt.Errorf("approval timeout should be positive, but got %d", ex.ApprovalTimeout)
+	}
+	return nil
+}
+
+// CheckTlsTerminate verify options of tls termination
+func CheckTlsTerminate() error {
+	ex := opt.Get().Exchange
+	if ex.TlsTerminate && ex.NoShadow {
+		return fmt.Errorf("--tlsTerminate requires shadow pod, cannot be used with --noShadow")
+	}
+	return general.CheckTlsOptions(ex.TlsTerminate, ex.TlsCert, ex.TlsKey)
+}
+
+// CheckSharedShadow verify options of sharing shadow pod with other exchanges
+func CheckSharedShadow() error {
+	ex := opt.Get().Exchange
+	if ex.SharedShadow == "" {
+		return nil
+	}
+	if ex.Mode != util.ExchangeModeSelector {
+		return fmt.Errorf("--sharedShadow is only supported in %s mode", util.ExchangeModeSelector)
+	}
+	if ex.NoShadow || ex.PassthroughPorts || opt.Get().Global.UseShadowDeployment {
+		return fmt.Errorf("--sharedShadow cannot be used with --noShadow, --passthroughPorts or --useShadowDeployment")
+	}
+	if !isValidSharedKey(ex.SharedShadow) {
+		return fmt.Errorf("invalid shared shadow key '%s', only lowercase letters, digits and '-' are allowed", ex.SharedShadow)
+	}
+	return nil
+}
+
+// CheckLocalReadiness verify options of local app readiness check
+func CheckLocalReadiness() error {
+	ex := opt.Get().Exchange
+	if ex.LocalReadyPath == "" {
+		if LocalReadinessEnabled() && ex.LocalReadyTimeout <= 0 {
+			return fmt.Errorf("local ready timeout should be positive, but got %d", ex.LocalReadyTimeout)
+		}
+		return nil
+	}
+	if !strings.HasPrefix(ex.LocalReadyPath, "/") {
+		return fmt.Errorf("local ready path should start with '/', but got '%s'", ex.LocalReadyPath)
+	}
+	if _, err := url.ParseRequestURI(ex.LocalReadyPath); err != nil {
+		return fmt.Errorf("invalid local ready path '%s': %s", ex.LocalReadyPath, err)
+	}
+	if ex.LocalReadyTimeout <= 0 {
+		return fmt.Errorf("local ready timeout should be positive, but got %d", ex.LocalReadyTimeout)
+	}
+	if ex.NoShadow {
+		return fmt.Errorf("--localReadyPath requires shadow pod, cannot be used with --noShadow")
+	}
+	return nil
+}
+
+// Permissions kubernetes permissions required by current exchange mode
+func Permissions(resourceNames []string) []cluster.PermissionRule {
+	switch opt.Get().Exchange.Mode {
+	case util.ExchangeModeEphemeral:
+		return []cluster.PermissionRule{
+			{Verb: "update", Resource: "pods", Subresource: "ephemeralcontainers"},
+			{Verb: "create", Resource: "pods", Subresource: "portforward"},
+		}
+	case util.ExchangeModeScale:
+		return append(general.ShadowPermissions(),
+			cluster.PermissionRule{Verb: "update", Group: "apps", Resource: scaledResource()})
+	default:
+		if opt.Get().Exchange.NoShadow {
+			return []cluster.PermissionRule{
+				{Verb: "update", Resource: "services"},
+				{Verb: "update", Resource: "endpoints"},
+				{Verb: "create", Resource: "pods"},
+				{Verb: "create", Resource: "pods", Subresource: "exec"},
+			}
+		}
+		if resourceType, _, err := general.ParseResourceName(resourceNames[0]); err == nil && resourceType == general.KindPod {
+			return append(general.ShadowPermissions(),
+				cluster.PermissionRule{Verb: "update", Resource: "services"},
+				cluster.PermissionRule{Verb: "update", Resource: "endpoints"})
+		}
+		return append(general.ShadowPermissions(),
+			cluster.PermissionRule{Verb: "update", Resource: "services"})
+	}
+}
+
+// scaledResource resource of workload scaled in scale mode, only known after target checked
+func scaledResource() string {
+	if opt.Store.OriginKind == general.KindStatefulSet {
+		return "statefulsets"
+	}
+	return "deployments"
+}
@@ -40,7 +40,7 @@ func NewForwardCommand() *cobra.Command {
 }
 
 func Forward(args []string) error {
-	ch, err := general.SetupProcess(util.ComponentForward)
+	ctx, ch, err := general.SetupProcess(util.ComponentForward)
 	if err != nil {
 		return err
 	}
@@ -52,7 +52,9 @@ func Forward(args []string) error {
 	}
 
 	if strings.Contains(target, ".") {
-		err = forward.RedirectAddress(target, localPort, remotePort)
+		err = general.RunSetup(ctx, func() error {
+			return forward.RedirectAddress(target, localPort, remotePort)
+		})
 		if err != nil {
 			return err
 		}
@@ -60,7 +62,10 @@ func Forward(args []string) error {
 		log.Info().Msgf(" Now you can access to '%s:%d' via 'localhost:%d'", target, remotePort, localPort)
 		log.Info().Msg("---------------------------------------------------------------")
 	} else {
-		localPort, err = forward.RedirectService(target, localPort, remotePort)
+		err = general.RunSetup(ctx, func() (setupErr error) {
+			localPort, setupErr = forward.RedirectService(target, localPort, remotePort)
+			return
+		})
 		if err != nil {
 			return err
 		}
@@ -74,8 +79,7 @@ func Forward(args []string) error {
 	}
 
 	// watch background process, clean the workspace and exit if background process occur exception
-	s := <-ch
-	log.Info().Msgf("Terminal Signal is %s", s)
+	general.WaitForStop(ctx, ch)
 	return nil
 }
 
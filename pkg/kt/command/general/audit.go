@@ -0,0 +1,234 @@
+package general
+
+import (
+	"encoding/json"
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"strings"
+	"time"
+)
+
+// AuditReport record of cluster changes made by current process, and whether they are restored
+type AuditReport struct {
+	Session       string          `json:"session"`
+	Operator      string          `json:"operator"`
+	KubeContext   string          `json:"kubeContext,omitempty"`
+	Component     string          `json:"component"`
+	Namespace     string          `json:"namespace"`
+	Mode          string          `json:"mode"`
+	StartedAt     time.Time       `json:"startedAt"`
+	FinishedAt    time.Time       `json:"finishedAt"`
+	Changes       []AuditedChange `json:"changes"`
+	Verified      bool            `json:"verified"`
+	Discrepancies []string        `json:"discrepancies"`
+}
+
+// AuditedChange one resource changed during session
+type AuditedChange struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Change   string `json:"change"`
+	Restored bool   `json:"restored"`
+}
+
+// auditCheck verify a change is restored, return description of discrepancy if not
+type auditCheck func() string
+
+var auditStartedAt = time.Now()
+var routeRestoreCheck func() []string
+
+// SetRouteRestoreCheck register verification of redirected mesh routes, which cannot be done in general package
+func SetRouteRestoreCheck(check func() []string) {
+	routeRestoreCheck = check
+}
+
+// writeAuditReport verify cluster is restored after exchange stopped, and write the report to audit file
+func writeAuditReport() {
+	auditFile := opt.Get().Exchange.AuditFile
+	if auditFile == "" || opt.Store.Component != util.ComponentExchange {
+		return
+	}
+	report := &AuditReport{
+		Session:       opt.Store.Session,
+		Operator:      util.GetLocalUserName(),
+		KubeContext:   opt.Get().Global.Context,
+		Component:     opt.Store.Component,
+		Namespace:     opt.Get().Global.Namespace,
+		Mode:          opt.Get().Exchange.Mode,
+		StartedAt:     auditStartedAt,
+		Changes:       []AuditedChange{},
+		Discrepancies: []string{},
+	}
+	for _, c := range exchangeChanges() {
+		discrepancy := c.check()
+		c.change.Restored = discrepancy == ""
+		if discrepancy != "" {
+			report.Discrepancies = append(report.Discrepancies, discrepancy)
+		}
+		report.Changes = append(report.Changes, c.change)
+	}
+	report.Verified = len(report.Discrepancies) == 0
+	report.FinishedAt = time.Now()
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		err = util.WriteFileAtomic(auditFile, append(data, '\n'), 0644)
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to write audit report to %s", auditFile)
+	} else if report.Verified {
+		log.Info().Msgf("Cluster restore verified, audit report written to %s", auditFile)
+	} else {
+		log.Warn().Msgf("Found %d discrepancies after cleanup, audit report written to %s",
+			len(report.Discrepancies), auditFile)
+	}
+}
+
+type checkedChange struct {
+	change AuditedChange
+	check  auditCheck
+}
+
+// exchangeChanges list changes applied by exchange according to runtime store
+func exchangeChanges() []checkedChange {
+	namespace := opt.Get().Global.Namespace
+	var changes []checkedChange
+	if opt.Store.Origin != "" {
+		if opt.Get().Exchange.Mode == util.ExchangeModeScale {
+			change := fmt.Sprintf("scaled from %d to 0", opt.Store.Replicas)
+			expected := opt.Store.Replicas
+			if !opt.Get().Exchange.RestoreOnExit {
+				change += ", kept down on exit"
+				expected = 0
+			}
+			changes = append(changes, checkedChange{
+				AuditedChange{KindTitle(opt.Store.OriginKind), opt.Store.Origin, change, false},
+				func() string { return verifyWorkloadReplicas(opt.Store.Origin, namespace, expected) },
+			})
+		} else if opt.Get().Exchange.Mode == util.ExchangeModeSelector {
+			change := "selector pointed to shadow pod"
+			if opt.Get().Exchange.NoShadow {
+				change = "selector removed and endpoints pointed to local"
+			}
+			for _, name := range strings.Split(opt.Store.Origin, ",") {
+				origin := name
+				changes = append(changes, checkedChange{
+					AuditedChange{"Service", origin, change, false},
+					func() string { return verifyServiceSelector(origin, namespace) },
+				})
+			}
+		}
+	}
+	if opt.Store.Passthrough != "" {
+		changes = append(changes, checkedChange{
+			AuditedChange{"Pod", opt.Store.Passthrough, "created", false},
+			func() string { return verifyPodRemoved(opt.Store.Passthrough, namespace) },
+		})
+	}
+	if opt.Store.Shadow != "" {
+		for _, name := range strings.Split(opt.Store.Shadow, ",") {
+			shadow := name
+			if opt.Get().Exchange.Mode == util.ExchangeModeEphemeral {
+				changes = append(changes, checkedChange{
+					AuditedChange{"Pod", shadow, "ephemeral container injected", false},
+					func() string { return verifyPodRemoved(shadow, namespace) },
+				})
+				continue
+			}
+			changes = append(changes, checkedChange{
+				AuditedChange{"ConfigMap", shadow, "created", false},
+				func() string { return verifyConfigMapRemoved(shadow, namespace) },
+			})
+			if opt.Get().Global.UseShadowDeployment {
+				changes = append(changes, checkedChange{
+					AuditedChange{"Deployment", shadow, "created", false},
+					func() string { return verifyDeploymentRemoved(shadow, namespace) },
+				})
+			} else {
+				changes = append(changes, checkedChange{
+					AuditedChange{"Pod", shadow, "created", false},
+					func() string { return verifyPodRemoved(shadow, namespace) },
+				})
+			}
+		}
+	}
+	if opt.Store.Service != "" {
+		changes = append(changes, checkedChange{
+			AuditedChange{"Service", opt.Store.Service, "created", false},
+			func() string { return verifyServiceRemoved(opt.Store.Service, namespace) },
+		})
+	}
+	if opt.Store.RoutingBackend != "" {
+		changes = append(changes, checkedChange{
+			AuditedChange{"Route", opt.Store.RoutingBackend, "rules redirected to shadow service", false},
+			verifyRoutesRestored,
+		})
+	}
+	return changes
+}
+
+func verifyRoutesRestored() string {
+	if routeRestoreCheck == nil {
+		return ""
+	}
+	return strings.Join(routeRestoreCheck(), "; ")
+}
+
+func verifyWorkloadReplicas(name, namespace string, replicas int32) string {
+	workload, err := GetWorkload(kindOfOrigin(), name, namespace)
+	if err != nil {
+		return fmt.Sprintf("%s %s cannot be fetched: %s", kindOfOrigin(), name, err)
+	}
+	if workload.Replicas != replicas {
+		return fmt.Sprintf("%s %s is not scaled back to %d replicas", kindOfOrigin(), name, replicas)
+	}
+	return ""
+}
+
+func verifyServiceSelector(name, namespace string) string {
+	svc, err := cluster.Ins().GetService(name, namespace)
+	if err != nil {
+		return fmt.Sprintf("service %s cannot be fetched: %s", name, err)
+	}
+	if svc.Annotations != nil && svc.Annotations[util.KtSelector] != "" {
+		return fmt.Sprintf("service %s still has %s annotation", name, util.KtSelector)
+	}
+	if _, exists := svc.Spec.Selector[util.KtRole]; exists {
+		return fmt.Sprintf("service %s still selects kt pods", name)
+	}
+	return ""
+}
+
+func verifyPodRemoved(name, namespace string) string {
+	pod, err := cluster.Ins().GetPod(name, namespace)
+	return verifyRemoved("pod", name, err, err == nil && pod.DeletionTimestamp != nil)
+}
+
+func verifyDeploymentRemoved(name, namespace string) string {
+	app, err := cluster.Ins().GetDeployment(name, namespace)
+	return verifyRemoved("deployment", name, err, err == nil && app.DeletionTimestamp != nil)
+}
+
+func verifyConfigMapRemoved(name, namespace string) string {
+	cm, err := cluster.Ins().GetConfigMap(name, namespace)
+	return verifyRemoved("configmap", name, err, err == nil && cm.DeletionTimestamp != nil)
+}
+
+func verifyServiceRemoved(name, namespace string) string {
+	svc, err := cluster.Ins().GetService(name, namespace)
+	return verifyRemoved("service", name, err, err == nil && svc.DeletionTimestamp != nil)
+}
+
+// verifyRemoved resource is considered removed when not found or being deleted
+func verifyRemoved(kind, name string, err error, terminating bool) string {
+	if k8sErrors.IsNotFound(err) || terminating {
+		return ""
+	} else if err != nil {
+		return fmt.Sprintf("%s %s cannot be fetched: %s", kind, name, err)
+	}
+	return fmt.Sprintf("%s %s still exists", kind, name)
+}
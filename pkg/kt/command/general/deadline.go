@@ -0,0 +1,57 @@
+package general
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/rs/zerolog/log"
+	"os"
+	"time"
+)
+
+// rootCtx context of whole command, carries the deadline specified by '--deadline' option
+var rootCtx = context.Background()
+var cancelRootCtx context.CancelFunc
+
+// DeadlineExceeded whether current command is stopped by deadline
+func DeadlineExceeded() bool {
+	return errors.Is(rootCtx.Err(), context.DeadlineExceeded)
+}
+
+// setupDeadline let root context of current command expire after deadline, regardless of which phase it is in
+func setupDeadline(deadline time.Duration) {
+	rootCtx, cancelRootCtx = context.WithTimeout(context.Background(), deadline)
+	log.Info().Msgf("Command will be stopped after %s", deadline)
+}
+
+// RunSetup run setup phase of command with api requests bounded by ctx, so that setup aborts itself once ctx is done,
+// requests made after setup returned, e.g. by cleanup, are not affected
+func RunSetup(ctx context.Context, setup func() error) error {
+	cluster.SetRequestContext(ctx)
+	defer cluster.SetRequestContext(nil)
+	err := setup()
+	if err != nil && DeadlineExceeded() {
+		return fmt.Errorf("setup aborted, deadline %s exceeded", opt.Get().Global.Deadline)
+	}
+	return err
+}
+
+// WaitForStop block serving loop until terminal signal received or deadline of command reached
+func WaitForStop(ctx context.Context, ch chan os.Signal) {
+	select {
+	case s := <-ch:
+		log.Info().Msgf("Terminal Signal is %s", s)
+	case <-ctx.Done():
+		log.Warn().Msgf("Deadline %s exceeded, shutting down", opt.Get().Global.Deadline)
+	}
+}
+
+// parseDeadline parse value of '--deadline' option
+func parseDeadline() (time.Duration, error) {
+	if opt.Get().Global.Deadline == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(opt.Get().Global.Deadline)
+}
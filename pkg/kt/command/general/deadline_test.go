@@ -0,0 +1,29 @@
+package general
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWaitForStop(t *testing.T) {
+	ch := make(chan os.Signal, 1)
+	ch <- os.Interrupt
+	WaitForStop(context.Background(), ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	WaitForStop(ctx, make(chan os.Signal))
+	require.Error(t, ctx.Err())
+}
+
+func TestRunSetup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, RunSetup(ctx, func() error { return nil }))
+	cancel()
+	err := RunSetup(ctx, func() error { return ctx.Err() })
+	require.Equal(t, context.Canceled, err)
+	require.False(t, DeadlineExceeded())
+}
@@ -0,0 +1,37 @@
+package general
+
+import (
+	"time"
+
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/rs/zerolog/log"
+)
+
+// drainLogInterval interval of reporting remaining connections while draining
+const drainLogInterval = 3 * time.Second
+
+// WaitConnectionsDrained wait up to specified seconds for connections forwarded to local to finish,
+// must be called before traffic redirect reverted, 0 means not wait
+func WaitConnectionsDrained(seconds int) {
+	if seconds <= 0 {
+		return
+	}
+	deadline := time.Now().Add(time.Duration(seconds) * time.Second)
+	lastReport := time.Time{}
+	for {
+		count := sshchannel.ActiveConnectionCount()
+		if count == 0 {
+			log.Info().Msgf("All connections drained")
+			return
+		}
+		if time.Now().After(deadline) {
+			log.Warn().Msgf("Drain timeout after %d seconds, %d connections still active", seconds, count)
+			return
+		}
+		if time.Since(lastReport) >= drainLogInterval {
+			log.Info().Msgf("Draining, %d connections still active ...", count)
+			lastReport = time.Now()
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
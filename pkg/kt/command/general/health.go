@@ -0,0 +1,112 @@
+package general
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/rs/zerolog/log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// healthServer http server of '--healthAddr', nil if not enabled
+var healthServer *http.Server
+
+// startedAt time current command started serving
+var startedAt time.Time
+
+// HealthStatus response body of '/healthz'
+type HealthStatus struct {
+	Status        string `json:"status"`
+	Component     string `json:"component"`
+	Namespace     string `json:"namespace"`
+	Session       string `json:"session"`
+	UptimeSeconds int64  `json:"uptimeSeconds"`
+	Reconnects    int64  `json:"reconnects"`
+}
+
+// startHealthServer serve health and metrics of current command on specified address, do nothing if address is empty
+func startHealthServer(addr string) error {
+	startedAt = time.Now()
+	if addr == "" {
+		return nil
+	}
+	// listen before serving, so that an occupied address fails the command at once
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen health address %s: %s", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/metrics", handleMetrics)
+	healthServer = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func(server *http.Server) {
+		if err2 := server.Serve(listener); err2 != nil && err2 != http.ErrServerClosed {
+			log.Warn().Err(err2).Msgf("Health server stopped unexpectedly")
+		}
+	}(healthServer)
+	log.Info().Msgf("Serving health status on http://%s/healthz", listener.Addr())
+	return nil
+}
+
+// stopHealthServer shutdown health server if started
+func stopHealthServer() {
+	if healthServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := healthServer.Shutdown(ctx); err != nil {
+		log.Debug().Err(err).Msgf("Failed to shutdown health server")
+	}
+	healthServer = nil
+}
+
+// currentHealth status of current command, unhealthy once ssh tunnel is lost
+func currentHealth() HealthStatus {
+	status := "ok"
+	if TunnelLost() {
+		status = "tunnel lost"
+	}
+	return HealthStatus{
+		Status:        status,
+		Component:     opt.Store.Component,
+		Namespace:     opt.Get().Global.Namespace,
+		Session:       opt.Store.Session,
+		UptimeSeconds: int64(time.Since(startedAt).Seconds()),
+		Reconnects:    sshchannel.ReconnectTotal(),
+	}
+}
+
+// handleHealthz response 200 when tunnel is alive, otherwise 503
+func handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	health := currentHealth()
+	w.Header().Set("Content-Type", "application/json")
+	if TunnelLost() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(health)
+}
+
+// handleMetrics response metrics in prometheus text format
+func handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	health := currentHealth()
+	up := 1
+	if TunnelLost() {
+		up = 0
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	label := fmt.Sprintf("{component=\"%s\",namespace=\"%s\",session=\"%s\"}", health.Component, health.Namespace,
+		health.Session)
+	_, _ = fmt.Fprintf(w, "# HELP kt_tunnel_up Whether ssh tunnel is alive.\n# TYPE kt_tunnel_up gauge\n")
+	_, _ = fmt.Fprintf(w, "kt_tunnel_up%s %d\n", label, up)
+	_, _ = fmt.Fprintf(w, "# HELP kt_uptime_seconds Seconds since command started.\n# TYPE kt_uptime_seconds gauge\n")
+	_, _ = fmt.Fprintf(w, "kt_uptime_seconds%s %d\n", label, health.UptimeSeconds)
+	_, _ = fmt.Fprintf(w, "# HELP kt_reconnect_attempts_total Reconnect attempts of ssh connections.\n# TYPE kt_reconnect_attempts_total counter\n")
+	_, _ = fmt.Fprintf(w, "kt_reconnect_attempts_total%s %d\n", label, health.Reconnects)
+	_, _ = fmt.Fprintf(w, "# HELP kt_reconnect_cycles_total Times the whole command was re-established.\n# TYPE kt_reconnect_cycles_total counter\n")
+	_, _ = fmt.Fprintf(w, "kt_reconnect_cycles_total%s %d\n", label, ReconnectCount())
+}
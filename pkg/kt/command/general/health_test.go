@@ -0,0 +1,42 @@
+package general
+
+import (
+	"encoding/json"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	opt.Store.Session = "abcdefghij"
+	recorder := httptest.NewRecorder()
+	handleHealthz(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var health HealthStatus
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &health))
+	require.Equal(t, "ok", health.Status)
+	recorder = httptest.NewRecorder()
+	handleMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Contains(t, recorder.Body.String(), "kt_tunnel_up{component=\"\",namespace=\"\",session=\"abcdefghij\"} 1\n")
+
+	atomic.StoreInt32(&tunnelLost, 1)
+	defer atomic.StoreInt32(&tunnelLost, 0)
+	recorder = httptest.NewRecorder()
+	handleHealthz(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	recorder = httptest.NewRecorder()
+	handleMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Contains(t, recorder.Body.String(), "kt_tunnel_up{component=\"\",namespace=\"\",session=\"abcdefghij\"} 0\n")
+}
+
+func TestStartHealthServer(t *testing.T) {
+	require.NoError(t, startHealthServer(""))
+	require.Nil(t, healthServer)
+	require.NoError(t, startHealthServer("127.0.0.1:0"))
+	require.NotNil(t, healthServer)
+	stopHealthServer()
+	require.Nil(t, healthServer)
+}
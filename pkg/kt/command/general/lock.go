@@ -2,6 +2,7 @@ package general
 
 import (
 	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
@@ -20,6 +21,10 @@ func LockService(serviceName, namespace string, times int) (*coreV1.Service, err
 	if err != nil {
 		return nil, err
 	}
+	if opt.Get().Global.DryRun {
+		// lock annotation is only meaningful for a running process
+		return svc, nil
+	}
 
 	if svc.Annotations == nil {
 		svc.Annotations = make(map[string]string)
@@ -40,6 +45,9 @@ func LockService(serviceName, namespace string, times int) (*coreV1.Service, err
 }
 
 func UnlockService(serviceName, namespace string) {
+	if opt.Get().Global.DryRun {
+		return
+	}
 	svc, err := cluster.Ins().GetService(serviceName, namespace)
 	if err != nil {
 		log.Warn().Err(err).Msgf("Failed to get service %s for unlock", serviceName)
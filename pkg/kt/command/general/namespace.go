@@ -0,0 +1,39 @@
+package general
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+)
+
+// envAllowedNamespaces environment variable of namespace allowlist, used when option is not set
+const envAllowedNamespaces = "KT_ALLOWED_NAMESPACES"
+
+// checkNamespaceAllowed refuse to work in namespace outside the allowlist, empty allowlist means no restriction
+func checkNamespaceAllowed() error {
+	allowed := getAllowedNamespaces()
+	if len(allowed) == 0 || util.Contains(allowed, opt.Get().Global.Namespace) {
+		return nil
+	}
+	return fmt.Errorf("namespace '%s' is not allowed by policy, ktctl is only permitted to modify namespace %s, "+
+		"please check the '--namespace' option or namespace of current kubeconfig context",
+		opt.Get().Global.Namespace, strings.Join(allowed, ", "))
+}
+
+// getAllowedNamespaces read allowlist from option (command line or config file) or environment variable
+func getAllowedNamespaces() []string {
+	value := opt.Get().Global.AllowedNamespaces
+	if value == "" {
+		value = os.Getenv(envAllowedNamespaces)
+	}
+	var namespaces []string
+	for _, ns := range strings.Split(value, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,26 @@
+package general
+
+import (
+	"testing"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkNamespaceAllowed(t *testing.T) {
+	opt.Get().Global.Namespace = "prod"
+	opt.Get().Global.AllowedNamespaces = ""
+	t.Setenv(envAllowedNamespaces, "")
+	require.NoError(t, checkNamespaceAllowed())
+
+	t.Setenv(envAllowedNamespaces, "dev, test")
+	require.Error(t, checkNamespaceAllowed())
+	opt.Get().Global.Namespace = "test"
+	require.NoError(t, checkNamespaceAllowed())
+
+	opt.Get().Global.AllowedNamespaces = "dev,prod"
+	require.Error(t, checkNamespaceAllowed())
+	opt.Get().Global.Namespace = "prod"
+	require.NoError(t, checkNamespaceAllowed())
+	opt.Get().Global.AllowedNamespaces = ""
+}
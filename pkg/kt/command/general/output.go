@@ -0,0 +1,99 @@
+package general
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+)
+
+// SetupResult summary of a successfully started command, returned to library caller and printed for tools wrapping ktctl
+type SetupResult struct {
+	Command       string   `json:"command"`
+	Mode          string   `json:"mode,omitempty"`
+	Namespace     string   `json:"namespace"`
+	Services      []string `json:"services"`
+	Expose        []string `json:"expose"`
+	Version       string   `json:"version,omitempty"`
+	ShadowPods    []string `json:"shadowPods"`
+	ShadowService string   `json:"shadowService,omitempty"`
+	RouterPod     string   `json:"routerPod,omitempty"`
+	SignalFile    string   `json:"signalFile"`
+	ControlPipe   string   `json:"controlPipe,omitempty"`
+	Pid           int      `json:"pid"`
+}
+
+// NewSetupResult generate setup result of target services, created resources are taken from runtime store
+func NewSetupResult(services []string, mode, expose string) *SetupResult {
+	result := &SetupResult{
+		Command:       opt.Store.Component,
+		Mode:          mode,
+		Namespace:     opt.Get().Global.Namespace,
+		Services:      make([]string, 0),
+		Expose:        make([]string, 0),
+		ShadowPods:    make([]string, 0),
+		ShadowService: opt.Store.Service,
+		RouterPod:     opt.Store.Router,
+		Pid:           os.Getpid(),
+	}
+	result.Services = append(result.Services, services...)
+	if expose != "" {
+		result.Expose = strings.Split(expose, ",")
+	}
+	if opt.Store.Component == util.ComponentMesh {
+		result.Version = opt.Store.Mesh
+	}
+	if opt.Store.Shadow != "" {
+		result.ShadowPods = strings.Split(opt.Store.Shadow, ",")
+	}
+	return result
+}
+
+// String brief description of setup result for logging
+func (r *SetupResult) String() string {
+	return fmt.Sprintf("services %v, shadow pods %v, exposed ports %v", r.Services, r.ShadowPods, r.Expose)
+}
+
+// IsJsonOutput whether result of command should be printed as json instead of hints for human
+func IsJsonOutput() bool {
+	return opt.Get().Global.Output == util.OutputJson
+}
+
+// CheckOutputFormat verify value of '--output' option
+func CheckOutputFormat() error {
+	if output := opt.Get().Global.Output; output != util.OutputText && output != util.OutputJson {
+		return fmt.Errorf("invalid output format '%s', supported are %s, %s", output, util.OutputText, util.OutputJson)
+	}
+	return nil
+}
+
+// PrintDryRunSummary log what would be set up, after manifests of resources to apply printed
+func PrintDryRunSummary(result *SetupResult) {
+	if result != nil {
+		log.Info().Msgf("Dry run of %s on %s", result.Command, result)
+	}
+	log.Info().Msg("Dry run finished, no change applied")
+}
+
+// PrintSetupResult log setup result, and print it to stdout as a single line of json in json output mode
+func PrintSetupResult(result *SetupResult, signalFile, pipeName string) {
+	if result == nil {
+		return
+	}
+	log.Debug().Msgf("Setup finished with %s", result)
+	if !IsJsonOutput() {
+		return
+	}
+	result.SignalFile = signalFile
+	result.ControlPipe = pipeName
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to generate json output")
+		return
+	}
+	fmt.Println(string(data))
+}
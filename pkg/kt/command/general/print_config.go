@@ -0,0 +1,121 @@
+package general
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"strings"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sourceFlag    = "flag"
+	sourceConfig  = "config"
+	sourceBuildIn = "build-in"
+	sourceDefault = "default"
+)
+
+// redactedValue placeholder of credential in printed option value
+const redactedValue = "REDACTED"
+
+// sensitiveKeywords option containing these words in name holds credential itself
+var sensitiveKeywords = []string{"password", "token", "credential"}
+
+// PrintConfig print options of global and current command group with the value finally used, and where the value came from,
+// in precedence order: command line flag, config file, build-in config, default value
+func PrintConfig(cmd *cobra.Command) {
+	config := parseConfigData(readConfigFile())
+	buildIn := map[string]map[string]string{}
+	if customize, exist := opt.GetCustomizeKtConfig(); exist {
+		buildIn = parseConfigData([]byte(customize))
+	}
+	groups := []string{"Global"}
+	if group := util.Capitalize(cmd.Name()); group != "Global" &&
+		reflect.ValueOf(opt.Get()).Elem().FieldByName(group).IsValid() {
+		groups = append(groups, group)
+	}
+	for _, group := range groups {
+		groupValue := reflect.ValueOf(opt.Get()).Elem().FieldByName(group).Elem()
+		groupName := util.DashSeparated(group)
+		for i := 0; i < groupValue.NumField(); i++ {
+			field := groupValue.Type().Field(i)
+			itemName := util.DashSeparated(field.Name)
+			source := optionSource(cmd.Flags().Lookup(util.UnCapitalize(field.Name)), config, buildIn, groupName, itemName)
+			fmt.Printf("%s.%s = %v  (%s)\n", groupName, itemName,
+				redactOptionValue(field.Name, fmt.Sprint(groupValue.Field(i).Interface())), source)
+		}
+	}
+}
+
+// optionSource find out which source supplied the value of option
+func optionSource(f *flag.Flag, config, buildIn map[string]map[string]string, group, item string) string {
+	if f != nil && f.Changed {
+		return sourceFlag
+	}
+	if _, exists := config[group][item]; exists {
+		return sourceConfig
+	}
+	if _, exists := buildIn[group][item]; exists {
+		return sourceBuildIn
+	}
+	return sourceDefault
+}
+
+// redactOptionValue hide credential in option value, e.g. password in url or token in query string
+func redactOptionValue(name, value string) string {
+	if value == "" {
+		return value
+	}
+	for _, keyword := range sensitiveKeywords {
+		if strings.Contains(strings.ToLower(name), keyword) {
+			return redactedValue
+		}
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return value
+	}
+	if u.User != nil {
+		u.User = url.User(redactedValue)
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query.Set(key, redactedValue)
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// readConfigFile content of config file, empty if not exists
+func readConfigFile() []byte {
+	data, err := ioutil.ReadFile(util.KtConfigFile)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// parseConfigData parse content of config file, names are normalized in the same way options are merged
+func parseConfigData(data []byte) map[string]map[string]string {
+	config := make(map[string]map[string]string)
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return map[string]map[string]string{}
+	}
+	normalized := make(map[string]map[string]string)
+	for group, items := range config {
+		groupName := util.DashSeparated(util.Capitalize(group))
+		normalized[groupName] = make(map[string]string)
+		for item, value := range items {
+			normalized[groupName][util.DashSeparated(util.Capitalize(item))] = value
+		}
+	}
+	return normalized
+}
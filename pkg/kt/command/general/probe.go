@@ -0,0 +1,59 @@
+package general
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"strings"
+	"time"
+)
+
+const (
+	probeHeader   = "Kt-Probe"
+	probeAttempts = 5
+	probeInterval = 2 * time.Second
+)
+
+// ExecProbe send a synthetic http request to service from inside the shadow pod,
+// and verify the request is forwarded to local port via reverse tunnel
+func ExecProbe(shadowPodName, serviceName string, servicePort, localPort int) error {
+	url := fmt.Sprintf("http://%s.%s:%d/", serviceName, opt.Get().Global.Namespace, servicePort)
+	marker := strings.ToLower(util.RandomString(10))
+	log.Info().Msgf("Probing %s with header '%s: %s'", url, probeHeader, marker)
+
+	var err error
+	for i := 0; i < probeAttempts; i++ {
+		if i > 0 {
+			time.Sleep(probeInterval)
+		}
+		if err = probeOnce(shadowPodName, url, marker, localPort); err == nil {
+			return nil
+		}
+		log.Debug().Err(err).Msgf("Probe attempt %d failed", i+1)
+	}
+	return err
+}
+
+func probeOnce(shadowPodName, url, marker string, localPort int) error {
+	before := sshchannel.AcceptedRequestCount()
+	stdout, stderr, err := cluster.Ins().ExecInPod(util.DefaultContainer, shadowPodName, opt.Get().Global.Namespace,
+		"curl", "-s", "-S", "-o", "/dev/null", "-m", "5", "-w", "%{http_code}", "-H",
+		fmt.Sprintf("%s: %s", probeHeader, marker), url)
+	reachedLocal := sshchannel.AcceptedRequestCount() > before
+	if err != nil {
+		if reachedLocal {
+			return fmt.Errorf("request reached shadow pod, but local service on port %d did not respond: %s",
+				localPort, stderr)
+		}
+		return fmt.Errorf("request to %s failed: %s", url, strings.TrimSpace(err.Error()+" "+stderr))
+	}
+	if !reachedLocal {
+		return fmt.Errorf("request to %s got status %s from cluster, request reached original pod, "+
+			"routing not effective", url, stdout)
+	}
+	log.Info().Msgf("Probe passed, request to %s was handled by local port %d with status %s", url, localPort, stdout)
+	return nil
+}
@@ -0,0 +1,47 @@
+package general
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// profileCmd command whose options are saved to profile once ready, nil if '--profile' not specified
+var profileCmd *cobra.Command
+
+// profilePath profile file of target service
+var profilePath string
+
+// LoadServiceProfile apply options last used for target service when '--profile' specified,
+// must be called after Prepare, since profile is located by namespace
+func LoadServiceProfile(cmd *cobra.Command, args []string) error {
+	if !opt.Get().Global.Profile {
+		return nil
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("'--profile' requires exactly one target service, but got %d", len(args))
+	}
+	path := opt.ProfilePath(opt.Get().Global.Namespace, args[0])
+	loaded, err := opt.LoadProfile(cmd, path)
+	if err != nil {
+		return err
+	}
+	if loaded {
+		log.Info().Msgf("Using options saved in profile %s", path)
+	}
+	profileCmd, profilePath = cmd, path
+	return nil
+}
+
+// saveServiceProfile remember options of current command for next run, only after they worked
+func saveServiceProfile() {
+	if profileCmd == nil || opt.Get().Global.DryRun {
+		return
+	}
+	if err := opt.SaveProfile(profileCmd, profilePath); err != nil {
+		log.Warn().Err(err).Msgf("Failed to save profile %s", profilePath)
+	} else {
+		log.Debug().Msgf("Options saved to profile %s", profilePath)
+	}
+}
@@ -0,0 +1,90 @@
+package general
+
+import (
+	"encoding/json"
+	"errors"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const readyFileSuffix = ".ready"
+
+// readyFile ready file created by current process, removed when cleanup
+var readyFile string
+
+// ReadyFilePath path of file indicating component of the signal file has finished setup, e.g. ktctl-exchange-tomcat-signal-1234.ready
+func ReadyFilePath(signalFile string) string {
+	return signalFile + readyFileSuffix
+}
+
+// IsReady whether component of the signal file has finished setup and traffic is redirected
+func IsReady(signalFile string) bool {
+	_, err := os.Stat(ReadyFilePath(signalFile))
+	return err == nil
+}
+
+// MarkReady create ready file next to signal file with setup result in json, and run '--readyHook' command if specified,
+// should only be called after redirect is active, since external tools start using the tunnel on it
+func MarkReady(result *SetupResult, signalFile, pipeName string) {
+	result.SignalFile = signalFile
+	result.ControlPipe = pipeName
+	path := ReadyFilePath(signalFile)
+	if data, err := json.Marshal(result); err != nil {
+		log.Warn().Err(err).Msgf("Failed to generate content of ready file")
+	} else if err = os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		log.Warn().Err(err).Msgf("Failed to create ready file %s", path)
+	} else {
+		readyFile = path
+		log.Debug().Msgf("Ready file %s created", path)
+	}
+	saveServiceProfile()
+	if opt.Get().Global.ReadyHook != "" {
+		go runReadyHook(opt.Get().Global.ReadyHook, result)
+	}
+}
+
+// runReadyHook run user command via shell, its failure is only logged and never stops current component
+func runReadyHook(hook string, result *SetupResult) {
+	var cmd *exec.Cmd
+	if util.IsWindows() {
+		cmd = exec.Command("cmd", "/C", hook)
+	} else {
+		cmd = exec.Command("sh", "-c", hook)
+	}
+	cmd.Env = append(os.Environ(),
+		"KT_COMPONENT="+result.Command,
+		"KT_NAMESPACE="+result.Namespace,
+		"KT_SERVICES="+strings.Join(result.Services, ","),
+		"KT_SIGNAL_FILE="+result.SignalFile,
+		"KT_READY_FILE="+ReadyFilePath(result.SignalFile),
+	)
+	// keep stdout of ktctl for its own output, e.g. setup result in json
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	log.Info().Msgf("Running ready hook: %s", hook)
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err == nil {
+		log.Info().Msgf("Ready hook finished with exit code 0")
+	} else if errors.As(err, &exitErr) {
+		log.Warn().Msgf("Ready hook finished with exit code %d", exitErr.ExitCode())
+	} else {
+		log.Warn().Err(err).Msgf("Failed to run ready hook")
+	}
+}
+
+// removeReadyFile remove ready file created by current process
+func removeReadyFile() {
+	if readyFile == "" {
+		return
+	}
+	if err := os.Remove(readyFile); err != nil && !os.IsNotExist(err) {
+		log.Debug().Err(err).Msgf("Remove ready file %s failed", readyFile)
+	} else if err == nil {
+		log.Info().Msgf("Removed ready file %s", readyFile)
+	}
+}
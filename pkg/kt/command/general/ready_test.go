@@ -0,0 +1,23 @@
+package general
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarkReady(t *testing.T) {
+	signalFile := filepath.Join(t.TempDir(), "ktctl-exchange-tomcat-signal-1234")
+	require.False(t, IsReady(signalFile))
+
+	MarkReady(&SetupResult{Command: "exchange", Services: []string{"tomcat"}}, signalFile, "")
+	require.True(t, IsReady(signalFile))
+	data, err := os.ReadFile(ReadyFilePath(signalFile))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"signalFile":"`+signalFile+`"`)
+
+	removeReadyFile()
+	require.False(t, IsReady(signalFile))
+	readyFile = ""
+}
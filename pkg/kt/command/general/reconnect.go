@@ -0,0 +1,91 @@
+package general
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const envReconnectSession = "KT_RECONNECT_SESSION"
+const envReconnectCount = "KT_RECONNECT_COUNT"
+
+// maxReconnectInterval upper bound of backoff between reconnect cycles
+const maxReconnectInterval = 60 * time.Second
+
+// ReconnectCount times current command has been re-established by reconnect, 0 for the first run
+func ReconnectCount() int {
+	count, err := strconv.Atoi(os.Getenv(envReconnectCount))
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// Reconnect tear down current command, and run it again with same session after a backoff,
+// never returns unless the new run failed to start
+func Reconnect(cause string) {
+	count := ReconnectCount() + 1
+	interval := 5 * time.Second << (count - 1)
+	if interval > maxReconnectInterval || interval <= 0 {
+		interval = maxReconnectInterval
+	}
+	log.Warn().Msgf("Reconnect cycle %d: %s", count, cause)
+	CleanupWorkspace()
+	log.Info().Msgf("Reconnecting in %v ...", interval)
+	time.Sleep(interval)
+	env := make([]string, 0)
+	for _, e := range os.Environ() {
+		// values of previous cycle must be removed, otherwise they shadow the new ones
+		if !strings.HasPrefix(e, envReconnectSession+"=") && !strings.HasPrefix(e, envReconnectCount+"=") {
+			env = append(env, e)
+		}
+	}
+	env = append(env, fmt.Sprintf("%s=%s", envReconnectSession, opt.Store.Session),
+		fmt.Sprintf("%s=%d", envReconnectCount, count))
+	if err := util.RestartProcess(env); err != nil {
+		log.Error().Err(err).Msgf("Failed to restart %s", opt.Store.Component)
+	}
+	os.Exit(1)
+}
+
+// tunnelLostSignal signal sent to serving loop when ssh tunnel cannot be re-established
+type tunnelLostSignal struct {
+	cause string
+}
+
+func (s tunnelLostSignal) String() string {
+	return s.cause
+}
+
+func (s tunnelLostSignal) Signal() {}
+
+var tunnelLost int32
+
+// TunnelLost whether current command is stopped because ssh tunnel lost
+func TunnelLost() bool {
+	return atomic.LoadInt32(&tunnelLost) == 1
+}
+
+// stopOnTunnelLost let serving loop stop and cleanup when reconnecting ssh tunnel gave up
+func stopOnTunnelLost(cause string) {
+	atomic.StoreInt32(&tunnelLost, 1)
+	if processSignal == nil {
+		return
+	}
+	select {
+	case processSignal <- tunnelLostSignal{cause: cause}:
+	case <-time.After(3 * time.Second):
+		log.Warn().Msgf("Command is not serving, tunnel lost ignored")
+	}
+}
+
+// reconnectSession session id of the run before reconnect, empty if not a reconnect run
+func reconnectSession() string {
+	return os.Getenv(envReconnectSession)
+}
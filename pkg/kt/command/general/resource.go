@@ -8,7 +8,6 @@ import (
 	"github.com/alibaba/kt-connect/pkg/kt/transmission"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
-	appV1 "k8s.io/api/apps/v1"
 	coreV1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -23,8 +22,11 @@ func CreateShadowAndInbound(shadowPodName, portsToExpose string, labels, annotat
 	if err != nil {
 		return err
 	}
+	if opt.Get().Global.DryRun {
+		return nil
+	}
 
-	if _, err = transmission.ForwardPodToLocal(portsToExpose, podName, privateKeyPath); err != nil {
+	if _, err = transmission.ForwardPodToLocal(portsToExpose, podName, privateKeyPath, LocalAddr()); err != nil {
 		return err
 	}
 	return nil
@@ -37,21 +39,14 @@ func GetServiceByResourceName(resourceName, namespace string) (*coreV1.Service,
 	}
 
 	switch resourceType {
-	case "deploy":
-		fallthrough
-	case "deployment":
-		app, err2 := cluster.Ins().GetDeployment(name, namespace)
+	case KindDeployment, KindStatefulSet, KindDaemonSet:
+		workload, err2 := GetWorkload(resourceType, name, namespace)
 		if err2 != nil {
-			if k8sErrors.IsNotFound(err2) {
-				return nil, fmt.Errorf("deployment '%s' is not found in namespace %s", name, namespace)
-			}
 			return nil, err2
 		}
-		return getServiceByDeployment(app, namespace)
-	case "svc":
-		fallthrough
-	case "service":
-		svc, err2 := cluster.Ins().GetService(name, namespace)
+		return getServiceByWorkload(workload, namespace)
+	case KindService:
+		svc, err2 := GetServiceWithRetry(name, namespace)
 		if err2 != nil && k8sErrors.IsNotFound(err2) {
 			return nil, fmt.Errorf("service '%s' is not found in namespace %s", name, namespace)
 		}
@@ -61,37 +56,16 @@ func GetServiceByResourceName(resourceName, namespace string) (*coreV1.Service,
 	}
 }
 
-func GetDeploymentByResourceName(resourceName, namespace string) (*appV1.Deployment, error) {
-	resourceType, name, err := ParseResourceName(resourceName)
-	if err != nil {
-		return nil, err
-	}
-
-	switch resourceType {
-	case "deploy":
-		fallthrough
-	case "deployment":
-		app, err2 := cluster.Ins().GetDeployment(name, namespace)
-		if err2 != nil && k8sErrors.IsNotFound(err2) {
-			return nil, fmt.Errorf("deployment '%s' is not found in namespace %s", name, namespace)
-		}
-		return app, err2
-	case "svc":
-		fallthrough
-	case "service":
-		svc, err2 := cluster.Ins().GetService(name, namespace)
-		if err2 != nil {
-			if k8sErrors.IsNotFound(err2) {
-				return nil, fmt.Errorf("service '%s' is not found in namespace %s", name, namespace)
-			}
-			return nil, err2
-		}
-		return getDeploymentByService(svc, namespace)
-	default:
-		return nil, fmt.Errorf("invalid resource type: %s", resourceType)
-	}
+// GetServiceWithRetry fetch service, retry when api server is temporarily unavailable
+func GetServiceWithRetry(name, namespace string) (svc *coreV1.Service, err error) {
+	err = RetryOnTransientError("fetch service "+name, func() error {
+		svc, err = cluster.Ins().GetService(name, namespace)
+		return err
+	})
+	return svc, err
 }
 
+// ParseResourceName split target in '<type>/<name>' format, type is normalized to kind and defaults to service
 func ParseResourceName(resourceName string) (string, string, error) {
 	segments := strings.Split(resourceName, "/")
 	var resourceType, name string
@@ -101,10 +75,10 @@ func ParseResourceName(resourceName string) (string, string, error) {
 		resourceType = segments[0]
 		name = segments[1]
 	} else {
-		resourceType = "service"
+		resourceType = KindService
 		name = resourceName
 	}
-	return resourceType, name, nil
+	return NormalizeResourceType(resourceType), name, nil
 }
 
 func UpdateServiceSelector(svcName, namespace string, selector map[string]string) error {
@@ -139,6 +113,10 @@ func UpdateServiceSelector(svcName, namespace string, selector map[string]string
 			return err
 		}
 	}
+	if opt.Get().Global.DryRun || len(selector) == 0 {
+		// no pod to watch when service selector is removed
+		return nil
+	}
 
 	go cluster.Ins().WatchService(svcName, namespace, nil, nil, func(newSvc *coreV1.Service) {
 		if pods, err2 := cluster.Ins().GetPodsByLabel(selector, namespace); err2 != nil || len(pods.Items) == 0 {
@@ -203,13 +181,13 @@ func isServiceChanged(svc *coreV1.Service, selector map[string]string, marshaled
 	return !util.MapEquals(svc.Spec.Selector, selector) || svc.Annotations == nil || svc.Annotations[util.KtSelector] != marshaledSelector
 }
 
-func getServiceByDeployment(app *appV1.Deployment, namespace string) (*coreV1.Service, error) {
-	svcList, err := cluster.Ins().GetServicesBySelector(app.Spec.Selector.MatchLabels, namespace)
+func getServiceByWorkload(workload *Workload, namespace string) (*coreV1.Service, error) {
+	svcList, err := cluster.Ins().GetServicesBySelector(workload.Selector, namespace)
 	if err != nil {
 		return nil, err
 	} else if len(svcList) == 0 {
-		return nil, fmt.Errorf("failed to find service for deployment '%s', with labels '%v'",
-			app.Name, app.Spec.Selector.MatchLabels)
+		return nil, fmt.Errorf("failed to find service for %s '%s', with labels '%v'",
+			workload.Kind, workload.Name, workload.Selector)
 	} else if len(svcList) > 1 {
 		svcNames := svcList[0].Name
 		for i, svc := range svcList {
@@ -217,8 +195,8 @@ func getServiceByDeployment(app *appV1.Deployment, namespace string) (*coreV1.Se
 				svcNames = svcNames + ", " + svc.Name
 			}
 		}
-		log.Warn().Msgf("Found %d services match deployment '%s': %s. First one will be used.",
-			len(svcList), app.Name, svcNames)
+		log.Warn().Msgf("Found %d services match %s '%s': %s. First one will be used.",
+			len(svcList), workload.Kind, workload.Name, svcNames)
 	}
 	svc := svcList[0]
 	if strings.HasSuffix(svc.Name, util.StuntmanServiceSuffix) {
@@ -227,21 +205,6 @@ func getServiceByDeployment(app *appV1.Deployment, namespace string) (*coreV1.Se
 	return &svc, nil
 }
 
-func getDeploymentByService(svc *coreV1.Service, namespace string) (*appV1.Deployment, error) {
-	apps, err := cluster.Ins().GetAllDeploymentInNamespace(namespace)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, app := range apps.Items {
-		if util.MapContains(svc.Spec.Selector, app.Spec.Template.Labels) {
-			log.Info().Msgf("Using first matched deployment '%s'", app.Name)
-			return &app, nil
-		}
-	}
-	return nil, fmt.Errorf("failed to find deployment for service '%s', with selector '%v'", svc.Name, svc.Spec.Selector)
-}
-
 func GetOccupiedUser(labels map[string]string) string {
 	podList, err := cluster.Ins().GetPodsByLabel(labels, opt.Get().Global.Namespace)
 	if err == nil && len(podList.Items) > 0 && podList.Items[0].Annotations != nil && podList.Items[0].Annotations[util.KtUser] != "" {
@@ -0,0 +1,96 @@
+package general
+
+import (
+	"context"
+	"errors"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/rs/zerolog/log"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	utilNet "k8s.io/apimachinery/pkg/util/net"
+	"net"
+	"strings"
+	"time"
+)
+
+// RetryOnTransientError run api request, retry it on transient error until retry times or timeout exhausted,
+// the error of last attempt is returned
+func RetryOnTransientError(action string, request func() error) error {
+	deadline := time.Now().Add(time.Duration(opt.Get().Global.ApiTimeout) * time.Second)
+	interval := time.Second
+	for i := 0; ; i++ {
+		err := request()
+		if err == nil || !isTransientError(err) || i >= opt.Get().Global.ApiRetry ||
+			time.Now().Add(interval).After(deadline) {
+			return err
+		}
+		log.Warn().Msgf("Failed to %s, retry in %v (%d/%d): %s", action, interval, i+1,
+			opt.Get().Global.ApiRetry, err.Error())
+		time.Sleep(interval)
+		interval *= 2
+	}
+}
+
+// RetrySetup run the whole setup, on retryable failure revert its partial changes and run it again after a backoff,
+// until it succeeds, retry times exhausted or ctx is done
+func RetrySetup(ctx context.Context, retries int, setup func() error, revert func()) error {
+	interval := 3 * time.Second
+	for i := 0; ; i++ {
+		err := setup()
+		if err == nil || i >= retries || opt.Get().Global.DryRun || ctx.Err() != nil {
+			return err
+		}
+		if !IsRetryableSetupError(err) {
+			log.Debug().Msgf("Setup error is not retryable: %s", err.Error())
+			return err
+		}
+		log.Warn().Msgf("Setup failed (attempt %d/%d): %s", i+1, retries+1, err.Error())
+		log.Info().Msgf("Reverting partial changes before retry ...")
+		if revert != nil {
+			revert()
+		}
+		RevertSetup()
+		log.Info().Msgf("Retry setup in %v", interval)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return err
+		}
+		interval *= 2
+	}
+}
+
+// IsRetryableSetupError check whether a failed setup may succeed by running again,
+// invalid input, permission denied and missing resource never do
+func IsRetryableSetupError(err error) bool {
+	if isTransientError(err) {
+		return true
+	}
+	var status k8sErrors.APIStatus
+	if errors.As(err, &status) {
+		return k8sErrors.IsConflict(err) || k8sErrors.IsAlreadyExists(err)
+	}
+	// most setup errors are formatted from original error, check by message
+	msg := strings.ToLower(err.Error())
+	for _, keyword := range []string{"forbidden", "unauthorized", "not found", "invalid", "not supported", "another user"} {
+		if strings.Contains(msg, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTransientError check whether the error may disappear when request again, e.g. api server temporarily unavailable
+func isTransientError(err error) bool {
+	if k8sErrors.IsNotFound(err) || k8sErrors.IsForbidden(err) || k8sErrors.IsUnauthorized(err) {
+		return false
+	}
+	if k8sErrors.IsServerTimeout(err) || k8sErrors.IsTimeout(err) || k8sErrors.IsTooManyRequests(err) ||
+		k8sErrors.IsServiceUnavailable(err) || k8sErrors.IsInternalError(err) || k8sErrors.IsUnexpectedServerError(err) {
+		return true
+	}
+	if utilNet.IsConnectionRefused(err) || utilNet.IsConnectionReset(err) || utilNet.IsProbableEOF(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,58 @@
+package general
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func Test_isTransientError(t *testing.T) {
+	svc := schema.GroupResource{Resource: "services"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"not found", k8sErrors.NewNotFound(svc, "tomcat"), false},
+		{"forbidden", k8sErrors.NewForbidden(svc, "tomcat", fmt.Errorf("denied")), false},
+		{"unauthorized", k8sErrors.NewUnauthorized("expired"), false},
+		{"service unavailable", k8sErrors.NewServiceUnavailable("restarting"), true},
+		{"too many requests", k8sErrors.NewTooManyRequests("busy", 1), true},
+		{"server timeout", k8sErrors.NewServerTimeout(svc, "get", 1), true},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"wrapped network error", fmt.Errorf("get service: %w", &net.DNSError{IsTimeout: true}), true},
+		{"other error", fmt.Errorf("invalid resource type"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, isTransientError(tt.err))
+		})
+	}
+}
+
+func TestIsRetryableSetupError(t *testing.T) {
+	svc := schema.GroupResource{Resource: "services"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"not found", k8sErrors.NewNotFound(svc, "tomcat"), false},
+		{"forbidden", k8sErrors.NewForbidden(svc, "tomcat", fmt.Errorf("denied")), false},
+		{"invalid", k8sErrors.NewBadRequest("bad spec"), false},
+		{"conflict", k8sErrors.NewConflict(svc, "tomcat", fmt.Errorf("modified")), true},
+		{"service unavailable", k8sErrors.NewServiceUnavailable("webhook down"), true},
+		{"pod failed", fmt.Errorf("pod tomcat-kt-exchange-abcde failed to start"), true},
+		{"formatted not found", fmt.Errorf("service 'tomcat' is not found"), false},
+		{"occupied", fmt.Errorf("another user is meshing service 'tomcat', cannot apply exchange"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsRetryableSetupError(tt.err))
+		})
+	}
+}
@@ -1,20 +1,25 @@
 package general
 
 import (
+	"context"
 	"fmt"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	k8sRuntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"syscall"
 	"strings"
 )
@@ -23,15 +28,63 @@ import (
 func Prepare() error {
 	// then setup logs
 	SetupLogger()
+	util.SetupWatchdog(opt.Get().Global.AutoRestart, CleanupWorkspace)
+
+	if opt.Get().Global.CopyBufferSize < 1 || opt.Get().Global.CopyBufferSize > util.MaxCopyBufferSizeKb {
+		return fmt.Errorf("copy buffer size should between 1 and %d KB, but got %d",
+			util.MaxCopyBufferSizeKb, opt.Get().Global.CopyBufferSize)
+	}
+	if opt.Get().Global.PreserveSourceIp != "" && !util.Contains([]string{util.SourceIpProxyProtocol,
+		util.SourceIpHttpHeader}, opt.Get().Global.PreserveSourceIp) {
+		return fmt.Errorf("invalid preserve source ip method '%s', supportted are %s, %s",
+			opt.Get().Global.PreserveSourceIp, util.SourceIpProxyProtocol, util.SourceIpHttpHeader)
+	}
+	if opt.Get().Global.ShadowGracePeriod < 0 {
+		return fmt.Errorf("shadow grace period should not be negative, but got %d", opt.Get().Global.ShadowGracePeriod)
+	}
+	if opt.Get().Global.ShadowToleration != "" {
+		if _, err := cluster.ParseTolerations(opt.Get().Global.ShadowToleration); err != nil {
+			return err
+		}
+	}
+	if opt.Get().Global.ShadowInitContainer != "" {
+		if _, err := cluster.ParseInitContainer(opt.Get().Global.ShadowInitContainer); err != nil {
+			return err
+		}
+	}
+	if err := CheckOutputFormat(); err != nil {
+		return err
+	}
+	if opt.Get().Global.ShadowPodPatch != "" {
+		if _, err := cluster.ParseShadowPodPatch(opt.Get().Global.ShadowPodPatch); err != nil {
+			return err
+		}
+	}
+	if deadline, err := parseDeadline(); err != nil || deadline < 0 {
+		return fmt.Errorf("invalid deadline '%s', should be a positive duration like 30m or 2h", opt.Get().Global.Deadline)
+	} else if deadline > 0 {
+		setupDeadline(deadline)
+	}
+	if opt.Get().Global.SetupTimeout < 0 {
+		return fmt.Errorf("setup timeout should not be negative, but got %d", opt.Get().Global.SetupTimeout)
+	}
 
 	if err := combineKubeOpts(); err != nil {
 		return err
 	}
+	if err := checkNamespaceAllowed(); err != nil {
+		return err
+	}
 
-	log.Info().Msgf("KtConnect %s start at %d (%s %s)",
-		opt.Store.Version, os.Getpid(), runtime.GOOS, runtime.GOARCH)
+	opt.Store.Session = strings.ToLower(util.RandomString(10))
+	if session := reconnectSession(); session != "" {
+		// keep session of previous run, so that resources created are recognized as same session
+		opt.Store.Session = session
+	}
+	log.Info().Msgf("KtConnect %s start at %d (%s %s), session %s",
+		opt.Store.Version, os.Getpid(), runtime.GOOS, runtime.GOARCH, opt.Store.Session)
 
-	if !opt.Get().Global.UseLocalTime {
+	if !opt.Get().Global.UseLocalTime && !opt.Get().Global.DryRun && !opt.Get().Global.ValidateOnly {
 		if err := cluster.SetupTimeDifference(); err != nil {
 			return err
 		}
@@ -43,6 +96,13 @@ func SetupLogger() {
 	if opt.Get().Global.Debug {
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
 	}
+	if opt.Get().Global.LogCaller {
+		// keep parent directory to distinguish files with same name in different packages
+		zerolog.CallerMarshalFunc = func(file string, line int) string {
+			return filepath.Join(filepath.Base(filepath.Dir(file)), filepath.Base(file)) + ":" + strconv.Itoa(line)
+		}
+		log.Logger = log.With().Caller().Logger()
+	}
 	util.PrepareLogger(opt.Get().Global.Debug)
 	k8sRuntime.ErrorHandlers = []func(error){
 		func(err error) {
@@ -53,12 +113,20 @@ func SetupLogger() {
 	klog.LogToStderr(false)
 }
 
-// SetupProcess write pid file and set component type
-func SetupProcess(componentName string) (chan os.Signal, error) {
-	ch := make(chan os.Signal)
+// processSignal channel of serving loop, nil before SetupProcess called
+var processSignal chan os.Signal
+
+// SetupProcess write pid file and set component type, the returned context is done when deadline of command reached
+func SetupProcess(componentName string) (context.Context, chan os.Signal, error) {
+	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, os.Interrupt, syscall.SIGHUP, syscall.SIGTERM, syscall.SIGQUIT)
 	opt.Store.Component = componentName
-	return ch, util.WritePidFile(componentName, ch)
+	processSignal = ch
+	sshchannel.SetGiveUpHandler(stopOnTunnelLost)
+	if err := startHealthServer(opt.Get().Global.HealthAddr); err != nil {
+		return rootCtx, ch, err
+	}
+	return rootCtx, ch, util.WritePidFile(componentName, ch)
 }
 
 // combineKubeOpts set default options of kubectl if not assign
@@ -111,11 +179,20 @@ func combineKubeOpts() (err error) {
 	if err != nil {
 		return err
 	}
+	if opt.Get().Connect.Fwmark > 0 && util.FwmarkSupported {
+		// connections to api server carry all tunnel traffic, they must not be routed into the tun device
+		restConfig.Dial = util.FwmarkDialer(opt.Get().Connect.Fwmark).DialContext
+	}
 	clientSet, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return err
 	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
 	opt.Store.Clientset = clientSet
+	opt.Store.DynamicClient = dynamicClient
 	opt.Store.RestConfig = restConfig
 
 	if opt.Get().Global.IpVersion == 6 || strings.Contains(restConfig.Host, "[") {
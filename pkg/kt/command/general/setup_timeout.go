@@ -0,0 +1,31 @@
+package general
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"time"
+)
+
+// RunSetupWithTimeout run setup of exchange, mesh or preview with a context expires in '--setupTimeout' seconds,
+// api requests and pod waiting of setup abort when it expires, so setup returns before cleanup starts,
+// the tunnel established after setup is not bounded by the timeout
+func RunSetupWithTimeout(ctx context.Context, component string, setup func(ctx context.Context) error) error {
+	timeout := opt.Get().Global.SetupTimeout
+	if timeout <= 0 || opt.Get().Global.DryRun {
+		return RunSetup(ctx, func() error {
+			return setup(ctx)
+		})
+	}
+	setupCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	err := RunSetup(setupCtx, func() error {
+		return setup(setupCtx)
+	})
+	if err != nil && errors.Is(setupCtx.Err(), context.DeadlineExceeded) && !DeadlineExceeded() {
+		return fmt.Errorf("%s setup not finished in %d seconds, please check whether cluster is reachable and "+
+			"shadow pod can be scheduled, or increase --setupTimeout", component, timeout)
+	}
+	return err
+}
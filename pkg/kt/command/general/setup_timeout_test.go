@@ -0,0 +1,40 @@
+package general
+
+import (
+	"context"
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRunSetupWithTimeout(t *testing.T) {
+	defer func(timeout int) { opt.Get().Global.SetupTimeout = timeout }(opt.Get().Global.SetupTimeout)
+	opt.Get().Global.SetupTimeout = 1
+
+	require.NoError(t, RunSetupWithTimeout(context.Background(), "exchange", func(ctx context.Context) error { return nil }))
+	require.EqualError(t, RunSetupWithTimeout(context.Background(), "exchange", func(ctx context.Context) error {
+		return fmt.Errorf("failed")
+	}), "failed")
+
+	finished := false
+	err := RunSetupWithTimeout(context.Background(), "mesh", func(ctx context.Context) error {
+		select {
+		case <-time.After(3 * time.Second):
+			return nil
+		case <-ctx.Done():
+			finished = true
+			return ctx.Err()
+		}
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "mesh setup not finished in 1 seconds")
+	require.True(t, finished, "setup should have returned before timeout error")
+
+	opt.Get().Global.SetupTimeout = 0
+	require.NoError(t, RunSetupWithTimeout(context.Background(), "preview", func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}))
+}
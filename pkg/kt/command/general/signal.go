@@ -0,0 +1,297 @@
+package general
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CommandStop control command to stop the component
+const CommandStop = "stop"
+
+// CommandPause control command to temporarily route traffic back to original pods
+const CommandPause = "pause"
+
+// CommandResume control command to route traffic to local again after paused
+const CommandResume = "resume"
+
+const signalFilePrefix = "ktctl-"
+const signalFileInfix = "-signal-"
+
+// SignalFilePath get path of signal file used for stopping specified component,
+// with name of resource it operates on if not empty, e.g. ktctl-exchange-tomcat-signal-1234
+func SignalFilePath(component, resourceName string) string {
+	if resourceName == "" {
+		return filepath.Join(os.TempDir(), fmt.Sprintf("%s%s%s%d", signalFilePrefix, component, signalFileInfix, os.Getpid()))
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s%s-%s%s%d", signalFilePrefix, component,
+		sanitizeResourceName(resourceName), signalFileInfix, os.Getpid()))
+}
+
+// FindSignalFiles get paths of signal files of running components, empty component or resource name matches any
+func FindSignalFiles(component, resourceName string) []string {
+	files, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		return nil
+	}
+	paths := make([]string, 0)
+	for _, f := range files {
+		c, r, pid, ok := ParseSignalFileName(f.Name())
+		if !ok || (component != "" && c != component) {
+			continue
+		}
+		path := filepath.Join(os.TempDir(), f.Name())
+		if resourceName != "" && !signalFileMatches(path, r, resourceName) {
+			continue
+		}
+		if util.IsProcessExist(pid) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// ParseSignalFileName get component, sanitized resource name and pid from signal file name,
+// resource name is empty for signal file in pid-only format, e.g. ktctl-connect-signal-1234
+func ParseSignalFileName(name string) (string, string, int, bool) {
+	pos := strings.LastIndex(name, signalFileInfix)
+	if !strings.HasPrefix(name, signalFilePrefix) || pos < len(signalFilePrefix) {
+		return "", "", -1, false
+	}
+	pid, err := strconv.Atoi(name[pos+len(signalFileInfix):])
+	if err != nil {
+		return "", "", -1, false
+	}
+	// component name never contains '-', the rest is resource name
+	parts := strings.SplitN(name[len(signalFilePrefix):pos], "-", 2)
+	if len(parts) == 1 {
+		return parts[0], "", pid, true
+	}
+	return parts[0], parts[1], pid, true
+}
+
+// sanitizeResourceName convert resource name to be part of file name, e.g. deployment/tomcat to deployment.tomcat
+func sanitizeResourceName(resourceName string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return '.'
+	}, resourceName)
+}
+
+// WatchSignalFile create the signal file with metadata of resource it operates on, and handle each command line
+// appended to it, send interrupt signal to channel when "stop" is received
+func WatchSignalFile(signalFile, resourceName string, ch chan os.Signal) {
+	// Create the signal file to indicate component is ready
+	if err := CreateSignalFile(signalFile, resourceName); err != nil {
+		log.Debug().Err(err).Msgf("Failed to create signal file")
+	}
+
+	reader := &signalFileReader{path: signalFile}
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = watcher.Add(signalFile); err != nil {
+			_ = watcher.Close()
+		}
+	}
+	if err != nil {
+		log.Debug().Err(err).Msgf("Unable to watch signal file, fallback to polling")
+		pollSignalFile(reader, ch)
+		return
+	}
+	defer watcher.Close()
+
+	// commands written before watcher started
+	if handleSignalCommands(reader, ch) {
+		return
+	}
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Write == fsnotify.Write {
+				if handleSignalCommands(reader, ch) {
+					return
+				}
+			} else if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				// signal file is removed when component exits, or replaced by tools writing via rename
+				if _, err = os.Stat(signalFile); err == nil {
+					_ = watcher.Close()
+					pollSignalFile(reader, ch)
+				}
+				return
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Debug().Err(err).Msgf("Error occurred while watching signal file")
+		}
+	}
+}
+
+// pollSignalFile check signal file every second, for file system not supporting notification
+func pollSignalFile(reader *signalFileReader, ch chan os.Signal) {
+	for {
+		time.Sleep(1 * time.Second)
+		if handleSignalCommands(reader, ch) {
+			return
+		}
+	}
+}
+
+// handleSignalCommands handle commands newly appended to signal file, return true if component should stop
+func handleSignalCommands(reader *signalFileReader, ch chan os.Signal) bool {
+	for _, command := range reader.readCommands() {
+		if handleControlCommand(command, "signal file", ch) {
+			return true
+		}
+	}
+	return false
+}
+
+// signalFileReader read command lines from signal file, each line is only read once
+type signalFileReader struct {
+	path string
+	read []byte
+}
+
+// readCommands return complete lines appended since last read, a line without line break is left for next read
+func (r *signalFileReader) readCommands() []string {
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil
+	}
+	if !bytes.HasPrefix(content, r.read) {
+		// signal file is overwritten instead of appended, read from beginning
+		r.read = nil
+	}
+	pending := content[len(r.read):]
+	end := bytes.LastIndexByte(pending, '\n')
+	if end < 0 {
+		// compatible with whole file content without line break, e.g. written by 'echo -n stop > signal-file'
+		if len(r.read) == 0 && strings.TrimSpace(string(pending)) == CommandStop {
+			r.read = content
+			return []string{CommandStop}
+		}
+		return nil
+	}
+	r.read = content[:len(r.read)+end+1]
+	commands := make([]string, 0)
+	for _, line := range strings.Split(string(pending[:end]), "\n") {
+		if command := strings.TrimSpace(line); command != "" && !isSignalFileHeader(command) {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// controlHandlers handlers of control commands other than "stop", registered by component supporting them
+var controlHandlers = map[string]func() error{}
+
+// controlHandlersLock handlers may be registered after signal file watcher started, e.g. pause after setup finished
+var controlHandlersLock sync.RWMutex
+
+// RegisterControlHandler let current component handle specified command received from signal file or named pipe
+func RegisterControlHandler(command string, handler func() error) {
+	controlHandlersLock.Lock()
+	defer controlHandlersLock.Unlock()
+	controlHandlers[command] = handler
+}
+
+// handleControlCommand handle command received from signal file or named pipe, return true if component should stop
+func handleControlCommand(command, source string, ch chan os.Signal) bool {
+	if command == CommandStop {
+		// Send interrupt signal to the main routine
+		ch <- os.Interrupt
+		return true
+	}
+	controlHandlersLock.RLock()
+	handler, exists := controlHandlers[command]
+	controlHandlersLock.RUnlock()
+	if exists {
+		log.Info().Msgf("Received command '%s' from %s", command, source)
+		if err := handler(); err != nil {
+			log.Warn().Err(err).Msgf("Failed to %s", command)
+		}
+		return false
+	}
+	log.Warn().Msgf("Unsupported command '%s' received from %s", command, source)
+	return false
+}
+
+// SendControlCommand append command to signal file of a running component
+func SendControlCommand(signalFile, command string) error {
+	f, err := os.OpenFile(signalFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(command + "\n")
+	return err
+}
+
+// IsPaused whether the last pause or resume command sent to component via signal file is pause
+func IsPaused(signalFile string) bool {
+	content, err := os.ReadFile(signalFile)
+	if err != nil {
+		return false
+	}
+	paused := false
+	for _, line := range strings.Split(string(content), "\n") {
+		switch strings.TrimSpace(line) {
+		case CommandPause:
+			paused = true
+		case CommandResume:
+			paused = false
+		}
+	}
+	return paused
+}
+
+// WatchControlPipe listen to a named pipe on windows, return pipe name or empty if pipe is unavailable
+func WatchControlPipe(component string, ch chan os.Signal) string {
+	if !util.IsWindows() {
+		return ""
+	}
+	pipeName := util.ControlPipeName(component, os.Getpid())
+	err := util.ListenControlPipe(pipeName, func(command string) {
+		handleControlCommand(command, "named pipe", ch)
+	})
+	if err != nil {
+		log.Debug().Err(err).Msgf("Named pipe unavailable, only signal file will be watched")
+		return ""
+	}
+	return pipeName
+}
+
+// PrintStopHint show how to stop current component via signal file or named pipe
+func PrintStopHint(action, signalFile, pipeName string) {
+	if IsJsonOutput() {
+		// signal file and pipe are already in json output
+		return
+	}
+	if pipeName != "" {
+		log.Info().Msgf("You can stop the %s by writing to named pipe: echo stop > %s", action, pipeName)
+	} else if util.IsWindows() {
+		log.Info().Msgf("You can stop the %s by creating a signal file:", action)
+		log.Info().Msgf("PowerShell:   \"stop\" | Out-File -FilePath %s -Encoding ASCII", signalFile)
+		log.Info().Msgf("Command Prompt: echo stop > %s", signalFile)
+	} else {
+		log.Info().Msgf("You can stop the %s by creating a signal file: echo stop >> %s", action, signalFile)
+	}
+}
@@ -0,0 +1,122 @@
+package general
+
+import (
+	"bufio"
+	"encoding/json"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SignalFileMeta metadata written as the first line of signal file when it's created
+type SignalFileMeta struct {
+	Component string    `json:"component"`
+	Service   string    `json:"service"`
+	Namespace string    `json:"namespace"`
+	Pid       int       `json:"pid"`
+	Started   time.Time `json:"started"`
+	Session   string    `json:"session"`
+}
+
+// SignalFileInfo metadata of a signal file and whether the component instance owning it still running
+type SignalFileInfo struct {
+	SignalFileMeta
+	Path  string
+	Alive bool
+}
+
+// CreateSignalFile create signal file of current process, with metadata of service it operates on
+func CreateSignalFile(signalFile, serviceName string) error {
+	header, err := json.Marshal(SignalFileMeta{
+		Component: opt.Store.Component,
+		Service:   serviceName,
+		Namespace: opt.Get().Global.Namespace,
+		Pid:       os.Getpid(),
+		Started:   time.Now(),
+		Session:   opt.Store.Session,
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(signalFile, append(header, '\n'), 0644)
+}
+
+// ReadSignalFileMeta read metadata from the first line of signal file,
+// return false if signal file not exists or it's created by old version without metadata
+func ReadSignalFileMeta(signalFile string) (*SignalFileMeta, bool) {
+	f, err := os.Open(signalFile)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil || !isSignalFileHeader(line) {
+		return nil, false
+	}
+	var meta SignalFileMeta
+	if err = json.Unmarshal([]byte(line), &meta); err != nil {
+		return nil, false
+	}
+	return &meta, true
+}
+
+// ListSignalFiles get metadata of signal files of specified components, including the ones whose process already gone,
+// metadata of signal file without header is taken from its name
+func ListSignalFiles(components ...string) []SignalFileInfo {
+	files, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		return nil
+	}
+	infos := make([]SignalFileInfo, 0)
+	for _, f := range files {
+		c, r, pid, ok := ParseSignalFileName(f.Name())
+		if !ok || (len(components) > 0 && !util.Contains(components, c)) {
+			continue
+		}
+		info := SignalFileInfo{Path: filepath.Join(os.TempDir(), f.Name())}
+		if meta, found := ReadSignalFileMeta(info.Path); found {
+			info.SignalFileMeta = *meta
+		} else {
+			info.SignalFileMeta = SignalFileMeta{Component: c, Service: r}
+			if fi, err2 := f.Info(); err2 == nil {
+				info.Started = fi.ModTime()
+			}
+		}
+		// file name is the source of truth of owner process
+		info.Pid = pid
+		info.Alive = util.IsProcessExist(pid)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Resources names of resources the component operates on, more than one when multiple services exchanged together
+func (m *SignalFileMeta) Resources() []string {
+	if m.Service == "" {
+		return nil
+	}
+	return strings.Split(m.Service, ",")
+}
+
+// signalFileMatches whether signal file belongs to component operating on specified resource, checked against
+// resource list in metadata header, or the sanitized resource name in file name if the file has no header
+func signalFileMatches(signalFile, resourceInName, resourceName string) bool {
+	meta, found := ReadSignalFileMeta(signalFile)
+	if !found {
+		return resourceInName == sanitizeResourceName(resourceName)
+	}
+	for _, r := range meta.Resources() {
+		if sanitizeResourceName(r) == sanitizeResourceName(resourceName) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSignalFileHeader whether a line of signal file is the metadata header instead of a command
+func isSignalFileHeader(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "{")
+}
@@ -0,0 +1,58 @@
+package general
+
+import (
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCreateSignalFile(t *testing.T) {
+	opt.Store.Component = "exchange"
+	opt.Store.Session = "abcdefghij"
+	opt.Get().Global.Namespace = "default"
+	signalFile := filepath.Join(t.TempDir(), "ktctl-exchange-deployment.tomcat-signal-1234")
+	require.Nil(t, CreateSignalFile(signalFile, "deployment/tomcat"))
+
+	meta, ok := ReadSignalFileMeta(signalFile)
+	require.True(t, ok)
+	require.Equal(t, "exchange", meta.Component)
+	require.Equal(t, "deployment/tomcat", meta.Service)
+	require.Equal(t, "default", meta.Namespace)
+	require.Equal(t, "abcdefghij", meta.Session)
+	require.Equal(t, os.Getpid(), meta.Pid)
+	require.WithinDuration(t, time.Now(), meta.Started, 5*time.Second)
+
+	// header is not a command, and stop is recognized only on a line by itself
+	reader := &signalFileReader{path: signalFile}
+	require.Empty(t, reader.readCommands())
+	require.Nil(t, appendToFile(signalFile, "pause stop\n"))
+	require.Equal(t, []string{"pause stop"}, reader.readCommands())
+	require.Nil(t, appendToFile(signalFile, " stop \n"))
+	require.Equal(t, []string{CommandStop}, reader.readCommands())
+}
+
+func TestReadSignalFileMetaWithoutHeader(t *testing.T) {
+	signalFile := filepath.Join(t.TempDir(), "ktctl-exchange-tomcat-signal-1234")
+	_, ok := ReadSignalFileMeta(signalFile)
+	require.False(t, ok)
+	require.Nil(t, os.WriteFile(signalFile, []byte("stop\n"), 0644))
+	_, ok = ReadSignalFileMeta(signalFile)
+	require.False(t, ok)
+}
+
+func TestSignalFileMatchesMultipleServices(t *testing.T) {
+	opt.Store.Component = "exchange"
+	signalFile := filepath.Join(t.TempDir(), "ktctl-exchange-svc-a.svc-b-signal-1234")
+	require.Nil(t, CreateSignalFile(signalFile, "svc-a,svc-b"))
+	require.True(t, signalFileMatches(signalFile, "svc-a.svc-b", "svc-a"))
+	require.True(t, signalFileMatches(signalFile, "svc-a.svc-b", "svc-b"))
+	require.False(t, signalFileMatches(signalFile, "svc-a.svc-b", "svc"))
+
+	// signal file without header is matched by name
+	oldSignalFile := filepath.Join(t.TempDir(), "ktctl-exchange-svc-a-signal-1234")
+	require.True(t, signalFileMatches(oldSignalFile, "svc-a", "svc-a"))
+	require.False(t, signalFileMatches(oldSignalFile, "svc-a", "svc-b"))
+}
@@ -0,0 +1,158 @@
+package general
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_readCommands(t *testing.T) {
+	signalFile := filepath.Join(t.TempDir(), "signal")
+	reader := &signalFileReader{path: signalFile}
+	require.Empty(t, reader.readCommands())
+
+	require.Nil(t, os.WriteFile(signalFile, []byte("stop"), 0644))
+	require.Equal(t, []string{"stop"}, reader.readCommands())
+	require.Empty(t, reader.readCommands())
+
+	require.Nil(t, os.WriteFile(signalFile, []byte("reload\r\n\nsto"), 0644))
+	require.Equal(t, []string{"reload"}, reader.readCommands())
+	require.Nil(t, appendToFile(signalFile, "p\n"))
+	require.Equal(t, []string{"stop"}, reader.readCommands())
+
+	require.Nil(t, os.WriteFile(signalFile, []byte("stop\n"), 0644))
+	require.Equal(t, []string{"stop"}, reader.readCommands())
+}
+
+func Test_readCommandsWithConcurrentAppenders(t *testing.T) {
+	signalFile := filepath.Join(t.TempDir(), "signal")
+	require.Nil(t, os.WriteFile(signalFile, []byte{}, 0644))
+	reader := &signalFileReader{path: signalFile}
+
+	writers, linesPerWriter := 5, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < linesPerWriter; i++ {
+				assert.Nil(t, appendToFile(signalFile, fmt.Sprintf("cmd-%d-%d\n", w, i)))
+			}
+		}(w)
+	}
+	done := make(chan struct{})
+	var commands []string
+	go func() {
+		defer close(done)
+		for len(commands) < writers*linesPerWriter {
+			commands = append(commands, reader.readCommands()...)
+		}
+	}()
+	wg.Wait()
+	<-done
+
+	require.Len(t, commands, writers*linesPerWriter)
+	next := make(map[string]int)
+	for _, command := range commands {
+		parts := strings.Split(command, "-")
+		require.Len(t, parts, 3, "command '%s' is broken", command)
+		require.Equal(t, fmt.Sprintf("%d", next[parts[1]]), parts[2], "command of writer %s out of order", parts[1])
+		next[parts[1]]++
+	}
+}
+
+func appendToFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(content)
+	return err
+}
+
+func TestWatchSignalFile(t *testing.T) {
+	signalFile := filepath.Join(t.TempDir(), "signal")
+	ch := make(chan os.Signal, 1)
+	done := make(chan bool)
+	go func() {
+		WatchSignalFile(signalFile, "tomcat", ch)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(signalFile)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	require.Nil(t, SendControlCommand(signalFile, CommandStop))
+	select {
+	case s := <-ch:
+		require.Equal(t, os.Interrupt, s)
+	case <-time.After(3 * time.Second):
+		t.Fatal("stop command not received")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watcher not stopped")
+	}
+}
+
+func Test_handleControlCommandWhileRegistering(t *testing.T) {
+	defer func() {
+		controlHandlersLock.Lock()
+		defer controlHandlersLock.Unlock()
+		controlHandlers = map[string]func() error{}
+	}()
+	ch := make(chan os.Signal, 1)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			RegisterControlHandler(fmt.Sprintf("cmd-%d", i), func() error { return nil })
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			assert.False(t, handleControlCommand(fmt.Sprintf("cmd-%d", i), "test", ch))
+		}
+	}()
+	wg.Wait()
+	require.True(t, handleControlCommand(CommandStop, "test", ch))
+	require.Equal(t, os.Interrupt, <-ch)
+}
+
+func Test_parseSignalFileName(t *testing.T) {
+	cases := []struct {
+		name      string
+		component string
+		resource  string
+		pid       int
+		ok        bool
+	}{
+		{name: "ktctl-connect-signal-1234", component: "connect", pid: 1234, ok: true},
+		{name: "ktctl-exchange-tomcat-signal-1234", component: "exchange", resource: "tomcat", pid: 1234, ok: true},
+		{name: "ktctl-mesh-deployment.my-app-signal-99", component: "mesh", resource: "deployment.my-app", pid: 99, ok: true},
+		{name: "ktctl-exchange-1234", ok: false},
+		{name: "ktctl-exchange-signal-abc", ok: false},
+		{name: "kt-123456.log", ok: false},
+	}
+	for _, c := range cases {
+		component, resource, pid, ok := ParseSignalFileName(c.name)
+		require.Equal(t, c.ok, ok, c.name)
+		if c.ok {
+			require.Equal(t, c.component, component, c.name)
+			require.Equal(t, c.resource, resource, c.name)
+			require.Equal(t, c.pid, pid, c.name)
+		}
+	}
+	_, resource, _, _ := ParseSignalFileName(filepath.Base(SignalFilePath("exchange", "Deployment/Tomcat")))
+	require.Equal(t, "deployment.tomcat", resource)
+}
@@ -11,29 +11,84 @@ import (
 	"github.com/rs/zerolog/log"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
 
-// CleanupWorkspace clean workspace
+var cleanupOnce sync.Once
+var cleanupHooks []func()
+
+// RegisterCleanupHook add action to run before shadow pod and service get cleaned up, e.g. removing routing rules
+// still pointing to them, hooks run on every exit path including panic and reconnect, the last registered runs first
+func RegisterCleanupHook(hook func()) {
+	cleanupHooks = append(cleanupHooks, hook)
+}
+
+// CleanupWorkspace clean workspace, only the first call takes effect
 func CleanupWorkspace() {
+	cleanupOnce.Do(cleanupWorkspace)
+}
+
+func cleanupWorkspace() {
 	log.Debug().Msgf("Cleaning workspace")
+	stopHealthServer()
+	if cancelRootCtx != nil {
+		cancelRootCtx()
+	}
 	cleanLocalFiles()
+	if opt.Get().Global.DryRun {
+		// nothing was applied to cluster or local network
+		return
+	}
+	for i := len(cleanupHooks) - 1; i >= 0; i-- {
+		cleanupHooks[i]()
+	}
 	if opt.Store.Component == util.ComponentConnect {
 		recoverGlobalHostsAndProxy()
 	}
 
 	if opt.Store.Component == util.ComponentExchange {
 		recoverExchangedTarget()
-	} else if opt.Store.Component == util.ComponentMesh {
-		recoverAutoMeshRoute()
+		cleanPassthroughPod()
+	}
+	if opt.Store.Component == util.ComponentPreview {
+		recoverPreviewedService()
+	}
+	cleanService()
+	cleanShadowPodAndConfigMap()
+	writeAuditReport()
+}
+
+// RevertSetup undo cluster changes made by a failed setup attempt, and forget them, so that setup can run again
+func RevertSetup() {
+	if opt.Store.Component == util.ComponentExchange {
+		recoverExchangedTarget()
+		cleanPassthroughPod()
 	}
 	cleanService()
 	cleanShadowPodAndConfigMap()
+	if opt.Store.Shadow != "" {
+		for _, shadow := range strings.Split(opt.Store.Shadow, ",") {
+			_ = os.Remove(util.PrivateKeyPath(shadow))
+		}
+	}
+	opt.Store.Shadow = ""
+	opt.Store.Router = ""
+	opt.Store.Service = ""
+	opt.Store.Origin = ""
+	opt.Store.Replicas = 0
+	opt.Store.Passthrough = ""
+	opt.Store.RoutingBackend = ""
 }
 
 func recoverGlobalHostsAndProxy() {
+	if opt.Get().Connect.Mode == util.ConnectModeSocks5 {
+		// local dns is not touched in socks5 mode
+		return
+	}
 	if strings.HasPrefix(opt.Get().Connect.DnsMode, util.DnsModeHosts) ||
 		strings.HasPrefix(opt.Get().Connect.DnsMode, util.DnsModeLocalDns) {
 		log.Debug().Msg("Dropping hosts records ...")
@@ -44,12 +99,17 @@ func recoverGlobalHostsAndProxy() {
 			log.Debug().Err(err).Msgf("Failed to restore route table")
 		}
 	}
+	if opt.Get().Connect.FlushDnsOnStop {
+		log.Debug().Msgf("Flushing dns cache of %s domains ...", opt.Get().Connect.ClusterDomain)
+		dns.FlushDnsCache()
+	}
 }
 
 func cleanLocalFiles() {
 	if opt.Store.Component == "" {
 		return
 	}
+	removeReadyFile()
 	pidFile := fmt.Sprintf("%s/%s-%d.pid", util.KtPidDir, opt.Store.Component, os.Getpid())
 	if err := os.Remove(pidFile); os.IsNotExist(err) {
 		log.Debug().Msgf("Pid file %s not exist", pidFile)
@@ -71,6 +131,13 @@ func cleanLocalFiles() {
 			}
 		}
 	}
+
+	certFile := GeneratedCertPath()
+	if err := os.Remove(certFile); err != nil && !os.IsNotExist(err) {
+		log.Debug().Err(err).Msgf("Remove cert file %s failed", certFile)
+	} else if err == nil {
+		log.Info().Msgf("Removed cert file %s", certFile)
+	}
 }
 
 func recoverExchangedTarget() {
@@ -79,67 +146,40 @@ func recoverExchangedTarget() {
 		return
 	}
 	if opt.Get().Exchange.Mode == util.ExchangeModeScale {
-		log.Info().Msgf("Recovering origin deployment %s", opt.Store.Origin)
-		err := cluster.Ins().ScaleTo(opt.Store.Origin, opt.Get().Global.Namespace, &opt.Store.Replicas)
+		if !opt.Get().Exchange.RestoreOnExit {
+			keepWorkloadScaledDown()
+			return
+		}
+		log.Info().Msgf("Recovering origin %s %s", kindOfOrigin(), opt.Store.Origin)
+		err := ScaleWorkloadTo(opt.Store.OriginKind, opt.Store.Origin, opt.Get().Global.Namespace, &opt.Store.Replicas)
 		if err != nil {
-			log.Error().Err(err).Msgf("Scale deployment %s to %d failed",
-				opt.Store.Origin, opt.Store.Replicas)
+			log.Error().Err(err).Msgf("Scale %s %s to %d failed",
+				kindOfOrigin(), opt.Store.Origin, opt.Store.Replicas)
 		}
 		// wait for scale complete
 		ch := make(chan os.Signal, 1)
 		signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
 		go func() {
-			waitDeploymentRecoverComplete()
+			waitWorkloadRecoverComplete()
 			ch <- os.Interrupt
 		}()
 		_ = <-ch
 	} else if opt.Get().Exchange.Mode == util.ExchangeModeSelector {
-		RecoverOriginalService(opt.Store.Origin, opt.Get().Global.Namespace)
-		log.Info().Msgf("Original service %s recovered", opt.Store.Origin)
-	}
-}
-
-func recoverAutoMeshRoute() {
-	if opt.Store.Router != "" {
-		routerPod, err := cluster.Ins().GetPod(opt.Store.Router, opt.Get().Global.Namespace)
-		if err != nil {
-			log.Error().Err(err).Msgf("Router pod has been removed unexpectedly")
-			// in case of router pod gone, try recover origin service via runtime store
-			if opt.Store.Origin != "" {
-				recoverService(opt.Store.Origin)
-			}
-			return
-		}
-		if shouldDelRouter, err2 := cluster.Ins().DecreasePodRef(opt.Store.Router, opt.Get().Global.Namespace); err2 != nil {
-			log.Error().Err(err2).Msgf("Decrease router pod %s reference failed", opt.Store.Shadow)
-		} else if shouldDelRouter {
-			routerConfig := routerPod.Annotations[util.KtConfig]
-			config := util.String2Map(routerConfig)
-			recoverService(config["service"])
-			if err = cluster.Ins().RemovePod(opt.Store.Router, opt.Get().Global.Namespace); err != nil {
-				log.Warn().Err(err).Msgf("Failed to remove router pod")
-			}
-		} else {
-			stdout, stderr, err3 := cluster.Ins().ExecInPod(util.DefaultContainer, opt.Store.Router, opt.Get().Global.Namespace,
-				util.RouterBin, "remove", opt.Store.Mesh)
-			log.Debug().Msgf("Stdout: %s", stdout)
-			log.Debug().Msgf("Stderr: %s", stderr)
-			if err3 != nil {
-				log.Warn().Err(err3).Msgf("Failed to remove version %s from router pod", opt.Store.Mesh)
-			}
+		// more than one service could be exchanged by the same process
+		for _, origin := range strings.Split(opt.Store.Origin, ",") {
+			RecoverOriginalService(origin, opt.Get().Global.Namespace)
+			log.Info().Msgf("Original service %s recovered", origin)
 		}
 	}
 }
 
-func recoverService(originSvcName string) {
-	RecoverOriginalService(originSvcName, opt.Get().Global.Namespace)
-	log.Info().Msgf("Original service %s recovered", originSvcName)
-
-	stuntmanSvcName := originSvcName + util.StuntmanServiceSuffix
-	if err := cluster.Ins().RemoveService(stuntmanSvcName, opt.Get().Global.Namespace); err != nil {
-		log.Error().Err(err).Msgf("Failed to remove stuntman service %s", stuntmanSvcName)
+// recoverPreviewedService let existing service taken over by preview select its original pods again
+func recoverPreviewedService() {
+	if opt.Store.Origin == "" {
+		return
 	}
-	log.Info().Msgf("Stuntman service %s removed", stuntmanSvcName)
+	RecoverOriginalService(opt.Store.Origin, opt.Get().Global.Namespace)
+	log.Info().Msgf("Original service %s recovered", opt.Store.Origin)
 }
 
 func RecoverOriginalService(svcName, namespace string) {
@@ -169,24 +209,53 @@ func RecoverOriginalService(svcName, namespace string) {
 	}
 }
 
-func waitDeploymentRecoverComplete() {
+// keepWorkloadScaledDown skip recovering origin workload, but record its replicas for 'ktctl clean' to recover it later
+func keepWorkloadScaledDown() {
+	err := AnnotateWorkload(opt.Store.OriginKind, opt.Store.Origin, opt.Get().Global.Namespace,
+		util.KtReplicas, strconv.Itoa(int(opt.Store.Replicas)))
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to record origin replicas of %s %s", kindOfOrigin(), opt.Store.Origin)
+		return
+	}
+	log.Info().Msgf("%s %s is kept scaled down, run 'ktctl clean' to scale it back to %d replicas",
+		KindTitle(opt.Store.OriginKind), opt.Store.Origin, opt.Store.Replicas)
+}
+
+func waitWorkloadRecoverComplete() {
 	ok := false
 	counts := opt.Get().Exchange.RecoverWaitTime / 5
 	for i := 0; i < counts; i++ {
-		deployment, err := cluster.Ins().GetDeployment(opt.Store.Origin, opt.Get().Global.Namespace)
+		workload, err := GetWorkload(opt.Store.OriginKind, opt.Store.Origin, opt.Get().Global.Namespace)
 		if err != nil {
-			log.Error().Err(err).Msgf("Cannot fetch original deployment %s", opt.Store.Origin)
+			log.Error().Err(err).Msgf("Cannot fetch original %s %s", kindOfOrigin(), opt.Store.Origin)
 			break
-		} else if deployment.Status.ReadyReplicas == opt.Store.Replicas {
+		} else if workload.ReadyReplicas == opt.Store.Replicas {
 			ok = true
 			break
 		} else {
-			log.Info().Msgf("Wait for deployment %s recover ...", opt.Store.Origin)
+			log.Info().Msgf("Wait for %s %s recover ...", kindOfOrigin(), opt.Store.Origin)
 			time.Sleep(5 * time.Second)
 		}
 	}
 	if !ok {
-		log.Warn().Msgf("Deployment %s recover timeout", opt.Store.Origin)
+		log.Warn().Msgf("%s %s recover timeout", KindTitle(opt.Store.OriginKind), opt.Store.Origin)
+	}
+}
+
+// kindOfOrigin kind of workload scaled by exchange, deployment if not recorded
+func kindOfOrigin() string {
+	if opt.Store.OriginKind == "" {
+		return KindDeployment
+	}
+	return opt.Store.OriginKind
+}
+
+func cleanPassthroughPod() {
+	if opt.Store.Passthrough != "" {
+		log.Info().Msgf("Cleaning passthrough pod %s", opt.Store.Passthrough)
+		if err := cluster.Ins().RemovePod(opt.Store.Passthrough, opt.Get().Global.Namespace); err != nil {
+			log.Error().Err(err).Msgf("Delete passthrough pod %s failed", opt.Store.Passthrough)
+		}
 	}
 }
 
@@ -204,8 +273,9 @@ func cleanShadowPodAndConfigMap() {
 	var err error
 	if opt.Store.Shadow != "" {
 		shouldDelWithShared := false
-		if opt.Get().Connect.ShareShadow {
-			// There is always exactly one shadow pod or deployment for connect
+		shared := cluster.IsShadowShared()
+		if shared {
+			// There is always exactly one shadow pod or deployment for connect or exchange
 			if opt.Get().Global.UseShadowDeployment {
 				shouldDelWithShared, err = cluster.Ins().DecreaseDeploymentRef(opt.Store.Shadow, opt.Get().Global.Namespace)
 			} else {
@@ -215,7 +285,7 @@ func cleanShadowPodAndConfigMap() {
 				log.Error().Err(err).Msgf("Decrease shadow daemon %s ref count failed", opt.Store.Shadow)
 			}
 		}
-		if shouldDelWithShared || !opt.Get().Connect.ShareShadow {
+		if shouldDelWithShared || !shared {
 			for _, shadow := range strings.Split(opt.Store.Shadow, ",") {
 				log.Info().Msgf("Cleaning configmap %s", shadow)
 				err = cluster.Ins().RemoveConfigMap(shadow, opt.Get().Global.Namespace)
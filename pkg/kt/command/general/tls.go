@@ -0,0 +1,105 @@
+package general
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"math/big"
+	"os"
+	"time"
+)
+
+// SetupTlsTermination let tls connections to service terminated by ktctl, use specified cert and key,
+// or a generated self-signed cert matching service dns names when cert file is empty
+func SetupTlsTermination(serviceName, certFile, keyFile string) error {
+	var cert tls.Certificate
+	var err error
+	if certFile != "" {
+		if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return fmt.Errorf("failed to load tls cert and key: %s", err)
+		}
+		log.Info().Msgf("Terminating tls with cert %s", certFile)
+	} else {
+		if cert, err = generateSelfSignedCert(serviceDnsNames(serviceName)); err != nil {
+			return fmt.Errorf("failed to generate tls cert: %s", err)
+		}
+		path := GeneratedCertPath()
+		if err = util.WriteFileAtomic(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}),
+			0644); err != nil {
+			return err
+		}
+		log.Info().Msgf("Terminating tls with self-signed cert %s", path)
+	}
+	sshchannel.SetupTlsTermination(&tls.Config{Certificates: []tls.Certificate{cert}})
+	return nil
+}
+
+// GeneratedCertPath path of self-signed cert generated by current process
+func GeneratedCertPath() string {
+	return fmt.Sprintf("%s/%s-%d%s", util.KtKeyDir, opt.Store.Component, os.Getpid(), util.PostfixTlsCert)
+}
+
+// CheckTlsOptions verify tls termination options
+func CheckTlsOptions(terminate bool, certFile, keyFile string) error {
+	if !terminate {
+		if certFile != "" || keyFile != "" {
+			return fmt.Errorf("--tlsCert and --tlsKey are only applicable when --tlsTerminate is specified")
+		}
+		return nil
+	}
+	if (certFile == "") != (keyFile == "") {
+		return fmt.Errorf("--tlsCert and --tlsKey should be specified together")
+	}
+	if certFile != "" {
+		if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return fmt.Errorf("invalid tls cert or key: %s", err)
+		}
+	}
+	return nil
+}
+
+// serviceDnsNames names to access a service inside cluster
+func serviceDnsNames(serviceName string) []string {
+	ns := opt.Get().Global.Namespace
+	return []string{
+		serviceName,
+		fmt.Sprintf("%s.%s", serviceName, ns),
+		fmt.Sprintf("%s.%s.svc", serviceName, ns),
+		fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, ns),
+	}
+}
+
+func generateSelfSignedCert(dnsNames []string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: dnsNames[0], Organization: []string{"kt-connect"}},
+		DNSNames:              dnsNames,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(30 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
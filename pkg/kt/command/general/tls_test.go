@@ -0,0 +1,23 @@
+package general
+
+import (
+	"crypto/x509"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_generateSelfSignedCert(t *testing.T) {
+	cert, err := generateSelfSignedCert([]string{"tomcat", "tomcat.default"})
+	require.NoError(t, err)
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, []string{"tomcat", "tomcat.default"}, parsed.DNSNames)
+	require.NoError(t, parsed.VerifyHostname("tomcat.default"))
+	require.Error(t, parsed.VerifyHostname("nginx.default"))
+
+	require.NoError(t, CheckTlsOptions(false, "", ""))
+	require.NoError(t, CheckTlsOptions(true, "", ""))
+	require.Error(t, CheckTlsOptions(false, "a.crt", "a.key"))
+	require.Error(t, CheckTlsOptions(true, "a.crt", ""))
+	require.Error(t, CheckTlsOptions(true, "not-exist.crt", "not-exist.key"))
+}
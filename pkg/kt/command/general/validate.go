@@ -0,0 +1,165 @@
+package general
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Check a pre-flight check item
+type Check struct {
+	Name string
+	Run  func() error
+}
+
+// RunChecks run all pre-flight checks and report result of each of them, return error if any check failed
+func RunChecks(checks []Check) error {
+	failed := 0
+	for _, c := range checks {
+		if err := c.Run(); err != nil {
+			log.Error().Msgf("[FAIL] %s: %s", c.Name, err)
+			failed++
+		} else {
+			log.Info().Msgf("[PASS] %s", c.Name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	log.Info().Msgf("All %d checks passed", len(checks))
+	return nil
+}
+
+// CheckExposePorts verify format of '--expose' option value
+func CheckExposePorts(exposePorts string) error {
+	for _, exposePort := range strings.Split(exposePorts, ",") {
+		if _, _, err := util.ParsePortMapping(exposePort); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckLocalPorts verify all local ports to expose are listened, on specified local address if not empty
+func CheckLocalPorts(exposePorts, localAddr string) error {
+	_, broken, err := util.CheckLocalPortsOn(exposePorts, localAddr)
+	if err != nil {
+		return err
+	}
+	onAddr := ""
+	if localAddr != "" {
+		onAddr = " of " + localAddr
+	}
+	if len(broken) == 1 {
+		return fmt.Errorf("no application is running on port %d%s", broken[0], onAddr)
+	} else if len(broken) > 1 {
+		ports := make([]string, 0, len(broken))
+		for _, p := range broken {
+			ports = append(ports, strconv.Itoa(p))
+		}
+		return fmt.Errorf("no application is running on ports %s%s", strings.Join(ports, ", "), onAddr)
+	}
+	return nil
+}
+
+// DetectExposePort find the only non-system tcp port listened on current machine, as port to expose
+func DetectExposePort() (string, error) {
+	ports, err := util.ListeningLocalPorts()
+	if err != nil {
+		return "", err
+	}
+	candidates := make([]string, 0)
+	for _, p := range ports {
+		// ports below 1024 are usually taken by system services
+		if p >= 1024 {
+			candidates = append(candidates, strconv.Itoa(p))
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no listening local port found, please specify port with '--expose'")
+	} else if len(candidates) > 1 {
+		return "", fmt.Errorf("multiple listening local ports found (%s), please specify port with '--expose'",
+			strings.Join(candidates, ", "))
+	}
+	log.Info().Msgf("Local port %s detected, using it as port to expose", candidates[0])
+	return candidates[0], nil
+}
+
+// CheckLocalAddr verify local address to forward connections to is an ip of current machine
+func CheckLocalAddr(localAddr string) error {
+	if localAddr == "" {
+		return nil
+	}
+	ip := net.ParseIP(localAddr)
+	if ip == nil {
+		return fmt.Errorf("invalid local address '%s', should be an ip address", localAddr)
+	}
+	if ip.IsLoopback() {
+		return nil
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return fmt.Errorf("failed to list local addresses: %s", err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("local address '%s' is not assigned to any network interface of current machine", localAddr)
+}
+
+// LocalAddr local address to forward connections of expose ports to, empty for default
+func LocalAddr() string {
+	switch opt.Store.Component {
+	case util.ComponentExchange:
+		return opt.Get().Exchange.LocalAddr
+	case util.ComponentPreview:
+		return opt.Get().Preview.LocalAddr
+	}
+	return ""
+}
+
+// CheckPermissions verify current user is allowed to perform all specified operations in current namespace
+func CheckPermissions(rules []cluster.PermissionRule) error {
+	denied := make([]string, 0)
+	for _, rule := range rules {
+		allowed, err := cluster.Ins().CanI(rule, opt.Get().Global.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to review permission: %s", err)
+		}
+		if !allowed {
+			resource := rule.Resource
+			if rule.Subresource != "" {
+				resource = resource + "/" + rule.Subresource
+			}
+			denied = append(denied, rule.Verb+" "+resource)
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("not allowed to %s in namespace %s", strings.Join(denied, ", "), opt.Get().Global.Namespace)
+	}
+	return nil
+}
+
+// ShadowPermissions permissions required for creating shadow pod or deployment
+func ShadowPermissions() []cluster.PermissionRule {
+	rules := []cluster.PermissionRule{
+		{Verb: "create", Resource: "configmaps"},
+		{Verb: "delete", Resource: "configmaps"},
+		{Verb: "create", Resource: "pods", Subresource: "portforward"},
+	}
+	if opt.Get().Global.UseShadowDeployment {
+		return append(rules,
+			cluster.PermissionRule{Verb: "create", Group: "apps", Resource: "deployments"},
+			cluster.PermissionRule{Verb: "delete", Group: "apps", Resource: "deployments"})
+	}
+	return append(rules,
+		cluster.PermissionRule{Verb: "create", Resource: "pods"},
+		cluster.PermissionRule{Verb: "delete", Resource: "pods"})
+}
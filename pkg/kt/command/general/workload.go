@@ -0,0 +1,259 @@
+package general
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	appV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+)
+
+// Resource types accepted in '<type>/<name>' target
+const (
+	KindService     = "service"
+	KindPod         = "pod"
+	KindDeployment  = "deployment"
+	KindStatefulSet = "statefulset"
+	KindDaemonSet   = "daemonset"
+)
+
+var resourceTypeAliases = map[string]string{
+	"svc":          KindService,
+	"services":     KindService,
+	"po":           KindPod,
+	"pods":         KindPod,
+	"deploy":       KindDeployment,
+	"deployments":  KindDeployment,
+	"sts":          KindStatefulSet,
+	"statefulsets": KindStatefulSet,
+	"ds":           KindDaemonSet,
+	"daemonsets":   KindDaemonSet,
+}
+
+// Workload pod controller of a target, regardless of its kind
+type Workload struct {
+	Kind          string
+	Name          string
+	Replicas      int32
+	ReadyReplicas int32
+	Selector      map[string]string
+	Template      coreV1.PodTemplateSpec
+	Annotations   map[string]string
+}
+
+// NormalizeResourceType convert short and plural names of resource type to its kind, e.g. 'sts' to 'statefulset'
+func NormalizeResourceType(resourceType string) string {
+	resourceType = strings.ToLower(resourceType)
+	if kind, exists := resourceTypeAliases[resourceType]; exists {
+		return kind
+	}
+	return resourceType
+}
+
+// IsWorkloadKind whether resource type is a pod controller
+func IsWorkloadKind(kind string) bool {
+	return kind == KindDeployment || kind == KindStatefulSet || kind == KindDaemonSet
+}
+
+// KindTitle name of kind used in messages and reports, e.g. 'StatefulSet'
+func KindTitle(kind string) string {
+	switch kind {
+	case KindStatefulSet:
+		return "StatefulSet"
+	case KindDaemonSet:
+		return "DaemonSet"
+	case "", KindDeployment:
+		return "Deployment"
+	}
+	return util.Capitalize(kind)
+}
+
+// GetWorkload fetch deployment, statefulset or daemonset as workload
+func GetWorkload(kind, name, namespace string) (*Workload, error) {
+	var workload *Workload
+	err := RetryOnTransientError("fetch "+kind+" "+name, func() error {
+		switch kind {
+		case "", KindDeployment:
+			app, err := cluster.Ins().GetDeployment(name, namespace)
+			if err != nil {
+				return err
+			}
+			workload = fromDeployment(app)
+		case KindStatefulSet:
+			sts, err := cluster.Ins().GetStatefulSet(name, namespace)
+			if err != nil {
+				return err
+			}
+			workload = fromStatefulSet(sts)
+		case KindDaemonSet:
+			ds, err := cluster.Ins().GetDaemonSet(name, namespace)
+			if err != nil {
+				return err
+			}
+			workload = fromDaemonSet(ds)
+		default:
+			return fmt.Errorf("invalid workload kind: %s", kind)
+		}
+		return nil
+	})
+	if err != nil && k8sErrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%s '%s' is not found in namespace %s", kind, name, namespace)
+	}
+	return workload, err
+}
+
+// GetWorkloadByResourceName get the workload of target, for service it is the first workload whose pods are selected
+func GetWorkloadByResourceName(resourceName, namespace string) (*Workload, error) {
+	resourceType, name, err := ParseResourceName(resourceName)
+	if err != nil {
+		return nil, err
+	}
+	if IsWorkloadKind(resourceType) {
+		return GetWorkload(resourceType, name, namespace)
+	} else if resourceType == KindService {
+		svc, err2 := GetServiceWithRetry(name, namespace)
+		if err2 != nil {
+			if k8sErrors.IsNotFound(err2) {
+				return nil, fmt.Errorf("service '%s' is not found in namespace %s", name, namespace)
+			}
+			return nil, err2
+		}
+		return getWorkloadByService(svc, namespace)
+	}
+	return nil, fmt.Errorf("invalid resource type: %s", resourceType)
+}
+
+// CheckScalable verify workload can be exchanged by scaling it down
+func CheckScalable(workload *Workload) error {
+	if workload.Kind == KindDaemonSet {
+		return fmt.Errorf("daemonset '%s' runs one pod on each node and cannot be scaled, "+
+			"use %s or %s mode instead", workload.Name, util.ExchangeModeSelector, util.ExchangeModeEphemeral)
+	}
+	return nil
+}
+
+// ScaleWorkloadTo scale deployment or statefulset to specified replicas
+func ScaleWorkloadTo(kind, name, namespace string, replicas *int32) error {
+	switch kind {
+	case "", KindDeployment:
+		return cluster.Ins().ScaleTo(name, namespace, replicas)
+	case KindStatefulSet:
+		return cluster.Ins().ScaleStatefulSetTo(name, namespace, replicas)
+	}
+	return fmt.Errorf("%s '%s' cannot be scaled", kind, name)
+}
+
+// AnnotateWorkload add or update an annotation of deployment or statefulset
+func AnnotateWorkload(kind, name, namespace, key, value string) error {
+	switch kind {
+	case "", KindDeployment:
+		app, err := cluster.Ins().GetDeployment(name, namespace)
+		if err != nil {
+			return err
+		}
+		app.Annotations = util.MapPut(app.Annotations, key, value)
+		_, err = cluster.Ins().UpdateDeployment(app)
+		return err
+	case KindStatefulSet:
+		sts, err := cluster.Ins().GetStatefulSet(name, namespace)
+		if err != nil {
+			return err
+		}
+		sts.Annotations = util.MapPut(sts.Annotations, key, value)
+		_, err = cluster.Ins().UpdateStatefulSet(sts)
+		return err
+	}
+	return fmt.Errorf("cannot annotate %s '%s'", kind, name)
+}
+
+func getWorkloadByService(svc *coreV1.Service, namespace string) (*Workload, error) {
+	if len(svc.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service '%s' has no selector, cannot find its workload", svc.Name)
+	}
+	apps, err := cluster.Ins().GetAllDeploymentInNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps.Items {
+		if util.MapContains(svc.Spec.Selector, app.Spec.Template.Labels) {
+			log.Info().Msgf("Using first matched deployment '%s'", app.Name)
+			return fromDeployment(&app), nil
+		}
+	}
+	stsList, err := cluster.Ins().GetAllStatefulSetInNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, sts := range stsList.Items {
+		if util.MapContains(svc.Spec.Selector, sts.Spec.Template.Labels) {
+			log.Info().Msgf("Using first matched statefulset '%s'", sts.Name)
+			return fromStatefulSet(&sts), nil
+		}
+	}
+	dsList, err := cluster.Ins().GetAllDaemonSetInNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, ds := range dsList.Items {
+		if util.MapContains(svc.Spec.Selector, ds.Spec.Template.Labels) {
+			log.Info().Msgf("Using first matched daemonset '%s'", ds.Name)
+			return fromDaemonSet(&ds), nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find workload for service '%s', with selector '%v'", svc.Name, svc.Spec.Selector)
+}
+
+func fromDeployment(app *appV1.Deployment) *Workload {
+	return &Workload{
+		Kind:          KindDeployment,
+		Name:          app.Name,
+		Replicas:      replicasOf(app.Spec.Replicas),
+		ReadyReplicas: app.Status.ReadyReplicas,
+		Selector:      matchLabelsOf(app.Spec.Selector),
+		Template:      app.Spec.Template,
+		Annotations:   app.Annotations,
+	}
+}
+
+func fromStatefulSet(sts *appV1.StatefulSet) *Workload {
+	return &Workload{
+		Kind:          KindStatefulSet,
+		Name:          sts.Name,
+		Replicas:      replicasOf(sts.Spec.Replicas),
+		ReadyReplicas: sts.Status.ReadyReplicas,
+		Selector:      matchLabelsOf(sts.Spec.Selector),
+		Template:      sts.Spec.Template,
+		Annotations:   sts.Annotations,
+	}
+}
+
+func fromDaemonSet(ds *appV1.DaemonSet) *Workload {
+	return &Workload{
+		Kind:          KindDaemonSet,
+		Name:          ds.Name,
+		Replicas:      ds.Status.DesiredNumberScheduled,
+		ReadyReplicas: ds.Status.NumberReady,
+		Selector:      matchLabelsOf(ds.Spec.Selector),
+		Template:      ds.Spec.Template,
+		Annotations:   ds.Annotations,
+	}
+}
+
+// replicasOf replicas field not specified means 1
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func matchLabelsOf(selector *metav1.LabelSelector) map[string]string {
+	if selector == nil {
+		return map[string]string{}
+	}
+	return selector.MatchLabels
+}
@@ -0,0 +1,40 @@
+package general
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseResourceName(t *testing.T) {
+	tests := []struct {
+		resourceName string
+		kind         string
+		name         string
+	}{
+		{"tomcat", KindService, "tomcat"},
+		{"svc/tomcat", KindService, "tomcat"},
+		{"deploy/tomcat", KindDeployment, "tomcat"},
+		{"Deployment/tomcat", KindDeployment, "tomcat"},
+		{"sts/mysql", KindStatefulSet, "mysql"},
+		{"statefulsets/mysql", KindStatefulSet, "mysql"},
+		{"ds/agent", KindDaemonSet, "agent"},
+		{"pod/tomcat-5d7bb8c5f7-x2kzn", KindPod, "tomcat-5d7bb8c5f7-x2kzn"},
+		{"job/migrate", "job", "migrate"},
+	}
+	for _, tt := range tests {
+		kind, name, err := ParseResourceName(tt.resourceName)
+		require.NoError(t, err, tt.resourceName)
+		require.Equal(t, tt.kind, kind, tt.resourceName)
+		require.Equal(t, tt.name, name, tt.resourceName)
+	}
+	_, _, err := ParseResourceName("apps/deploy/tomcat")
+	require.Error(t, err)
+}
+
+func TestCheckScalable(t *testing.T) {
+	require.NoError(t, CheckScalable(&Workload{Kind: KindDeployment, Name: "tomcat"}))
+	require.NoError(t, CheckScalable(&Workload{Kind: KindStatefulSet, Name: "mysql"}))
+	err := CheckScalable(&Workload{Kind: KindDaemonSet, Name: "agent"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "daemonset 'agent'")
+}
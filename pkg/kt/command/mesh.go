@@ -1,10 +1,9 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"time"
 
 	"strings"
 
@@ -27,9 +26,15 @@ func NewMeshCommand() *cobra.Command {
 			} else if len(args) > 1 {
 				return fmt.Errorf("too many service names are spcified (%s), should be one", strings.Join(args, ","))
 			}
-			return general.Prepare()
+			if err := general.Prepare(); err != nil {
+				return err
+			}
+			return general.LoadServiceProfile(cmd, args)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opt.Get().Global.ValidateOnly {
+				return validateMesh(args[0])
+			}
 			return Mesh(args[0])
 		},
 		Example: "ktctl mesh <service-name> [command options]",
@@ -42,20 +47,25 @@ func NewMeshCommand() *cobra.Command {
 
 //Mesh exchange kubernetes workload
 func Mesh(resourceName string) error {
-	ch, err := general.SetupProcess(util.ComponentMesh)
+	ctx, ch, err := general.SetupProcess(util.ComponentMesh)
 	if err != nil {
 		return err
 	}
 
-	if opt.Get().Mesh.SkipPortChecking {
-		if port := util.FindBrokenLocalPort(opt.Get().Mesh.Expose); port != "" {
-			return fmt.Errorf("no application is running on port %s", port)
+	if !opt.Get().Mesh.SkipPortChecking {
+		if err = general.CheckLocalPorts(opt.Get().Mesh.Expose, ""); err != nil {
+			return err
 		}
 	}
 
+	if err = mesh.CheckOptions(); err != nil {
+		return err
+	}
+
 	// Setup signal file watcher
-	signalFile := filepath.Join(os.TempDir(), fmt.Sprintf("ktctl-mesh-signal-%d", os.Getpid()))
-	go watchMeshSignalFile(signalFile, ch)
+	signalFile := general.SignalFilePath(util.ComponentMesh, resourceName)
+	go general.WatchSignalFile(signalFile, resourceName, ch)
+	pipeName := general.WatchControlPipe(util.ComponentMesh, ch)
 
 	// Get service to mesh
 	svc, err := general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
@@ -71,15 +81,22 @@ func Mesh(resourceName string) error {
 		return fmt.Errorf("target port %s not exists in service %s", port, svc.Name)
 	}
 
+	// Routing rules must be removed before shadow pod and service get cleaned up
+	general.RegisterCleanupHook(mesh.Teardown)
+
 	log.Info().Msgf("Using %s mode", opt.Get().Mesh.Mode)
-	if opt.Get().Mesh.Mode == util.MeshModeManual {
-		err = mesh.ManualMesh(svc)
-	} else if opt.Get().Mesh.Mode == util.MeshModeAuto {
-		err = mesh.AutoMesh(svc)
-	} else {
-		err = fmt.Errorf("invalid mesh method '%s', supportted are %s, %s", opt.Get().Mesh.Mode,
-			util.MeshModeAuto, util.MeshModeManual)
-	}
+	var result *general.SetupResult
+	err = general.RunSetupWithTimeout(ctx, util.ComponentMesh, func(ctx context.Context) (setupErr error) {
+		if opt.Get().Mesh.Mode == util.MeshModeManual {
+			result, setupErr = mesh.ManualMesh(svc)
+		} else if opt.Get().Mesh.Mode == util.MeshModeAuto {
+			result, setupErr = mesh.AutoMesh(svc)
+		} else {
+			setupErr = fmt.Errorf("invalid mesh method '%s', supportted are %s, %s", opt.Get().Mesh.Mode,
+				util.MeshModeAuto, util.MeshModeManual)
+		}
+		return
+	})
 
 	// Move signal file cleanup to deferred function to ensure it's only cleaned up at the end
 	defer os.RemoveAll(signalFile)
@@ -87,40 +104,48 @@ func Mesh(resourceName string) error {
 	if err != nil {
 		return err
 	}
+	if opt.Get().Global.DryRun {
+		general.PrintDryRunSummary(result)
+		return nil
+	}
 
 	log.Info().Msg("---------------------------------------------------------------")
 	log.Info().Msgf(" Now all request to %s '%s' will be redirected to local", svc.Kind, svc.Name)
 	log.Info().Msg("---------------------------------------------------------------")
 
-	if util.IsWindows() {
-		log.Info().Msgf("You can stop the mesh by creating a signal file:")
-		log.Info().Msgf("PowerShell:   \"stop\" | Out-File -FilePath %s -Encoding ASCII", signalFile)
-		log.Info().Msgf("Command Prompt: echo stop > %s", signalFile)
-	} else {
-		log.Info().Msgf("You can stop the mesh by creating a signal file: echo stop > %s", signalFile)
-	}
+	general.MarkReady(result, signalFile, pipeName)
+	general.PrintSetupResult(result, signalFile, pipeName)
+	general.PrintStopHint("mesh", signalFile, pipeName)
 
 	// watch background process, clean the workspace and exit if background process occur exception
-	s := <-ch
-	log.Info().Msgf("Terminal Signal is %s", s)
+	general.WaitForStop(ctx, ch)
+	general.WaitConnectionsDrained(opt.Get().Mesh.DrainTimeout)
 
 	return nil
 }
 
-func watchMeshSignalFile(signalFile string, ch chan os.Signal) {
-	// Create the signal file to indicate mesh is ready
-	os.Create(signalFile)
-
-	for {
-		time.Sleep(1 * time.Second)
-
-		// Check if signal file contains "stop"
-		if content, err := os.ReadFile(signalFile); err == nil {
-			if strings.TrimSpace(string(content)) == "stop" {
-				// Send interrupt signal to the main routine
-				ch <- os.Interrupt
-				return
+func validateMesh(resourceName string) error {
+	return general.RunChecks([]general.Check{
+		{Name: "Mesh options", Run: mesh.CheckOptions},
+		{Name: "Local ports", Run: func() error {
+			if opt.Get().Mesh.SkipPortChecking {
+				return nil
 			}
-		}
-	}
+			return general.CheckLocalPorts(opt.Get().Mesh.Expose, "")
+		}},
+		{Name: "Routing backend", Run: mesh.CheckRoutingBackend},
+		{Name: "Target service", Run: func() error {
+			svc, err := general.GetServiceByResourceName(resourceName, opt.Get().Global.Namespace)
+			if err != nil {
+				return err
+			}
+			if port := util.FindInvalidRemotePort(opt.Get().Mesh.Expose, general.GetTargetPorts(svc)); port != "" {
+				return fmt.Errorf("target port %s not exists in service %s", port, svc.Name)
+			}
+			return nil
+		}},
+		{Name: "Permissions", Run: func() error {
+			return general.CheckPermissions(mesh.Permissions())
+		}},
+	})
 }
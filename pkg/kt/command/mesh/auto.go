@@ -8,28 +8,58 @@ import (
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
 	coreV1 "k8s.io/api/core/v1"
-	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func AutoMesh(svc *coreV1.Service) error {
-	// Lock service to avoid conflict, must be first step
+func AutoMesh(svc *coreV1.Service) (*general.SetupResult, error) {
+	// Verify before anything changed in cluster
+	if err := checkEndpoints(svc.Name); err != nil {
+		return nil, err
+	}
+
+	// Lock service to avoid conflict, must be first step of changes
 	svc, err := general.LockService(svc.Name, opt.Get().Global.Namespace, 0)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer general.UnlockService(svc.Name, opt.Get().Global.Namespace)
 
 	if svc.Annotations != nil && svc.Annotations[util.KtSelector] != "" && svc.Spec.Selector[util.KtRole] == util.RoleExchangeShadow {
-		return fmt.Errorf("another user%s is exchanging service '%s', cannot apply mesh",
+		return nil, fmt.Errorf("another user%s is exchanging service '%s', cannot apply mesh",
 			general.GetOccupiedUser(svc.Spec.Selector), svc.Name)
 	}
 
+	backend, err := getRoutingBackend(opt.Get().Mesh.RoutingBackend)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkBackendOptions(backend); err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Using %s routing backend", backend.Name())
+
+	fallbackCodes, err := parseFallbackCodes(opt.Get().Mesh.FallbackOn)
+	if err != nil {
+		return nil, err
+	}
+	cookieMark, err := parseCookieMark(opt.Get().Mesh.MeshCookie)
+	if err != nil {
+		return nil, err
+	}
+	grpcMethods, err := parseGrpcMethods(opt.Get().Mesh.GrpcMethod)
+	if err != nil {
+		return nil, err
+	}
+	baggage, err := parseTraceTag(opt.Get().Mesh.TraceTag)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse or generate mesh kv
-	meshKey, meshVersion := getVersion(opt.Get().Mesh.VersionMark)
+	meshKey, meshVersion := getMeshMark()
 	versionMark := meshKey + ":" + meshVersion
 	opt.Store.Mesh = versionMark
 
@@ -47,7 +77,7 @@ func AutoMesh(svc *coreV1.Service) error {
 				}
 			}
 			if podPort < 0 {
-				return fmt.Errorf("cannot found port number of target port '%s' of service %s",
+				return nil, fmt.Errorf("cannot found port number of target port '%s' of service %s",
 					specPort.TargetPort.StrVal, svc.Name)
 			}
 			ports[int(specPort.Port)] = podPort
@@ -56,12 +86,7 @@ func AutoMesh(svc *coreV1.Service) error {
 
 	// Check name usable
 	if err = isNameUsable(svc.Name, meshVersion, 0); err != nil {
-		return err
-	}
-
-	// Create stuntman service
-	if err = createStuntmanService(svc, ports); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Create shadow service
@@ -71,25 +96,24 @@ func AutoMesh(svc *coreV1.Service) error {
 		util.KtTarget: util.RandomString(20),
 	}
 	if err = createShadowService(shadowName, ports, shadowLabels); err != nil {
-		return err
-	}
-
-	// Create router pod
-	// Must after stuntman service and shadow service, otherwise will cause 'host not found in upstream' error
-	routerPodName := svc.Name + util.RouterPodSuffix
-	routerLabels := map[string]string{
-		util.KtRole:   util.RoleRouter,
-	}
-	if err = createRouter(routerPodName, svc.Name, ports, routerLabels, versionMark); err != nil {
-		return err
-	}
-
-	// Let target service select router pod
-	// Must after router pod created, otherwise request will be interrupted
-	if err = general.UpdateServiceSelector(svc.Name, opt.Get().Global.Namespace, routerLabels); err != nil {
-		return err
+		return nil, err
+	}
+
+	// Setup routing rules, must after shadow service created
+	opt.Store.RoutingBackend = backend.Name()
+	if err = backend.Setup(svc, &Route{
+		Header:        meshKey,
+		Version:       meshVersion,
+		ShadowService: shadowName,
+		Ports:         ports,
+		FallbackCodes: fallbackCodes,
+		CookieMark:    cookieMark,
+		GrpcMethods:   grpcMethods,
+		Baggage:       baggage,
+		Weight:        opt.Get().Mesh.MeshWeight,
+	}); err != nil {
+		return nil, err
 	}
-	opt.Store.Origin = svc.Name
 
 	// Create shadow pod
 	annotations := map[string]string{
@@ -97,11 +121,43 @@ func AutoMesh(svc *coreV1.Service) error {
 	}
 	if err = general.CreateShadowAndInbound(shadowName, opt.Get().Mesh.Expose,
 		shadowLabels, annotations, portToNames); err != nil {
-		return err
+		return nil, err
 	}
 	log.Info().Msg("---------------------------------------------------------------")
 	log.Info().Msgf(" Now you can access your service by header '%s: %s' ", strings.ToUpper(meshKey), meshVersion)
+	if cookieMark != "" {
+		log.Info().Msgf(" Or by cookie '%s'", cookieMark)
+	}
+	if len(grpcMethods) > 0 {
+		log.Info().Msgf(" Only calls of grpc method %s are redirected", strings.Join(grpcMethods, ", "))
+	}
+	if baggage != "" {
+		log.Info().Msgf(" Redirected requests are marked with baggage '%s'", baggage)
+	}
+	if weight := opt.Get().Mesh.MeshWeight; weight > 0 {
+		log.Info().Msgf(" And %d%% of requests without the header", weight)
+	}
+	if fallbackCodes != "" {
+		log.Info().Msgf(" Response with status %s will fall back to origin service", fallbackCodes)
+	}
 	log.Info().Msg("---------------------------------------------------------------")
+	return general.NewSetupResult([]string{svc.Name}, util.MeshModeAuto, opt.Get().Mesh.Expose), nil
+}
+
+// checkEndpoints refuse to mesh service without ready endpoints, since requests not redirected to local have nowhere to go
+func checkEndpoints(svcName string) error {
+	count, err := cluster.Ins().CountReadyEndpoints(svcName, opt.Get().Global.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to check endpoints of service %s: %s", svcName, err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if !opt.Get().Mesh.AllowNoEndpoints {
+		return fmt.Errorf("service %s has no ready endpoints, requests not redirected to local would fail, "+
+			"please check its workload or use '--allowNoEndpoints' to mesh it anyway", svcName)
+	}
+	log.Warn().Msgf("Service %s has no ready endpoints, only requests redirected to local will succeed", svcName)
 	return nil
 }
 
@@ -157,94 +213,6 @@ func createShadowService(shadowSvcName string, ports map[int]int,
 	return nil
 }
 
-func createRouter(routerPodName string, svcName string, ports map[int]int, labels map[string]string, versionMark string) error {
-	namespace := opt.Get().Global.Namespace
-	routerPod, err := cluster.Ins().GetPod(routerPodName, namespace)
-	if err == nil && routerPod.DeletionTimestamp != nil {
-		routerPod, err = cluster.Ins().WaitPodTerminate(routerPodName, namespace)
-	}
-	if err != nil {
-		if !k8sErrors.IsNotFound(err) {
-			// Failed to get or wait router pod
-			return err
-		}
-		// Router not exist or just terminated
-		labels[util.KtTarget] = util.RandomString(20)
-		annotations := map[string]string{util.KtRefCount: "1", util.KtConfig: fmt.Sprintf("service=%s", svcName)}
-		if _, err = cluster.Ins().CreateRouterPod(routerPodName, labels, annotations, ports); err != nil {
-			log.Error().Err(err).Msgf("Failed to create router pod")
-			return err
-		}
-		log.Info().Msgf("Router pod is ready")
-
-		stdout, stderr, err2 := cluster.Ins().ExecInPod(util.DefaultContainer, routerPodName, namespace,
-			util.RouterBin, "setup", svcName, toPortMapParameter(ports), versionMark)
-		log.Debug().Msgf("Stdout: %s", stdout)
-		log.Debug().Msgf("Stderr: %s", stderr)
-		if err2 != nil {
-			return err2
-		}
-	} else {
-		// Router pod exist
-		labels[util.KtTarget] = routerPod.Labels[util.KtTarget]
-		cluster.Ins().UpdatePodHeartBeat(routerPodName, namespace)
-		if _, err = strconv.Atoi(routerPod.Annotations[util.KtRefCount]); err != nil {
-			log.Error().Msgf("Router pod exists, but do not have ref count")
-			return err
-		} else if err = cluster.Ins().IncreasePodRef(routerPodName, namespace); err != nil {
-			log.Error().Msgf("Failed to increase router pod ref count")
-			return err
-		}
-		log.Info().Msgf("Router pod already exists")
-
-		stdout, stderr, err2 := cluster.Ins().ExecInPod(util.DefaultContainer, routerPodName, namespace,
-			util.RouterBin, "add", versionMark)
-		log.Debug().Msgf("Stdout: %s", stdout)
-		log.Debug().Msgf("Stderr: %s", stderr)
-		if err2 != nil {
-			return err2
-		}
-	}
-	log.Info().Msgf("Router pod configuration done")
-	opt.Store.Router = routerPodName
-	return nil
-}
-
-func createStuntmanService(svc *coreV1.Service, ports map[int]int) error {
-	stuntmanSvcName := svc.Name + util.StuntmanServiceSuffix
-	namespace := opt.Get().Global.Namespace
-	if stuntmanSvc, err := cluster.Ins().GetService(stuntmanSvcName, namespace); err != nil {
-		if !k8sErrors.IsNotFound(err) {
-			return err
-		}
-		// Check service in sanity status
-		if err = sanityCheck(svc); err != nil {
-			return err
-		}
-		// Stuntman service not exist yet, create it
-		if _, err = cluster.Ins().CreateService(&cluster.SvcMetaAndSpec{
-			Meta: &cluster.ResourceMeta{
-				Name:        stuntmanSvcName,
-				Namespace:   namespace,
-				Labels:      map[string]string{},
-				Annotations: map[string]string{},
-			},
-			External:  false,
-			Ports:     ports,
-			Selectors: svc.Spec.Selector,
-		}); err != nil {
-			return err
-		}
-		log.Info().Msgf("Service %s created", stuntmanSvcName)
-	} else if stuntmanSvc.Labels[util.ControlBy] != util.KubernetesToolkit {
-		return fmt.Errorf("service %s exists, but not created by kt", stuntmanSvcName)
-	} else {
-		cluster.Ins().UpdateServiceHeartBeat(stuntmanSvcName, namespace)
-		log.Info().Msgf("Stuntman service already exists")
-	}
-	return nil
-}
-
 func toPortMapParameter(ports map[int]int) string {
 	// input: { 80:8080, 70:7000 }
 	// output: "80:8080,70:7000"
@@ -1,12 +1,59 @@
 package mesh
 
 import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
 	"regexp"
 	"strings"
 )
 
+const (
+	// traceBaggageHeader w3c header carrying baggage of distributed tracing
+	traceBaggageHeader = "baggage"
+	// traceBaggageKey key of baggage member marking requests passed kt
+	traceBaggageKey = "kt-connect"
+)
+
+// getMeshMark header and value of requests to redirect, from '--meshHeader' if specified, or from '--versionMark'
+func getMeshMark() (string, string) {
+	if header := strings.TrimSpace(opt.Get().Mesh.MeshHeader); header != "" {
+		value := opt.Get().Mesh.MeshHeaderValue
+		if value == "" {
+			value = strings.ToLower(util.RandomString(5))
+		}
+		// header name is case-insensitive, route rules only accept lower case
+		return strings.ToLower(header), value
+	}
+	return getVersion(opt.Get().Mesh.VersionMark)
+}
+
+// checkMeshHeader header value is also used as version in name of shadow pod and route rule,
+// so it must be a valid kubernetes name segment
+func checkMeshHeader(header, value string) error {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		if value != "" {
+			return fmt.Errorf("'--meshHeader' is required when '--meshHeaderValue' is specified")
+		}
+		return nil
+	}
+	if opt.Get().Mesh.VersionMark != "" {
+		return fmt.Errorf("'--meshHeader' cannot be used together with '--versionMark'")
+	}
+	if !isValidKey(strings.ToLower(header)) {
+		return fmt.Errorf("invalid mesh header '%s', should start with letter and only contain letters, digits, '-' and '_'", header)
+	}
+	if value != "" {
+		if ok, err := regexp.MatchString("^[a-z0-9]([a-z0-9-]*[a-z0-9])?$", value); err != nil || !ok || len(value) > 32 {
+			return fmt.Errorf("invalid mesh header value '%s', only lower case letters, digits and '-' are allowed, "+
+				"at most 32 characters", value)
+		}
+	}
+	return nil
+}
+
 func getVersion(versionMark string) (string, string) {
 	versionKey := "version"
 	versionVal := strings.ToLower(util.RandomString(5))
@@ -32,3 +79,85 @@ func isValidKey(key string) bool {
 	ok, err := regexp.MatchString("^[a-z][a-z0-9_-]*$", key)
 	return err == nil && ok
 }
+
+func parseCookieMark(cookieMark string) (string, error) {
+	// input: "name" or "name=value"
+	// output: same format, with spaces trimmed
+	if cookieMark == "" {
+		return "", nil
+	}
+	parts := strings.SplitN(cookieMark, "=", 2)
+	name := strings.TrimSpace(parts[0])
+	// cookie name is used as part of router variable name, so only letters, digits and underscore are allowed
+	if ok, err := regexp.MatchString("^[A-Za-z0-9_]+$", name); err != nil || !ok {
+		return "", fmt.Errorf("invalid cookie name '%s', only letters, digits and '_' are allowed", name)
+	}
+	if len(parts) == 1 {
+		return name, nil
+	}
+	value := strings.TrimSpace(parts[1])
+	if ok, err := regexp.MatchString("^[A-Za-z0-9._~%+/-]+$", value); err != nil || !ok {
+		return "", fmt.Errorf("invalid cookie value '%s', only letters, digits and '._~%%+/-' are allowed", value)
+	}
+	return name + "=" + value, nil
+}
+
+func parseGrpcMethods(grpcMethod string) ([]string, error) {
+	// input: "pkg.Service/Method,/pkg.Service/Other"
+	// output: ["/pkg.Service/Method", "/pkg.Service/Other"], which are request paths of grpc calls
+	if grpcMethod == "" {
+		return nil, nil
+	}
+	paths := make([]string, 0)
+	for _, method := range strings.Split(grpcMethod, ",") {
+		method = strings.TrimPrefix(strings.TrimSpace(method), "/")
+		if ok, err := regexp.MatchString("^([A-Za-z_][A-Za-z0-9_]*\\.)*[A-Za-z_][A-Za-z0-9_]*/[A-Za-z_][A-Za-z0-9_]*$", method); err != nil || !ok {
+			return nil, fmt.Errorf("invalid grpc method '%s', should be in 'package.Service/Method' format", method)
+		}
+		if !util.Contains(paths, "/"+method) {
+			paths = append(paths, "/"+method)
+		}
+	}
+	return paths, nil
+}
+
+func parseTraceTag(traceTag string) (string, error) {
+	// input: "alice-laptop"
+	// output: "kt-connect=alice-laptop", which is a w3c baggage member
+	traceTag = strings.TrimSpace(traceTag)
+	if traceTag == "" {
+		return "", nil
+	}
+	// keep to characters allowed in baggage value without percent-encoding
+	if ok, err := regexp.MatchString("^[A-Za-z0-9._:-]+$", traceTag); err != nil || !ok {
+		return "", fmt.Errorf("invalid trace tag '%s', only letters, digits and '._:-' are allowed", traceTag)
+	}
+	return traceBaggageKey + "=" + traceTag, nil
+}
+
+func parseFallbackCodes(fallbackOn string) (string, error) {
+	// input: "5xx,404"
+	// output: "500,502,503,504,404"
+	if fallbackOn == "" {
+		return "", nil
+	}
+	var codes []string
+	for _, code := range strings.Split(fallbackOn, ",") {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "5xx" {
+			codes = append(codes, "500", "502", "503", "504")
+		} else if util.Contains([]string{"403", "404", "429", "500", "502", "503", "504"}, code) {
+			codes = append(codes, code)
+		} else {
+			return "", fmt.Errorf("unsupported fallback status code '%s', should be '5xx', '403', '404', '429', "+
+				"'500', '502', '503' or '504'", code)
+		}
+	}
+	distinctCodes := make([]string, 0)
+	for _, code := range codes {
+		if !util.Contains(distinctCodes, code) {
+			distinctCodes = append(distinctCodes, code)
+		}
+	}
+	return strings.Join(distinctCodes, ","), nil
+}
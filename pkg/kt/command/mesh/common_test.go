@@ -1,6 +1,7 @@
 package mesh
 
 import (
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -31,3 +32,104 @@ func Test_getVersion(t *testing.T) {
 	require.Equal(t, k, "mark")
 	require.Equal(t, v, "test")
 }
+
+func Test_checkMeshHeader(t *testing.T) {
+	opt.Get().Mesh.VersionMark = ""
+	require.Nil(t, checkMeshHeader("", ""))
+	require.Nil(t, checkMeshHeader("x-tenant-id", ""))
+	require.Nil(t, checkMeshHeader("X-Tenant-Id", "acme"))
+	require.NotNil(t, checkMeshHeader("", "acme"))
+	require.NotNil(t, checkMeshHeader("x.tenant", "acme"))
+	require.NotNil(t, checkMeshHeader("x-tenant-id", "Acme"))
+	require.NotNil(t, checkMeshHeader("x-tenant-id", "acme_1"))
+	opt.Get().Mesh.VersionMark = "mark:test"
+	require.NotNil(t, checkMeshHeader("x-tenant-id", "acme"))
+	opt.Get().Mesh.VersionMark = ""
+}
+
+func Test_getMeshMark(t *testing.T) {
+	opt.Get().Mesh.MeshHeader = "X-Tenant-Id"
+	opt.Get().Mesh.MeshHeaderValue = "acme"
+	k, v := getMeshMark()
+	require.Equal(t, "x-tenant-id", k)
+	require.Equal(t, "acme", v)
+	opt.Get().Mesh.MeshHeaderValue = ""
+	k, v = getMeshMark()
+	require.Equal(t, "x-tenant-id", k)
+	require.Equal(t, 5, len(v))
+	opt.Get().Mesh.MeshHeader = ""
+	k, _ = getMeshMark()
+	require.Equal(t, "version", k)
+}
+
+func Test_parseCookieMark(t *testing.T) {
+	cases := map[string]string{
+		"":              "",
+		"canary":        "canary",
+		"user_id=tom":   "user_id=tom",
+		" ab = v1.0-x ": "ab=v1.0-x",
+	}
+	for input, expected := range cases {
+		cookie, err := parseCookieMark(input)
+		require.Nil(t, err)
+		require.Equal(t, expected, cookie, "cookie mark of '%s' incorrect", input)
+	}
+	for _, input := range []string{"=abc", "user-id=tom", "a=", "a=b;c", "a=\"b\"", "a=${b}"} {
+		_, err := parseCookieMark(input)
+		require.NotNil(t, err, "'%s' should be invalid", input)
+	}
+}
+
+func Test_parseGrpcMethods(t *testing.T) {
+	cases := map[string][]string{
+		"":                            nil,
+		"echo.Echo/Say":               {"/echo.Echo/Say"},
+		"/a.b.Greeter/Hi, Greeter/Hi": {"/a.b.Greeter/Hi", "/Greeter/Hi"},
+		"x.Y/Z,/x.Y/Z":                {"/x.Y/Z"},
+	}
+	for input, expected := range cases {
+		paths, err := parseGrpcMethods(input)
+		require.Nil(t, err)
+		require.Equal(t, expected, paths, "grpc method of '%s' incorrect", input)
+	}
+	for _, input := range []string{"Echo", "echo.Echo/", "echo.Echo/Say/x", "echo..Echo/Say", "echo.Echo/Say*", "a,"} {
+		_, err := parseGrpcMethods(input)
+		require.NotNil(t, err, "'%s' should be invalid", input)
+	}
+}
+
+func Test_parseTraceTag(t *testing.T) {
+	cases := map[string]string{
+		"":              "",
+		"alice":         "kt-connect=alice",
+		" dev-1.local ": "kt-connect=dev-1.local",
+	}
+	for input, expected := range cases {
+		baggage, err := parseTraceTag(input)
+		require.Nil(t, err)
+		require.Equal(t, expected, baggage, "baggage of '%s' incorrect", input)
+	}
+	for _, input := range []string{"a b", "a=b", "a,b", "a;b"} {
+		_, err := parseTraceTag(input)
+		require.NotNil(t, err, "'%s' should be invalid", input)
+	}
+}
+
+func Test_parseFallbackCodes(t *testing.T) {
+	cases := map[string]string{
+		"":            "",
+		"5xx":         "500,502,503,504",
+		"503":         "503",
+		"404, 5XX":    "404,500,502,503,504",
+		"500,5xx,500": "500,502,503,504",
+	}
+	for input, expected := range cases {
+		codes, err := parseFallbackCodes(input)
+		require.Nil(t, err)
+		require.Equal(t, expected, codes, "fallback codes of '%s' incorrect", input)
+	}
+	for _, input := range []string{"4xx", "501", "abc"} {
+		_, err := parseFallbackCodes(input)
+		require.NotNil(t, err, "'%s' should be invalid", input)
+	}
+}
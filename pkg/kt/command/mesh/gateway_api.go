@@ -0,0 +1,204 @@
+package mesh
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var httpRouteGvr = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "httproutes"}
+
+// gatewayApiBackend route requests with gateway api http route attached to service (GAMMA),
+// routes of all versions share one http route for each service port
+type gatewayApiBackend struct{}
+
+func (b *gatewayApiBackend) Name() string {
+	return util.RoutingBackendGatewayApi
+}
+
+func (b *gatewayApiBackend) Installed() bool {
+	return cluster.Ins().IsResourceInstalled(httpRouteGvr)
+}
+
+func (b *gatewayApiBackend) Setup(svc *coreV1.Service, route *Route) error {
+	if err := checkRouteConflict(httpRouteGvr, func(obj *unstructured.Unstructured) bool {
+		return isHttpRouteOf(obj, svc.Name)
+	}); err != nil {
+		return err
+	}
+	// record origin service first, so that rules of ports already added can be removed if later port failed
+	opt.Store.Origin = svc.Name
+	for _, p := range svc.Spec.Ports {
+		port := int64(p.Port)
+		rule := map[string]interface{}{
+			"matches": httpRouteMatches(route),
+			"backendRefs": []interface{}{
+				map[string]interface{}{"name": route.ShadowService, "port": port},
+			},
+		}
+		if route.Baggage != "" {
+			// header modifier appends value to existing baggage header, separated by comma
+			rule["filters"] = []interface{}{
+				map[string]interface{}{
+					"type": "RequestHeaderModifier",
+					"requestHeaderModifier": map[string]interface{}{
+						"add": []interface{}{
+							map[string]interface{}{"name": traceBaggageHeader, "value": route.Baggage},
+						},
+					},
+				},
+			}
+		}
+		name := fmt.Sprintf("%s%s-%d", svc.Name, util.RouteRuleSuffix, port)
+		err := addRouteRule(httpRouteGvr, name, rule, func() (*unstructured.Unstructured, error) {
+			httpRoute := newRouteResource(httpRouteGvr, "HTTPRoute", name)
+			httpRoute.Object["spec"] = map[string]interface{}{
+				"parentRefs": []interface{}{
+					map[string]interface{}{"group": "", "kind": "Service", "name": svc.Name, "port": port},
+				},
+				"rules": []interface{}{
+					rule,
+					map[string]interface{}{
+						"backendRefs": []interface{}{
+							map[string]interface{}{"name": svc.Name, "port": port},
+						},
+					},
+				},
+			}
+			return httpRoute, nil
+		}, "spec", "rules")
+		if err != nil {
+			return err
+		}
+		if route.Weight == 0 {
+			continue
+		}
+		err = updateDefaultRouteRule(httpRouteGvr, name, func(rule map[string]interface{}) error {
+			if backends, _, _ := unstructured.NestedSlice(rule, "backendRefs"); len(backends) > 1 {
+				return fmt.Errorf("requests of service %s are already split by another mesh, cannot apply '--meshWeight'", svc.Name)
+			}
+			rule["backendRefs"] = httpRouteBackends(svc.Name, route.ShadowService, port, route.Weight)
+			return nil
+		}, "spec", "rules")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// httpRouteBackends route specified percentage of requests to shadow service, and the rest to origin service
+func httpRouteBackends(svcName, shadowService string, port int64, weight int) []interface{} {
+	origin := map[string]interface{}{"name": svcName, "port": port}
+	if weight == 0 {
+		return []interface{}{origin}
+	}
+	origin["weight"] = int64(100 - weight)
+	return []interface{}{
+		origin,
+		map[string]interface{}{"name": shadowService, "port": port, "weight": int64(weight)},
+	}
+}
+
+// httpRouteMatches requests with version header, and calling one of grpc methods if specified
+func httpRouteMatches(route *Route) []interface{} {
+	newMatch := func() map[string]interface{} {
+		return map[string]interface{}{
+			"headers": []interface{}{
+				map[string]interface{}{"type": "Exact", "name": route.Header, "value": route.Version},
+			},
+		}
+	}
+	if len(route.GrpcMethods) == 0 {
+		return []interface{}{newMatch()}
+	}
+	matches := make([]interface{}, 0)
+	for _, path := range route.GrpcMethods {
+		match := newMatch()
+		match["path"] = map[string]interface{}{"type": "Exact", "value": path}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+func (b *gatewayApiBackend) Teardown() {
+	if opt.Store.Origin == "" {
+		return
+	}
+	svc, err := cluster.Ins().GetService(opt.Store.Origin, opt.Get().Global.Namespace)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to get service %s, routes cannot be removed", opt.Store.Origin)
+		return
+	}
+	shadowSvcName := shadowServiceOfStore()
+	routeToShadow := func(rule map[string]interface{}) bool {
+		backends, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		for _, ref := range backends {
+			if backend, _, _ := unstructured.NestedString(ref.(map[string]interface{}), "name"); backend == shadowSvcName {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range svc.Spec.Ports {
+		port := int64(p.Port)
+		name := fmt.Sprintf("%s%s-%d", svc.Name, util.RouteRuleSuffix, port)
+		// default rule is kept, only the share of shadow service is taken back
+		resetDefaultRouteRule(httpRouteGvr, name, func(rule map[string]interface{}) error {
+			if routeToShadow(rule) {
+				rule["backendRefs"] = httpRouteBackends(svc.Name, "", port, 0)
+			}
+			return nil
+		}, "spec", "rules")
+		removeRouteRules(httpRouteGvr, name, routeToShadow, "spec", "rules")
+	}
+}
+
+func isHttpRouteOf(httpRoute *unstructured.Unstructured, svcName string) bool {
+	parents, _, _ := unstructured.NestedSlice(httpRoute.Object, "spec", "parentRefs")
+	for _, p := range parents {
+		parent := p.(map[string]interface{})
+		if parent["kind"] == "Service" && parent["name"] == svcName {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *gatewayApiBackend) Redirect(svc *coreV1.Service, shadowService string) (int, error) {
+	count, err := redirectRoutes(httpRouteGvr, func(rule map[string]interface{}) bool {
+		backends, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		changed := false
+		for _, ref := range backends {
+			backend := ref.(map[string]interface{})
+			kind, _, _ := unstructured.NestedString(backend, "kind")
+			namespace, _, _ := unstructured.NestedString(backend, "namespace")
+			if backend["name"] == svc.Name && (kind == "" || kind == "Service") &&
+				(namespace == "" || namespace == opt.Get().Global.Namespace) {
+				backend["name"] = shadowService
+				changed = true
+			}
+		}
+		if changed {
+			_ = unstructured.SetNestedSlice(rule, backends, "backendRefs")
+		}
+		return changed
+	}, "spec", "rules")
+	if count > 0 {
+		opt.Store.RoutingBackend = b.Name()
+	}
+	return count, err
+}
+
+func (b *gatewayApiBackend) Restore() {
+	restoreRoutes(httpRouteGvr, "spec", "rules")
+}
+
+func (b *gatewayApiBackend) Unrestored() []string {
+	return unrestoredRoutes(httpRouteGvr)
+}
@@ -0,0 +1,184 @@
+package mesh
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strings"
+)
+
+var virtualServiceGvr = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "virtualservices"}
+
+// istioBackend route requests with istio virtual service, routes of all versions share one virtual service
+type istioBackend struct{}
+
+func (b *istioBackend) Name() string {
+	return util.RoutingBackendIstio
+}
+
+func (b *istioBackend) Installed() bool {
+	return cluster.Ins().IsResourceInstalled(virtualServiceGvr)
+}
+
+func (b *istioBackend) Setup(svc *coreV1.Service, route *Route) error {
+	rule := map[string]interface{}{
+		"name":  "kt-" + route.Version,
+		"match": istioMatches(route),
+		"route": []interface{}{
+			map[string]interface{}{"destination": map[string]interface{}{"host": route.ShadowService}},
+		},
+	}
+	if route.Baggage != "" {
+		// append as an extra baggage header, which is merged with existing ones by receiver
+		rule["headers"] = map[string]interface{}{
+			"request": map[string]interface{}{
+				"add": map[string]interface{}{traceBaggageHeader: route.Baggage},
+			},
+		}
+	}
+	name := svc.Name + util.RouteRuleSuffix
+	err := addRouteRule(virtualServiceGvr, name, rule, func() (*unstructured.Unstructured, error) {
+		if err := checkRouteConflict(virtualServiceGvr, func(obj *unstructured.Unstructured) bool {
+			return isVirtualServiceOf(obj, svc.Name)
+		}); err != nil {
+			return nil, err
+		}
+		vs := newRouteResource(virtualServiceGvr, "VirtualService", name)
+		vs.Object["spec"] = map[string]interface{}{
+			"hosts": []interface{}{svc.Name},
+			"http": []interface{}{
+				rule,
+				map[string]interface{}{
+					"name": "kt-default",
+					"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"host": svc.Name}},
+					},
+				},
+			},
+		}
+		return vs, nil
+	}, "spec", "http")
+	if err != nil {
+		return err
+	}
+	opt.Store.Origin = svc.Name
+	if route.Weight == 0 {
+		return nil
+	}
+	return updateDefaultRouteRule(virtualServiceGvr, name, func(rule map[string]interface{}) error {
+		if destinations, _, _ := unstructured.NestedSlice(rule, "route"); len(destinations) > 1 {
+			return fmt.Errorf("requests of service %s are already split by another mesh, cannot apply '--meshWeight'", svc.Name)
+		}
+		rule["route"] = istioDestinations(svc.Name, route.ShadowService, route.Weight)
+		return nil
+	}, "spec", "http")
+}
+
+// istioDestinations route specified percentage of requests to shadow service, and the rest to origin service
+func istioDestinations(svcName, shadowService string, weight int) []interface{} {
+	origin := map[string]interface{}{"destination": map[string]interface{}{"host": svcName}}
+	if weight == 0 {
+		return []interface{}{origin}
+	}
+	origin["weight"] = int64(100 - weight)
+	return []interface{}{
+		origin,
+		map[string]interface{}{"destination": map[string]interface{}{"host": shadowService}, "weight": int64(weight)},
+	}
+}
+
+// istioMatches requests with version header, and calling one of grpc methods if specified
+func istioMatches(route *Route) []interface{} {
+	newMatch := func() map[string]interface{} {
+		return map[string]interface{}{
+			"headers": map[string]interface{}{
+				route.Header: map[string]interface{}{"exact": route.Version},
+			},
+		}
+	}
+	if len(route.GrpcMethods) == 0 {
+		return []interface{}{newMatch()}
+	}
+	matches := make([]interface{}, 0)
+	for _, path := range route.GrpcMethods {
+		match := newMatch()
+		match["uri"] = map[string]interface{}{"exact": path}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+func (b *istioBackend) Teardown() {
+	if opt.Store.Origin == "" {
+		return
+	}
+	shadowSvcName := shadowServiceOfStore()
+	routeToShadow := func(rule map[string]interface{}) bool {
+		destinations, _, _ := unstructured.NestedSlice(rule, "route")
+		for _, d := range destinations {
+			if host, _, _ := unstructured.NestedString(d.(map[string]interface{}), "destination", "host"); host == shadowSvcName {
+				return true
+			}
+		}
+		return false
+	}
+	name := opt.Store.Origin + util.RouteRuleSuffix
+	// default rule is kept, only the share of shadow service is taken back
+	resetDefaultRouteRule(virtualServiceGvr, name, func(rule map[string]interface{}) error {
+		if routeToShadow(rule) {
+			rule["route"] = istioDestinations(opt.Store.Origin, "", 0)
+		}
+		return nil
+	}, "spec", "http")
+	removeRouteRules(virtualServiceGvr, name, routeToShadow, "spec", "http")
+}
+
+func isVirtualServiceOf(vs *unstructured.Unstructured, svcName string) bool {
+	hosts, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "hosts")
+	for _, host := range hosts {
+		if isHostOf(host, svcName) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHostOf check whether host is short or full domain name of service in current namespace
+func isHostOf(host, svcName string) bool {
+	fullName := svcName + "." + opt.Get().Global.Namespace
+	return host == svcName || host == fullName || strings.HasPrefix(host, fullName+".svc")
+}
+
+func (b *istioBackend) Redirect(svc *coreV1.Service, shadowService string) (int, error) {
+	count, err := redirectRoutes(virtualServiceGvr, func(rule map[string]interface{}) bool {
+		destinations, _, _ := unstructured.NestedSlice(rule, "route")
+		changed := false
+		for _, d := range destinations {
+			destination, ok := d.(map[string]interface{})["destination"].(map[string]interface{})
+			if ok && isHostOf(fmt.Sprint(destination["host"]), svc.Name) {
+				destination["host"] = shadowService
+				changed = true
+			}
+		}
+		if changed {
+			_ = unstructured.SetNestedSlice(rule, destinations, "route")
+		}
+		return changed
+	}, "spec", "http")
+	if count > 0 {
+		opt.Store.RoutingBackend = b.Name()
+	}
+	return count, err
+}
+
+func (b *istioBackend) Restore() {
+	restoreRoutes(virtualServiceGvr, "spec", "http")
+}
+
+func (b *istioBackend) Unrestored() []string {
+	return unrestoredRoutes(virtualServiceGvr)
+}
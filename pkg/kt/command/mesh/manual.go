@@ -8,19 +8,19 @@ import (
 	coreV1 "k8s.io/api/core/v1"
 )
 
-func ManualMesh(svc *coreV1.Service) error {
-	meshKey, meshVersion := getVersion(opt.Get().Mesh.VersionMark)
+func ManualMesh(svc *coreV1.Service) (*general.SetupResult, error) {
+	meshKey, meshVersion := getMeshMark()
 	shadowPodName := svc.Name + util.MeshPodInfix + meshVersion
 	labels := getMeshLabels(meshKey, meshVersion, svc)
 	annotations := make(map[string]string)
 	if err := general.CreateShadowAndInbound(shadowPodName, opt.Get().Mesh.Expose, labels,
 		annotations, general.GetTargetPorts(svc)); err != nil {
-		return err
+		return nil, err
 	}
 	log.Info().Msg("---------------------------------------------------------")
 	log.Info().Msgf(" Now you can update Istio rule by label '%s=%s' ", meshKey, meshVersion)
 	log.Info().Msg("---------------------------------------------------------")
-	return nil
+	return general.NewSetupResult([]string{svc.Name}, util.MeshModeManual, opt.Get().Mesh.Expose), nil
 }
 
 func getMeshLabels(meshKey, meshVersion string, svc *coreV1.Service) map[string]string {
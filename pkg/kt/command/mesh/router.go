@@ -0,0 +1,184 @@
+package mesh
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"strconv"
+)
+
+// routerBackend route requests with kt router pod, which works without any service mesh installed
+type routerBackend struct{}
+
+func (b *routerBackend) Name() string {
+	return util.RoutingBackendRouter
+}
+
+func (b *routerBackend) Installed() bool {
+	return true
+}
+
+func (b *routerBackend) Setup(svc *coreV1.Service, route *Route) error {
+	// Create stuntman service
+	if err := createStuntmanService(svc, route.Ports); err != nil {
+		return err
+	}
+
+	// Create router pod
+	// Must after stuntman service and shadow service, otherwise will cause 'host not found in upstream' error
+	routerPodName := svc.Name + util.RouterPodSuffix
+	routerLabels := map[string]string{
+		util.KtRole: util.RoleRouter,
+	}
+	versionMark := route.Header + ":" + route.Version
+	if err := createRouter(routerPodName, svc.Name, route.Ports, routerLabels, versionMark,
+		route.FallbackCodes, route.CookieMark, route.Weight); err != nil {
+		return err
+	}
+
+	// Let target service select router pod
+	// Must after router pod created, otherwise request will be interrupted
+	if err := general.UpdateServiceSelector(svc.Name, opt.Get().Global.Namespace, routerLabels); err != nil {
+		return err
+	}
+	opt.Store.Origin = svc.Name
+	return nil
+}
+
+func (b *routerBackend) Teardown() {
+	if opt.Store.Router != "" {
+		routerPod, err := cluster.Ins().GetPod(opt.Store.Router, opt.Get().Global.Namespace)
+		if err != nil {
+			log.Error().Err(err).Msgf("Router pod has been removed unexpectedly")
+			// in case of router pod gone, try recover origin service via runtime store
+			if opt.Store.Origin != "" {
+				recoverService(opt.Store.Origin)
+			}
+			return
+		}
+		if shouldDelRouter, err2 := cluster.Ins().DecreasePodRef(opt.Store.Router, opt.Get().Global.Namespace); err2 != nil {
+			log.Error().Err(err2).Msgf("Decrease router pod %s reference failed", opt.Store.Shadow)
+		} else if shouldDelRouter {
+			routerConfig := routerPod.Annotations[util.KtConfig]
+			config := util.String2Map(routerConfig)
+			recoverService(config["service"])
+			if err = cluster.Ins().RemovePod(opt.Store.Router, opt.Get().Global.Namespace); err != nil {
+				log.Warn().Err(err).Msgf("Failed to remove router pod")
+			}
+		} else {
+			stdout, stderr, err3 := cluster.Ins().ExecInPod(util.DefaultContainer, opt.Store.Router, opt.Get().Global.Namespace,
+				util.RouterBin, "remove", opt.Store.Mesh)
+			log.Debug().Msgf("Stdout: %s", stdout)
+			log.Debug().Msgf("Stderr: %s", stderr)
+			if err3 != nil {
+				log.Warn().Err(err3).Msgf("Failed to remove version %s from router pod", opt.Store.Mesh)
+			}
+		}
+	}
+}
+
+func recoverService(originSvcName string) {
+	general.RecoverOriginalService(originSvcName, opt.Get().Global.Namespace)
+	log.Info().Msgf("Original service %s recovered", originSvcName)
+
+	stuntmanSvcName := originSvcName + util.StuntmanServiceSuffix
+	if err := cluster.Ins().RemoveService(stuntmanSvcName, opt.Get().Global.Namespace); err != nil {
+		log.Error().Err(err).Msgf("Failed to remove stuntman service %s", stuntmanSvcName)
+	}
+	log.Info().Msgf("Stuntman service %s removed", stuntmanSvcName)
+}
+
+func createRouter(routerPodName string, svcName string, ports map[int]int, labels map[string]string,
+	versionMark, fallbackCodes, cookieMark string, weight int) error {
+	namespace := opt.Get().Global.Namespace
+	routerPod, err := cluster.Ins().GetPod(routerPodName, namespace)
+	if err == nil && routerPod.DeletionTimestamp != nil {
+		routerPod, err = cluster.Ins().WaitPodTerminate(routerPodName, namespace)
+	}
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			// Failed to get or wait router pod
+			return err
+		}
+		// Router not exist or just terminated
+		labels[util.KtTarget] = util.RandomString(20)
+		annotations := map[string]string{util.KtRefCount: "1", util.KtConfig: fmt.Sprintf("service=%s", svcName)}
+		if _, err = cluster.Ins().CreateRouterPod(routerPodName, labels, annotations, ports); err != nil {
+			log.Error().Err(err).Msgf("Failed to create router pod")
+			return err
+		}
+		log.Info().Msgf("Router pod is ready")
+
+		stdout, stderr, err2 := cluster.Ins().ExecInPod(util.DefaultContainer, routerPodName, namespace,
+			util.RouterBin, "setup", svcName, toPortMapParameter(ports), versionMark, fallbackCodes, cookieMark,
+			strconv.Itoa(weight))
+		log.Debug().Msgf("Stdout: %s", stdout)
+		log.Debug().Msgf("Stderr: %s", stderr)
+		if err2 != nil {
+			return err2
+		}
+	} else {
+		// Router pod exist
+		labels[util.KtTarget] = routerPod.Labels[util.KtTarget]
+		cluster.Ins().UpdatePodHeartBeat(routerPodName, namespace)
+		if _, err = strconv.Atoi(routerPod.Annotations[util.KtRefCount]); err != nil {
+			log.Error().Msgf("Router pod exists, but do not have ref count")
+			return err
+		} else if err = cluster.Ins().IncreasePodRef(routerPodName, namespace); err != nil {
+			log.Error().Msgf("Failed to increase router pod ref count")
+			return err
+		}
+		log.Info().Msgf("Router pod already exists")
+
+		stdout, stderr, err2 := cluster.Ins().ExecInPod(util.DefaultContainer, routerPodName, namespace,
+			util.RouterBin, "add", versionMark, fallbackCodes, cookieMark, strconv.Itoa(weight))
+		log.Debug().Msgf("Stdout: %s", stdout)
+		log.Debug().Msgf("Stderr: %s", stderr)
+		if err2 != nil {
+			return err2
+		}
+	}
+	log.Info().Msgf("Router pod configuration done")
+	opt.Store.Router = routerPodName
+	return nil
+}
+
+func createStuntmanService(svc *coreV1.Service, ports map[int]int) error {
+	stuntmanSvcName := svc.Name + util.StuntmanServiceSuffix
+	namespace := opt.Get().Global.Namespace
+	if stuntmanSvc, err := cluster.Ins().GetService(stuntmanSvcName, namespace); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		// Check service in sanity status
+		if err = sanityCheck(svc); err != nil {
+			return err
+		}
+		// Stuntman service not exist yet, create it
+		if _, err = cluster.Ins().CreateService(&cluster.SvcMetaAndSpec{
+			Meta: &cluster.ResourceMeta{
+				Name:        stuntmanSvcName,
+				Namespace:   namespace,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+			External:  false,
+			Ports:     ports,
+			Selectors: svc.Spec.Selector,
+		}); err != nil {
+			return err
+		}
+		log.Info().Msgf("Service %s created", stuntmanSvcName)
+	} else if stuntmanSvc.Labels[util.ControlBy] != util.KubernetesToolkit {
+		return fmt.Errorf("service %s exists, but not created by kt", stuntmanSvcName)
+	} else {
+		cluster.Ins().UpdateServiceHeartBeat(stuntmanSvcName, namespace)
+		log.Info().Msgf("Stuntman service already exists")
+	}
+	return nil
+}
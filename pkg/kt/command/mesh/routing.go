@@ -0,0 +1,413 @@
+package mesh
+
+import (
+	"encoding/json"
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+	"strings"
+)
+
+// Route describe where marked requests of a service should go
+type Route struct {
+	// Header name of header used for routing
+	Header string
+	// Version value of header used for routing
+	Version string
+	// ShadowService service select the shadow pod
+	ShadowService string
+	// Ports service port to target port
+	Ports map[int]int
+	// FallbackCodes status codes to fall back to origin service, router backend only
+	FallbackCodes string
+	// CookieMark cookie used for routing besides header, router backend only
+	CookieMark string
+	// GrpcMethods request paths of grpc methods, only calls of them are routed, istio and gateway api backends only
+	GrpcMethods []string
+	// Baggage baggage member added to requests routed to local, istio and gateway api backends only
+	Baggage string
+	// Weight percentage of requests without version header also routed to shadow service, 0 for none
+	Weight int
+}
+
+// RoutingBackend generate and remove routing rules which redirect marked requests to shadow service
+type RoutingBackend interface {
+	// Name of routing backend, same as value of '--routingBackend' option
+	Name() string
+	// Installed check whether resources required by routing backend exist in cluster
+	Installed() bool
+	// Setup create or update routing rules of service, let marked requests go to shadow service
+	Setup(svc *coreV1.Service, route *Route) error
+	// Teardown remove routing rules of current process, according to runtime store
+	Teardown()
+}
+
+// RouteRedirector routing backend able to redirect all requests routed to a service by existing route resources
+type RouteRedirector interface {
+	RoutingBackend
+	// Redirect let rules of route resources send requests of service to shadow service instead,
+	// return number of route resources changed
+	Redirect(svc *coreV1.Service, shadowService string) (int, error)
+	// Restore recover route resources changed by current process
+	Restore()
+	// Unrestored list route resources still redirected by current process
+	Unrestored() []string
+}
+
+// GetRouteRedirectors get installed routing backends which support redirecting existing routes
+func GetRouteRedirectors() []RouteRedirector {
+	redirectors := make([]RouteRedirector, 0)
+	for _, r := range []RouteRedirector{&istioBackend{}, &gatewayApiBackend{}} {
+		if r.Installed() {
+			redirectors = append(redirectors, r)
+		}
+	}
+	return redirectors
+}
+
+// RestoreRoutes recover route resources redirected by exchange
+func RestoreRoutes() {
+	if opt.Store.RoutingBackend == "" || opt.Get().Global.DryRun {
+		return
+	}
+	// routes of more than one backend could be redirected
+	for _, redirector := range GetRouteRedirectors() {
+		redirector.Restore()
+	}
+}
+
+// UnrestoredRoutes list route resources redirected by exchange but not recovered yet
+func UnrestoredRoutes() []string {
+	var routes []string
+	for _, redirector := range GetRouteRedirectors() {
+		routes = append(routes, redirector.Unrestored()...)
+	}
+	return routes
+}
+
+func newRoutingBackend(name string) (RoutingBackend, error) {
+	switch name {
+	case util.RoutingBackendRouter:
+		return &routerBackend{}, nil
+	case util.RoutingBackendIstio:
+		return &istioBackend{}, nil
+	case util.RoutingBackendGatewayApi:
+		return &gatewayApiBackend{}, nil
+	}
+	return nil, fmt.Errorf("invalid routing backend '%s', supportted are %s, %s, %s, %s", name,
+		util.RoutingBackendAuto, util.RoutingBackendRouter, util.RoutingBackendIstio, util.RoutingBackendGatewayApi)
+}
+
+// getRoutingBackend get routing backend specified by option, or detect it from cluster
+func getRoutingBackend(name string) (RoutingBackend, error) {
+	if name == util.RoutingBackendAuto {
+		if istio := (&istioBackend{}); istio.Installed() {
+			log.Info().Msgf("Istio detected, using %s routing backend", util.RoutingBackendIstio)
+			return istio, nil
+		}
+		return &routerBackend{}, nil
+	}
+	backend, err := newRoutingBackend(name)
+	if err != nil {
+		return nil, err
+	}
+	if !backend.Installed() {
+		return nil, fmt.Errorf("routing backend '%s' is not available, required custom resource is not installed in cluster", name)
+	}
+	return backend, nil
+}
+
+// checkBackendOptions verify options only supported by specified routing backend
+func checkBackendOptions(backend RoutingBackend) error {
+	if backend.Name() != util.RoutingBackendRouter && (opt.Get().Mesh.FallbackOn != "" || opt.Get().Mesh.MeshCookie != "") {
+		return fmt.Errorf("'--fallbackOn' and '--meshCookie' are only supported by %s routing backend, current is %s",
+			util.RoutingBackendRouter, backend.Name())
+	}
+	if backend.Name() == util.RoutingBackendRouter && opt.Get().Mesh.GrpcMethod != "" {
+		return fmt.Errorf("'--grpcMethod' is only supported by %s and %s routing backend, current is %s",
+			util.RoutingBackendIstio, util.RoutingBackendGatewayApi, backend.Name())
+	}
+	if backend.Name() == util.RoutingBackendRouter && opt.Get().Mesh.TraceTag != "" {
+		return fmt.Errorf("'--traceTag' is only supported by %s and %s routing backend, current is %s",
+			util.RoutingBackendIstio, util.RoutingBackendGatewayApi, backend.Name())
+	}
+	return nil
+}
+
+// Teardown remove routing rules created by auto mesh
+func Teardown() {
+	if opt.Store.RoutingBackend == "" || opt.Get().Global.DryRun {
+		return
+	}
+	backend, err := newRoutingBackend(opt.Store.RoutingBackend)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to remove routing rules")
+		return
+	}
+	backend.Teardown()
+}
+
+// newRouteResource create an empty route resource controlled by kt
+func newRouteResource(gvr schema.GroupVersionResource, kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(gvr.GroupVersion().String())
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(opt.Get().Global.Namespace)
+	obj.SetLabels(map[string]string{util.ControlBy: util.KubernetesToolkit})
+	obj.SetAnnotations(map[string]string{util.KtSession: opt.Store.Session})
+	return obj
+}
+
+// addRouteRule insert rule before the last (default) rule of route resource, create the resource if not exists
+func addRouteRule(gvr schema.GroupVersionResource, name string, rule interface{},
+	newResource func() (*unstructured.Unstructured, error), path ...string) error {
+	namespace := opt.Get().Global.Namespace
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := cluster.Ins().GetCustomResource(gvr, name, namespace)
+		if k8sErrors.IsNotFound(err) {
+			if obj, err = newResource(); err != nil {
+				return err
+			}
+			if _, err = cluster.Ins().CreateCustomResource(gvr, obj); err != nil {
+				return err
+			}
+			log.Info().Msgf("Route %s created", name)
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if obj.GetLabels()[util.ControlBy] != util.KubernetesToolkit {
+			return fmt.Errorf("%s %s exists, but not created by kt", obj.GetKind(), name)
+		}
+		rules, _, _ := unstructured.NestedSlice(obj.Object, path...)
+		if len(rules) == 0 {
+			return fmt.Errorf("%s %s has no default route", obj.GetKind(), name)
+		}
+		newRules := make([]interface{}, 0, len(rules)+1)
+		newRules = append(newRules, rules[:len(rules)-1]...)
+		newRules = append(newRules, rule, rules[len(rules)-1])
+		if err = unstructured.SetNestedSlice(obj.Object, newRules, path...); err != nil {
+			return err
+		}
+		if _, err = cluster.Ins().UpdateCustomResource(gvr, obj); err != nil {
+			return err
+		}
+		log.Info().Msgf("Route %s updated", name)
+		return nil
+	})
+}
+
+// updateDefaultRouteRule change the last (default) rule of route resource, which routes requests matching no other rule
+func updateDefaultRouteRule(gvr schema.GroupVersionResource, name string, update func(map[string]interface{}) error,
+	path ...string) error {
+	namespace := opt.Get().Global.Namespace
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := cluster.Ins().GetCustomResource(gvr, name, namespace)
+		if err != nil {
+			return err
+		}
+		rules, _, _ := unstructured.NestedSlice(obj.Object, path...)
+		if len(rules) == 0 {
+			return fmt.Errorf("%s %s has no default route", obj.GetKind(), name)
+		}
+		rule, ok := rules[len(rules)-1].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s %s has invalid default route", obj.GetKind(), name)
+		}
+		if err = update(rule); err != nil {
+			return err
+		}
+		if err = unstructured.SetNestedSlice(obj.Object, rules, path...); err != nil {
+			return err
+		}
+		_, err = cluster.Ins().UpdateCustomResource(gvr, obj)
+		return err
+	})
+}
+
+// resetDefaultRouteRule let default rule route all requests back to origin service, if it was split by current process
+func resetDefaultRouteRule(gvr schema.GroupVersionResource, name string, update func(map[string]interface{}) error,
+	path ...string) {
+	if err := updateDefaultRouteRule(gvr, name, update, path...); err != nil && !k8sErrors.IsNotFound(err) {
+		log.Warn().Err(err).Msgf("Failed to reset default rule of route %s", name)
+	}
+}
+
+// removeRouteRules remove matched rules from route resource, remove the resource if only default rule left
+func removeRouteRules(gvr schema.GroupVersionResource, name string, matched func(map[string]interface{}) bool, path ...string) {
+	namespace := opt.Get().Global.Namespace
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := cluster.Ins().GetCustomResource(gvr, name, namespace)
+		if err != nil {
+			return err
+		}
+		rules, _, _ := unstructured.NestedSlice(obj.Object, path...)
+		remaining := make([]interface{}, 0)
+		for _, r := range rules {
+			if m, ok := r.(map[string]interface{}); ok && matched(m) {
+				continue
+			}
+			remaining = append(remaining, r)
+		}
+		if len(remaining) <= 1 {
+			if err = cluster.Ins().RemoveCustomResource(gvr, name, namespace); err != nil {
+				return err
+			}
+			log.Info().Msgf("Route %s removed", name)
+			return nil
+		}
+		if err = unstructured.SetNestedSlice(obj.Object, remaining, path...); err != nil {
+			return err
+		}
+		if _, err = cluster.Ins().UpdateCustomResource(gvr, obj); err != nil {
+			return err
+		}
+		log.Info().Msgf("Route %s updated", name)
+		return nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to remove rules of version %s from route %s", opt.Store.Mesh, name)
+	}
+}
+
+// checkRouteConflict make sure no route resource created by others is applied to the service
+func checkRouteConflict(gvr schema.GroupVersionResource, refer func(*unstructured.Unstructured) bool) error {
+	objs, err := cluster.Ins().GetCustomResourcesInNamespace(gvr, opt.Get().Global.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs.Items {
+		if obj.GetLabels()[util.ControlBy] != util.KubernetesToolkit && refer(&obj) {
+			return fmt.Errorf("%s %s already routes the service, please use '--routingBackend %s' or manual mode instead",
+				obj.GetKind(), obj.GetName(), util.RoutingBackendRouter)
+		}
+	}
+	return nil
+}
+
+// shadowServiceOfStore get name of shadow service created by current process
+func shadowServiceOfStore() string {
+	version := opt.Store.Mesh
+	if parts := strings.SplitN(opt.Store.Mesh, ":", 2); len(parts) == 2 {
+		version = parts[1]
+	}
+	return opt.Store.Origin + util.MeshPodInfix + version
+}
+
+// redirectRoutes rewrite rules of route resources not created by kt, original rules are kept in annotation
+func redirectRoutes(gvr schema.GroupVersionResource, rewrite func(rule map[string]interface{}) bool, path ...string) (int, error) {
+	namespace := opt.Get().Global.Namespace
+	objs, err := cluster.Ins().GetCustomResourcesInNamespace(gvr, namespace)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, item := range objs.Items {
+		if item.GetLabels()[util.ControlBy] == util.KubernetesToolkit {
+			continue
+		}
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			obj, err2 := cluster.Ins().GetCustomResource(gvr, item.GetName(), namespace)
+			if err2 != nil {
+				return err2
+			}
+			rules, _, _ := unstructured.NestedSlice(obj.Object, path...)
+			origin, err2 := json.Marshal(rules)
+			if err2 != nil {
+				return err2
+			}
+			changed := false
+			for _, r := range rules {
+				if m, ok := r.(map[string]interface{}); ok && rewrite(m) {
+					changed = true
+				}
+			}
+			if !changed {
+				return nil
+			}
+			if obj.GetAnnotations()[util.KtRouteOrigin] != "" {
+				return fmt.Errorf("%s %s is already redirected by another user", obj.GetKind(), obj.GetName())
+			}
+			if err2 = unstructured.SetNestedSlice(obj.Object, rules, path...); err2 != nil {
+				return err2
+			}
+			annotations := obj.GetAnnotations()
+			annotations = util.MapPut(annotations, util.KtRouteOrigin, string(origin))
+			annotations = util.MapPut(annotations, util.KtSession, opt.Store.Session)
+			obj.SetAnnotations(annotations)
+			if _, err2 = cluster.Ins().UpdateCustomResource(gvr, obj); err2 != nil {
+				return err2
+			}
+			log.Info().Msgf("%s %s redirected", obj.GetKind(), obj.GetName())
+			count++
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// unrestoredRoutes find route resources still carrying origin rules saved by current process
+func unrestoredRoutes(gvr schema.GroupVersionResource) []string {
+	objs, err := cluster.Ins().GetCustomResourcesInNamespace(gvr, opt.Get().Global.Namespace)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to list %s: %s", gvr.Resource, err)}
+	}
+	var routes []string
+	for _, item := range objs.Items {
+		if item.GetAnnotations()[util.KtRouteOrigin] != "" && item.GetAnnotations()[util.KtSession] == opt.Store.Session {
+			routes = append(routes, fmt.Sprintf("%s %s is not restored", item.GetKind(), item.GetName()))
+		}
+	}
+	return routes
+}
+
+// restoreRoutes recover rules of route resources redirected by current process
+func restoreRoutes(gvr schema.GroupVersionResource, path ...string) {
+	namespace := opt.Get().Global.Namespace
+	objs, err := cluster.Ins().GetCustomResourcesInNamespace(gvr, namespace)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to list route resources, please restore them manually")
+		return
+	}
+	for _, item := range objs.Items {
+		if item.GetAnnotations()[util.KtRouteOrigin] == "" || item.GetAnnotations()[util.KtSession] != opt.Store.Session {
+			continue
+		}
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			obj, err2 := cluster.Ins().GetCustomResource(gvr, item.GetName(), namespace)
+			if err2 != nil {
+				return err2
+			}
+			var rules []interface{}
+			if err2 = json.Unmarshal([]byte(obj.GetAnnotations()[util.KtRouteOrigin]), &rules); err2 != nil {
+				return err2
+			}
+			if err2 = unstructured.SetNestedSlice(obj.Object, rules, path...); err2 != nil {
+				return err2
+			}
+			annotations := obj.GetAnnotations()
+			delete(annotations, util.KtRouteOrigin)
+			delete(annotations, util.KtSession)
+			obj.SetAnnotations(annotations)
+			_, err2 = cluster.Ins().UpdateCustomResource(gvr, obj)
+			return err2
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to restore %s %s, origin rules are kept in its '%s' annotation",
+				item.GetKind(), item.GetName(), util.KtRouteOrigin)
+		} else {
+			log.Info().Msgf("%s %s restored", item.GetKind(), item.GetName())
+		}
+	}
+}
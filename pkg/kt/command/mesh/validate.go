@@ -0,0 +1,129 @@
+package mesh
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strings"
+)
+
+// CheckOptions verify mesh options are valid and compatible with mesh mode
+func CheckOptions() error {
+	mode := opt.Get().Mesh.Mode
+	if mode != util.MeshModeAuto && mode != util.MeshModeManual {
+		return fmt.Errorf("invalid mesh method '%s', supportted are %s, %s", mode, util.MeshModeAuto, util.MeshModeManual)
+	}
+	if opt.Get().Mesh.FallbackOn != "" && mode != util.MeshModeAuto {
+		return fmt.Errorf("'--fallbackOn' is only supported in %s mode", util.MeshModeAuto)
+	}
+	if opt.Get().Mesh.MeshCookie != "" && mode != util.MeshModeAuto {
+		return fmt.Errorf("'--meshCookie' is only supported in %s mode", util.MeshModeAuto)
+	}
+	if opt.Get().Mesh.GrpcMethod != "" && mode != util.MeshModeAuto {
+		return fmt.Errorf("'--grpcMethod' is only supported in %s mode", util.MeshModeAuto)
+	}
+	if opt.Get().Mesh.TraceTag != "" && mode != util.MeshModeAuto {
+		return fmt.Errorf("'--traceTag' is only supported in %s mode", util.MeshModeAuto)
+	}
+	if opt.Get().Mesh.AllowNoEndpoints && mode != util.MeshModeAuto {
+		return fmt.Errorf("'--allowNoEndpoints' is only supported in %s mode", util.MeshModeAuto)
+	}
+	if weight := opt.Get().Mesh.MeshWeight; weight < 0 || weight > 100 {
+		return fmt.Errorf("mesh weight should between 0 and 100, but got %d", weight)
+	} else if weight > 0 && mode != util.MeshModeAuto {
+		return fmt.Errorf("'--meshWeight' is only supported in %s mode", util.MeshModeAuto)
+	}
+	if err := checkMeshHeader(opt.Get().Mesh.MeshHeader, opt.Get().Mesh.MeshHeaderValue); err != nil {
+		return err
+	}
+	if backend := opt.Get().Mesh.RoutingBackend; backend != util.RoutingBackendAuto {
+		if _, err := newRoutingBackend(backend); err != nil {
+			return err
+		}
+	}
+	if _, err := parseFallbackCodes(opt.Get().Mesh.FallbackOn); err != nil {
+		return err
+	}
+	if _, err := parseCookieMark(opt.Get().Mesh.MeshCookie); err != nil {
+		return err
+	}
+	if _, err := parseGrpcMethods(opt.Get().Mesh.GrpcMethod); err != nil {
+		return err
+	}
+	if _, err := parseTraceTag(opt.Get().Mesh.TraceTag); err != nil {
+		return err
+	}
+	if err := general.CheckExposePorts(opt.Get().Mesh.Expose); err != nil {
+		return err
+	}
+	return checkRoutingProtocols()
+}
+
+// checkRoutingProtocols verify routing rules of auto mode can match requests of expose port protocols
+func checkRoutingProtocols() error {
+	if opt.Get().Mesh.Mode != util.MeshModeAuto {
+		return nil
+	}
+	for _, exposePort := range strings.Split(opt.Get().Mesh.Expose, ",") {
+		_, remotePort, protocol, _ := util.ParseExposePort(exposePort)
+		switch protocol {
+		case util.ExposeProtocolTcp:
+			if opt.Get().Mesh.GrpcMethod != "" {
+				return fmt.Errorf("port %d is plain tcp, which has no grpc method, '--grpcMethod' is not applicable", remotePort)
+			}
+			return fmt.Errorf("port %d is plain tcp, which cannot be routed by header in %s mode, please use %s mode instead",
+				remotePort, util.MeshModeAuto, util.MeshModeManual)
+		case util.ExposeProtocolGrpc:
+			if opt.Get().Mesh.MeshCookie != "" {
+				return fmt.Errorf("port %d is grpc, which cannot be routed by cookie, '--meshCookie' is not applicable", remotePort)
+			}
+			if opt.Get().Mesh.FallbackOn != "" {
+				return fmt.Errorf("port %d is grpc, whose status is not in http status code, '--fallbackOn' is not applicable", remotePort)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckRoutingBackend verify routing backend of auto mesh is available in cluster
+func CheckRoutingBackend() error {
+	if opt.Get().Mesh.Mode != util.MeshModeAuto {
+		return nil
+	}
+	backend, err := getRoutingBackend(opt.Get().Mesh.RoutingBackend)
+	if err != nil {
+		return err
+	}
+	return checkBackendOptions(backend)
+}
+
+// Permissions kubernetes permissions required by current mesh mode
+func Permissions() []cluster.PermissionRule {
+	if opt.Get().Mesh.Mode != util.MeshModeAuto {
+		return general.ShadowPermissions()
+	}
+	rules := append(general.ShadowPermissions(), cluster.PermissionRule{Verb: "create", Resource: "services"})
+	switch opt.Get().Mesh.RoutingBackend {
+	case util.RoutingBackendIstio:
+		return append(rules, routeResourcePermissions(virtualServiceGvr)...)
+	case util.RoutingBackendGatewayApi:
+		return append(rules, routeResourcePermissions(httpRouteGvr)...)
+	}
+	return append(rules,
+		cluster.PermissionRule{Verb: "update", Resource: "services"},
+		cluster.PermissionRule{Verb: "delete", Resource: "services"},
+		cluster.PermissionRule{Verb: "create", Resource: "pods"},
+		cluster.PermissionRule{Verb: "update", Resource: "pods"},
+		cluster.PermissionRule{Verb: "create", Resource: "pods", Subresource: "exec"})
+}
+
+func routeResourcePermissions(gvr schema.GroupVersionResource) []cluster.PermissionRule {
+	rules := make([]cluster.PermissionRule, 0)
+	for _, verb := range []string{"list", "create", "update", "delete"} {
+		rules = append(rules, cluster.PermissionRule{Verb: verb, Group: gvr.Group, Resource: gvr.Resource})
+	}
+	return rules
+}
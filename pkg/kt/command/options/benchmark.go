@@ -0,0 +1,37 @@
+package options
+
+func BenchmarkFlags() []OptionConfig {
+	flags := []OptionConfig{
+		{
+			Target:       "Port",
+			DefaultValue: 0,
+			Description:  "Port of the service to request, can be omitted if the service has only one port",
+		},
+		{
+			Target:       "Path",
+			DefaultValue: "/",
+			Description:  "Http path to request",
+		},
+		{
+			Target:       "Requests",
+			DefaultValue: 100,
+			Description:  "Number of requests to send via each route",
+		},
+		{
+			Target:       "Concurrency",
+			DefaultValue: 4,
+			Description:  "Number of connections sending requests at the same time",
+		},
+		{
+			Target:       "Socks",
+			DefaultValue: "",
+			Description:  "Send requests via socks5 proxy of connect at specified address, e.g. 127.0.0.1:2223, required in socks5 mode",
+		},
+		{
+			Target:       "SkipBaseline",
+			DefaultValue: false,
+			Description:  "Do not compare with requests via kubernetes port-forward",
+		},
+	}
+	return flags
+}
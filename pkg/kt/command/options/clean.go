@@ -21,6 +21,16 @@ func CleanFlags() []OptionConfig {
 			DefaultValue: false,
 			Description:  "Only check and restore local changes made by kt",
 		},
+		{
+			Target:       "Session",
+			DefaultValue: "",
+			Description:  "Only clean up resources created by ktctl instance of specified session id",
+		},
+		{
+			Target:       "OlderThan",
+			DefaultValue: "",
+			Description:  "Only clean up resources not alive for longer than specified duration, e.g. 24h",
+		},
 	}
 	return flags
 }
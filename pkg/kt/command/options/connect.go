@@ -9,7 +9,7 @@ func ConnectFlags() []OptionConfig {
 		{
 			Target:      "Mode",
 			DefaultValue: util.ConnectModeTun2Socks,
-			Description: "Connect mode 'tun2socks' or 'sshuttle'",
+			Description: "Connect mode 'tun2socks', 'socks5', 'sshuttle' or 'auto'",
 		},
 		{
 			Target:      "DnsMode",
@@ -71,11 +71,41 @@ func ConnectFlags() []OptionConfig {
 			DefaultValue: "127.0.0.1",
 			Description: "(tun2socks mode only) Specify the ip address or hostname which socks5 proxy should use",
 		},
+		{
+			Target:      "HttpProxyPort",
+			DefaultValue: 0,
+			Description: "(tun2socks/socks5 mode only) Also serve http proxy supporting CONNECT method on specified local port",
+		},
+		{
+			Target:      "PacPort",
+			DefaultValue: 0,
+			Description: "(tun2socks/socks5 mode only) Serve proxy auto-config file of cluster hosts on specified local port",
+		},
+		{
+			Target:      "FlushDnsOnStop",
+			DefaultValue: true,
+			Description: "Flush dns cache of system resolver when connect stopped, use '--flushDnsOnStop=false' to disable",
+		},
 		{
 			Target:      "DnsCacheTtl",
 			DefaultValue: 60,
 			Description: "(local dns mode only) DNS cache refresh interval in seconds",
 		},
+		{
+			Target:      "Reconnect",
+			DefaultValue: false,
+			Description: "Tear down and re-establish the connection with same session when tunnel to shadow pod broken",
+		},
+		{
+			Target:      "PodDomain",
+			DefaultValue: false,
+			Description: "(local dns mode only) Also resolve '<pod>.<service>.<namespace>' to ip of the pod",
+		},
+		{
+			Target:      "Fwmark",
+			DefaultValue: 0,
+			Description: "(linux only) Mark connections to api server with specified fwmark, for policy routing on gateway host",
+		},
 	}
 	if util.IsMacos() {
 		flags = append(flags,
@@ -1,30 +1,174 @@
 package options
 
-import "github.com/alibaba/kt-connect/pkg/kt/util"
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+)
 
 func ExchangeFlags() []OptionConfig {
 	flags := []OptionConfig{
 		{
 			Target:       "Expose",
 			DefaultValue: "",
-			Description:  "Ports to expose, use ',' separated, in [port] or [local:remote] format, e.g. 7001,8080:80",
-			Required:     true,
+			Description:  "Ports to expose, use ',' separated, in [port] or [local:remote] format with optional /protocol, e.g. 7001,8080:80/http",
+		},
+		{
+			Target:       "ExposeFrom",
+			DefaultValue: "",
+			Description:  "Derive ports to expose from a Service or Deployment manifest file, instead of '--expose'",
+		},
+		{
+			Target:       "AutoExpose",
+			DefaultValue: false,
+			Description:  "Expose the only non-system port listened on local machine when '--expose' is not specified",
 		},
 		{
 			Target:       "Mode",
 			DefaultValue: util.ExchangeModeSelector,
-			Description:  "Exchange method 'selector', 'scale' or 'ephemeral'(experimental)",
+			Description:  "Exchange method 'selector', 'scale' or 'ephemeral'(experimental), multiple services can only be exchanged together in 'selector' mode",
 		},
 		{
 			Target:       "SkipPortChecking",
 			DefaultValue: false,
 			Description:  "Do not check whether specified local ports are listened",
 		},
+		{
+			Target:       "LocalAddr",
+			DefaultValue: "",
+			Description:  "Local address to forward connections of expose ports to, e.g. ip of a vpn interface, use 127.0.0.1 if not specified",
+		},
+		{
+			Target:       "ExecProbe",
+			DefaultValue: false,
+			Description:  "Send a probe request to the service after exchange is ready, and verify it reaches local",
+		},
+		{
+			Target:       "LocalRateLimit",
+			DefaultValue: 0,
+			Description:  "Max connections per second forwarded to local, 0 means no limit",
+		},
+		{
+			Target:       "OverloadAction",
+			DefaultValue: util.OverloadActionQueue,
+			Description:  "Action for connections exceeding local rate limit, 'queue' or 'shed'",
+		},
+		{
+			Target:       "FallbackOnOverload",
+			DefaultValue: false,
+			Description:  "(selector method only) Forward shed connections to original pods instead of dropping them",
+		},
+		{
+			Target:       "NoShadow",
+			DefaultValue: false,
+			Description:  "(selector method only) Let service endpoints point to local directly instead of via shadow pod, require local ip reachable from cluster",
+		},
+		{
+			Target:       "LocalIp",
+			DefaultValue: "",
+			Description:  "(no shadow only) Local ip address reachable from cluster, auto detect if not specified",
+		},
+		{
+			Target:       "SkipReachableCheck",
+			DefaultValue: false,
+			Description:  "(no shadow only) Do not check whether local ip is reachable from cluster",
+		},
+		{
+			Target:       "LocalReadyPath",
+			DefaultValue: "",
+			Description:  "Http path of local app to check readiness, requests only go to local when it returns 2xx",
+		},
+		{
+			Target:       "LocalReadyTimeout",
+			DefaultValue: 2,
+			Description:  "Seconds to wait for response of local ready path",
+		},
+		{
+			Target:       "Ramp",
+			DefaultValue: "",
+			Description:  "(selector method only) Shift traffic to local gradually, in '<start>:<end>:<duration>' format, e.g. 10:100:5m",
+		},
+		{
+			Target:       "PassthroughPorts",
+			DefaultValue: false,
+			Description:  "(selector and scale method only) Only exchange ports to expose, connections to other ports go to original pods",
+		},
+		{
+			Target:       "AuditFile",
+			DefaultValue: "",
+			Description:  "Verify cluster is restored after exchange stopped, and write the audit report to specified file in json",
+		},
+		{
+			Target:       "RequireAnnotation",
+			DefaultValue: "",
+			Description:  "Only exchange services having specified annotation, in 'key' or 'key=value' format",
+		},
+		{
+			Target:       "ApprovalWebhook",
+			DefaultValue: "",
+			Description:  "Post exchange details to specified url and only proceed when it responds with approval, also accept 'env:<VAR>' or 'file:<path>' reference",
+			Secret:       true,
+		},
+		{
+			Target:       "ApprovalTimeout",
+			DefaultValue: 300,
+			Description:  "Seconds to wait for decision of approval webhook",
+		},
+		{
+			Target:       "SkipApproval",
+			DefaultValue: false,
+			Description:  "(emergency only) Proceed without asking approval webhook, the skip is logged",
+		},
+		{
+			Target:       "TlsTerminate",
+			DefaultValue: false,
+			Description:  "Terminate tls of requests to target service, and forward them to local in plain text",
+		},
+		{
+			Target:       "TlsCert",
+			DefaultValue: "",
+			Description:  "(tls terminate only) Cert file for terminating tls, a self-signed cert is generated if not specified",
+		},
+		{
+			Target:       "TlsKey",
+			DefaultValue: "",
+			Description:  "(tls terminate only) Private key file of the cert specified by --tlsCert",
+		},
+		{
+			Target:       "SetupRetries",
+			DefaultValue: 0,
+			Description:  "Times to retry the whole exchange setup on failure, partial changes are reverted before each retry",
+		},
+		{
+			Target:       "SharedShadow",
+			DefaultValue: "",
+			Description:  "(selector method only) Share one shadow pod with other exchanges using the same key, target ports of them must not overlap",
+		},
 		{
 			Target:       "RecoverWaitTime",
 			DefaultValue: 120,
 			Description:  "(scale method only) Seconds to wait for original deployment recover before turn off the shadow pod",
 		},
+		{
+			Target:       "RestoreOnExit",
+			DefaultValue: true,
+			Description:  "(scale method only) Scale original deployment back when exchange stopped, use '--restoreOnExit=false' to keep it down",
+		},
+		{
+			Target:       "DrainTimeout",
+			DefaultValue: 0,
+			Description:  "Seconds to wait for connections to local to finish before recovering when stopped, 0 means not wait",
+		},
+		{
+			Target:       "Selector",
+			DefaultValue: "",
+			Description:  "(selector method only) Exchange all services matching label selector instead of named ones, e.g. 'team=payments'",
+		},
+		{
+			Target:       "Yes",
+			DefaultValue: false,
+			Description: fmt.Sprintf("Exchange without confirmation when '--selector' matches more than %d services",
+				util.MaxUnconfirmedSelectedServices),
+		},
 	}
 	return flags
 }
@@ -45,6 +45,26 @@ func GlobalFlags() []OptionConfig {
 			DefaultValue: "",
 			Description:  "Specify location of shadow and route pod by node label, e.g. 'disk=ssd,region=hangzhou'",
 		},
+		{
+			Target:       "ShadowToleration",
+			DefaultValue: "",
+			Description:  "Tolerations of shadow and route pod, e.g. 'dedicated=debug:NoSchedule,gpu:NoExecute'",
+		},
+		{
+			Target:       "ShadowInitContainer",
+			DefaultValue: "",
+			Description:  "Init container of shadow pod, image optionally followed by command, e.g. 'cert-fetcher:1.0 /bin/fetch --out /certs'",
+		},
+		{
+			Target:       "ShadowPodPatch",
+			DefaultValue: "",
+			Description:  "Strategic merge patch applied to shadow pod, path of yaml file or inline yaml, e.g. 'spec: {priorityClassName: high}'",
+		},
+		{
+			Target:       "ShadowGracePeriod",
+			DefaultValue: 0,
+			Description:  "Seconds for shadow pod to drain existing connections before terminated, 0 means default of kubernetes",
+		},
 		{
 			Target:       "Debug",
 			Alias:        "d",
@@ -72,6 +92,16 @@ func GlobalFlags() []OptionConfig {
 			DefaultValue: 60,
 			Description:  "Seconds to wait before shadow or router pod creation timeout",
 		},
+		{
+			Target:       "ApiRetry",
+			DefaultValue: 3,
+			Description:  "Times to retry fetching target resource when api server is temporarily unavailable",
+		},
+		{
+			Target:       "ApiTimeout",
+			DefaultValue: 30,
+			Description:  "Seconds to keep retrying fetching target resource before give up",
+		},
 		{
 			Target:       "UseShadowDeployment",
 			DefaultValue: false,
@@ -104,6 +134,77 @@ func GlobalFlags() []OptionConfig {
 			DefaultValue: 4,
 			Description:  "network type connect local and remote,the value could be '4' or '6'",
 		},
+		{
+			Target:       "CopyBufferSize",
+			DefaultValue: util.DefaultCopyBufferSizeKb,
+			Description:  "Size in KB of buffer used for copying data through tunnel, should between 1 and 1024",
+		},
+		{
+			Target:       "PreserveSourceIp",
+			DefaultValue: "",
+			Description:  "Pass client ip through tunnel to local, via 'proxy' (PROXY protocol) or 'http' (X-Forwarded-For header)",
+		},
+		{
+			Target:       "LogCaller",
+			DefaultValue: false,
+			Description:  "Include source file and line number in log",
+		},
+		{
+			Target:       "Deadline",
+			DefaultValue: "",
+			Description:  "Stop and clean up after specified duration (e.g. 30m, 2h) regardless of current phase",
+		},
+		{
+			Target:       "SetupTimeout",
+			DefaultValue: 120,
+			Description:  "Seconds to wait for exchange, mesh or preview setup finished before give up, 0 means not limit",
+		},
+		{
+			Target:       "ReadyHook",
+			DefaultValue: "",
+			Description:  "Command to run once exchange, mesh or preview is ready, its failure does not stop the tunnel",
+		},
+		{
+			Target:       "HealthAddr",
+			DefaultValue: "",
+			Description:  "Address to serve '/healthz' and '/metrics' of running command, e.g. 127.0.0.1:9080",
+		},
+		{
+			Target:       "Profile",
+			DefaultValue: false,
+			Description:  "Reuse options last used for the target service, explicitly specified ones take precedence",
+		},
+		{
+			Target:       "AutoRestart",
+			DefaultValue: false,
+			Description:  "Restart tunnel instead of exit when it crashes by unexpected panic",
+		},
+		{
+			Target:       "DryRun",
+			DefaultValue: false,
+			Description:  "Print manifests of resources to be created or changed as yaml, instead of applying them",
+		},
+		{
+			Target:       "ValidateOnly",
+			DefaultValue: false,
+			Description:  "Only run pre-flight checks of options, local ports, target resource and permissions, then exit",
+		},
+		{
+			Target:       "PrintConfig",
+			DefaultValue: false,
+			Description:  "Print resolved value and source of each option of current command, then exit",
+		},
+		{
+			Target:       "AllowedNamespaces",
+			DefaultValue: "",
+			Description:  "Only allow working in specified namespaces, e.g. 'dev,test' (default from env KT_ALLOWED_NAMESPACES)",
+		},
+		{
+			Target:       "Output",
+			Alias:        "o",
+			DefaultValue: util.OutputText,
+			Description:  "Output format 'text' or 'json', print result of command to stdout as json when set to 'json'",
+		},
 	}
 	return flags
 }
@@ -10,7 +10,7 @@ func MeshFlags() []OptionConfig {
 		{
 			Target:       "Expose",
 			DefaultValue: "",
-			Description:  "Ports to expose, use ',' separated, in [port] or [local:remote] format, e.g. 7001,8080:80",
+			Description:  "Ports to expose, use ',' separated, in [port] or [local:remote] format with optional /protocol, e.g. 7001,8080:80/http",
 			Required:     true,
 		},
 		{
@@ -23,6 +23,16 @@ func MeshFlags() []OptionConfig {
 			DefaultValue: "",
 			Description:  "Specify the version of mesh service, e.g. '0.0.1' or 'mark:local'",
 		},
+		{
+			Target:       "MeshHeader",
+			DefaultValue: "",
+			Description:  "Route by an existing request header instead of generated version header, e.g. 'x-tenant-id'",
+		},
+		{
+			Target:       "MeshHeaderValue",
+			DefaultValue: "",
+			Description:  "Value of '--meshHeader' to redirect to local, a random value is generated if not specified",
+		},
 		{
 			Target:       "SkipPortChecking",
 			DefaultValue: false,
@@ -33,6 +43,46 @@ func MeshFlags() []OptionConfig {
 			DefaultValue: fmt.Sprintf("%s:v%s", util.ImageKtRouter, Store.Version),
 			Description:  "(auto method only) Customize router image",
 		},
+		{
+			Target:       "FallbackOn",
+			DefaultValue: "",
+			Description:  "(auto method only) Fall back to origin service when local service response with specified status code, e.g. '5xx' or '500,503'",
+		},
+		{
+			Target:       "MeshCookie",
+			DefaultValue: "",
+			Description:  "(auto method only) Also redirect requests with specified cookie to local, in 'name' or 'name=value' format",
+		},
+		{
+			Target:       "GrpcMethod",
+			DefaultValue: "",
+			Description:  "(auto method only) Only redirect calls of specified grpc methods to local, in 'package.Service/Method' format, use ',' separated",
+		},
+		{
+			Target:       "TraceTag",
+			DefaultValue: "",
+			Description:  "(auto method only) Add 'kt-connect=<tag>' baggage to requests redirected to local, to mark them in distributed tracing",
+		},
+		{
+			Target:       "MeshWeight",
+			DefaultValue: 0,
+			Description:  "(auto method only) Percentage of requests without version header also redirected to local, between 0 and 100",
+		},
+		{
+			Target:       "RoutingBackend",
+			DefaultValue: util.RoutingBackendAuto,
+			Description:  "(auto method only) Routing implementation 'router', 'istio' or 'gatewayapi', use istio if installed when set to 'auto'",
+		},
+		{
+			Target:       "DrainTimeout",
+			DefaultValue: 0,
+			Description:  "Seconds to wait for connections to local to finish before recovering when stopped, 0 means not wait",
+		},
+		{
+			Target:       "AllowNoEndpoints",
+			DefaultValue: false,
+			Description:  "(auto method only) Mesh service without ready endpoints, requests not redirected to local will fail",
+		},
 	}
 	return flags
 }
@@ -15,6 +15,9 @@ type OptionConfig struct {
 	Description string
 	Hidden bool
 	Required bool
+	Secret bool
+	// Repeatable string option specified multiple times is joined with ','
+	Repeatable bool
 }
 
 func SetOptions(cmd *cobra.Command, flags *flag.FlagSet, optionStore any, config []OptionConfig) {
@@ -32,7 +35,10 @@ func SetOptions(cmd *cobra.Command, flags *flag.FlagSet, optionStore any, config
 			if field.String() != "" {
 				defaultValue = field.String()
 			}
-			if c.Alias != "" {
+			if c.Repeatable {
+				*fieldPtr = defaultValue
+				flags.VarP(&joinedStringValue{value: fieldPtr}, name, c.Alias, c.Description)
+			} else if c.Alias != "" {
 				flags.StringVarP(fieldPtr, name, c.Alias, defaultValue, c.Description)
 			} else {
 				flags.StringVar(fieldPtr, name, defaultValue, c.Description)
@@ -66,5 +72,35 @@ func SetOptions(cmd *cobra.Command, flags *flag.FlagSet, optionStore any, config
 		if c.Required {
 			_ = cmd.MarkFlagRequired(name)
 		}
+		if c.Secret {
+			_ = flags.SetAnnotation(name, secretAnnotation, []string{"true"})
+		}
+	}
+}
+
+// joinedStringValue string flag accumulating values of each occurrence, default value is replaced by the first one
+type joinedStringValue struct {
+	value   *string
+	changed bool
+}
+
+func (v *joinedStringValue) String() string {
+	if v.value == nil {
+		return ""
 	}
-}
\ No newline at end of file
+	return *v.value
+}
+
+func (v *joinedStringValue) Set(s string) error {
+	if v.changed && *v.value != "" {
+		*v.value = *v.value + "," + s
+	} else {
+		*v.value = s
+	}
+	v.changed = true
+	return nil
+}
+
+func (v *joinedStringValue) Type() string {
+	return "string"
+}
@@ -0,0 +1,24 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepeatableOption(t *testing.T) {
+	cases := map[string][]string{
+		"":                  {},
+		"8080":              {"--expose", "8080"},
+		"8080,9090:90":      {"--expose", "8080,9090:90"},
+		"8080,9090:90,7001": {"--expose", "8080", "--expose", "9090:90", "--expose", "7001"},
+	}
+	for expected, args := range cases {
+		store := &PreviewOptions{}
+		cmd := &cobra.Command{Use: "preview"}
+		SetOptions(cmd, cmd.Flags(), store, []OptionConfig{{Target: "Expose", DefaultValue: "", Repeatable: true}})
+		require.Nil(t, cmd.Flags().Parse(args))
+		require.Equal(t, expected, store.Expose, "parsed value of %v", args)
+	}
+}
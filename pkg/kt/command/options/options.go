@@ -17,8 +17,14 @@ type ConnectOptions struct {
 	DisableTunRoute  bool
 	ProxyPort        int
 	ProxyAddr        string
+	HttpProxyPort    int
+	PacPort          int
 	DnsPort          int
 	DnsCacheTtl      int
+	FlushDnsOnStop   bool
+	Reconnect        bool
+	Fwmark           int
+	PodDomain        bool
 	IncludeIps       string
 	ExcludeIps       string
 	IngressIp        string
@@ -32,10 +38,38 @@ type ConnectOptions struct {
 
 // ExchangeOptions ...
 type ExchangeOptions struct {
-	Mode             string
-	Expose           string
-	RecoverWaitTime  int
-	SkipPortChecking bool
+	Mode               string
+	Expose             string
+	ExposeFrom         string
+	AutoExpose         bool
+	RecoverWaitTime    int
+	RestoreOnExit      bool
+	SkipPortChecking   bool
+	LocalAddr          string
+	ExecProbe          bool
+	LocalRateLimit     int
+	OverloadAction     string
+	FallbackOnOverload bool
+	NoShadow           bool
+	LocalIp            string
+	SkipReachableCheck bool
+	PassthroughPorts   bool
+	LocalReadyPath     string
+	LocalReadyTimeout  int
+	AuditFile          string
+	RequireAnnotation  string
+	ApprovalWebhook    string
+	ApprovalTimeout    int
+	SkipApproval       bool
+	TlsTerminate       bool
+	TlsCert            string
+	TlsKey             string
+	SetupRetries       int
+	SharedShadow       string
+	Ramp               string
+	DrainTimeout       int
+	Selector           string
+	Yes                bool
 }
 
 // MeshOptions ...
@@ -43,8 +77,18 @@ type MeshOptions struct {
 	Mode             string
 	Expose           string
 	VersionMark      string
+	MeshHeader       string
+	MeshHeaderValue  string
 	RouterImage      string
 	SkipPortChecking bool
+	FallbackOn       string
+	MeshCookie       string
+	GrpcMethod       string
+	TraceTag         string
+	MeshWeight       int
+	RoutingBackend   string
+	DrainTimeout     int
+	AllowNoEndpoints bool
 }
 
 // RecoverOptions ...
@@ -55,18 +99,51 @@ type RecoverOptions struct {
 type PreviewOptions struct {
 	External         bool
 	Expose           string
+	AutoExpose       bool
 	SkipPortChecking bool
+	LocalAddr        string
+	ExecProbe        bool
+	WaitLocalReady   bool
+	LocalReadyPath   string
+	LocalReadyWait   int
+	Override         bool
+	TlsTerminate     bool
+	TlsCert          string
+	TlsKey           string
+	DrainTimeout     int
 }
 
 // ForwardOptions ...
 type ForwardOptions struct {
 }
 
+// BenchmarkOptions ...
+type BenchmarkOptions struct {
+	Port         int
+	Path         string
+	Requests     int
+	Concurrency  int
+	Socks        string
+	SkipBaseline bool
+}
+
 // CleanOptions ...
 type CleanOptions struct {
 	DryRun           bool
 	ThresholdInMinus int64
+	Session          string
 	LocalOnly        bool
+	OlderThan        string
+}
+
+// StatusOptions ...
+type StatusOptions struct {
+	Session string
+}
+
+// StopOptions ...
+type StopOptions struct {
+	Pid int
 }
 
 // ConfigOptions ...
@@ -90,10 +167,13 @@ type GlobalOptions struct {
 	Image               string
 	ImagePullSecret     string
 	NodeSelector        string
+	ShadowToleration    string
 	WithLabel           string
 	WithAnnotation      string
 	PortForwardTimeout  int
 	PodCreationTimeout  int
+	ApiRetry            int
+	ApiTimeout          int
 	UseShadowDeployment bool
 	ForceUpdate         bool
 	UseLocalTime        bool
@@ -101,20 +181,40 @@ type GlobalOptions struct {
 	PodQuota            string
 	ListenCheck         bool
 	IpVersion           int
+	CopyBufferSize      int
+	PreserveSourceIp    string
+	LogCaller           bool
+	Deadline            string
+	SetupTimeout        int
+	ReadyHook           string
+	HealthAddr          string
+	Profile             bool
+	AutoRestart         bool
+	DryRun              bool
+	ValidateOnly        bool
+	PrintConfig         bool
+	AllowedNamespaces   string
+	ShadowInitContainer string
+	ShadowPodPatch      string
+	ShadowGracePeriod   int
+	Output              string
 }
 
 // DaemonOptions cli options
 type DaemonOptions struct {
-	Connect  *ConnectOptions
-	Exchange *ExchangeOptions
-	Mesh     *MeshOptions
-	Preview  *PreviewOptions
-	Forward  *ForwardOptions
-	Recover  *RecoverOptions
-	Clean    *CleanOptions
-	Config   *ConfigOptions
-	Birdseye *BirdseyeOptions
-	Global   *GlobalOptions
+	Connect   *ConnectOptions
+	Exchange  *ExchangeOptions
+	Mesh      *MeshOptions
+	Preview   *PreviewOptions
+	Forward   *ForwardOptions
+	Benchmark *BenchmarkOptions
+	Recover   *RecoverOptions
+	Clean     *CleanOptions
+	Status    *StatusOptions
+	Stop      *StopOptions
+	Config    *ConfigOptions
+	Birdseye  *BirdseyeOptions
+	Global    *GlobalOptions
 }
 
 var opt *DaemonOptions
@@ -123,16 +223,19 @@ var opt *DaemonOptions
 func Get() *DaemonOptions {
 	if opt == nil {
 		opt = &DaemonOptions{
-			Global:   &GlobalOptions{},
-			Connect:  &ConnectOptions{},
-			Exchange: &ExchangeOptions{},
-			Mesh:     &MeshOptions{},
-			Preview:  &PreviewOptions{},
-			Forward:  &ForwardOptions{},
-			Recover:  &RecoverOptions{},
-			Clean:    &CleanOptions{},
-			Birdseye: &BirdseyeOptions{},
-			Config:   &ConfigOptions{},
+			Global:    &GlobalOptions{},
+			Connect:   &ConnectOptions{},
+			Exchange:  &ExchangeOptions{},
+			Mesh:      &MeshOptions{},
+			Preview:   &PreviewOptions{},
+			Forward:   &ForwardOptions{},
+			Benchmark: &BenchmarkOptions{},
+			Recover:   &RecoverOptions{},
+			Clean:     &CleanOptions{},
+			Status:    &StatusOptions{},
+			Stop:      &StopOptions{},
+			Birdseye:  &BirdseyeOptions{},
+			Config:    &ConfigOptions{},
 		}
 		if customize, exist := GetCustomizeKtConfig(); exist {
 			mergeOptions(opt, []byte(customize))
@@ -5,8 +5,13 @@ func PreviewFlags() []OptionConfig {
 		{
 			Target:       "Expose",
 			DefaultValue: "",
-			Description:  "Ports to expose, use ',' separated, in [port] or [local:remote] format, e.g. 7001,8080:80",
-			Required:     true,
+			Description:  "Ports to expose, use ',' separated or specify multiple times, in [port] or [local:remote] format, e.g. 7001,8080:80",
+			Repeatable:   true,
+		},
+		{
+			Target:       "AutoExpose",
+			DefaultValue: false,
+			Description:  "Expose the only non-system port listened on local machine when '--expose' is not specified",
 		},
 		{
 			Target:       "External",
@@ -18,6 +23,56 @@ func PreviewFlags() []OptionConfig {
 			DefaultValue: false,
 			Description:  "Do not check whether specified local ports are listened",
 		},
+		{
+			Target:       "LocalAddr",
+			DefaultValue: "",
+			Description:  "Local address to forward connections of expose ports to, e.g. ip of a vpn interface, use 127.0.0.1 if not specified",
+		},
+		{
+			Target:       "ExecProbe",
+			DefaultValue: false,
+			Description:  "Send a probe request to the service after preview is ready, and verify it reaches local",
+		},
+		{
+			Target:       "WaitLocalReady",
+			DefaultValue: false,
+			Description:  "Only publish endpoints of preview service when local app is ready, and withdraw them when it goes down",
+		},
+		{
+			Target:       "LocalReadyPath",
+			DefaultValue: "",
+			Description:  "(wait local ready only) Http path of local app to check readiness, local app is ready when it returns 2xx",
+		},
+		{
+			Target:       "LocalReadyWait",
+			DefaultValue: 60,
+			Description:  "(wait local ready only) Seconds to wait for local app to be ready after preview started, 0 means wait forever",
+		},
+		{
+			Target:       "Override",
+			DefaultValue: false,
+			Description:  "Take over existing service of same name not created by kt, its original selector is restored when preview stopped",
+		},
+		{
+			Target:       "TlsTerminate",
+			DefaultValue: false,
+			Description:  "Terminate tls of requests to preview service, and forward them to local in plain text",
+		},
+		{
+			Target:       "TlsCert",
+			DefaultValue: "",
+			Description:  "(tls terminate only) Cert file for terminating tls, a self-signed cert is generated if not specified",
+		},
+		{
+			Target:       "TlsKey",
+			DefaultValue: "",
+			Description:  "(tls terminate only) Private key file of the cert specified by --tlsCert",
+		},
+		{
+			Target:       "DrainTimeout",
+			DefaultValue: 0,
+			Description:  "Seconds to wait for connections to local to finish before recovering when stopped, 0 means not wait",
+		},
 	}
 	return flags
 }
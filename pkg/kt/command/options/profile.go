@@ -0,0 +1,61 @@
+package options
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProfilePath path of file keeping options last used for service, e.g. ~/.kt/profiles/default/tomcat.yaml
+func ProfilePath(namespace, service string) string {
+	return filepath.Join(util.KtServiceProfileDir, namespace, strings.ReplaceAll(service, "/", "_")+".yaml")
+}
+
+// LoadProfile set options of command not explicitly specified to values saved in profile,
+// profile is in same format as config file, only item of current command is used
+func LoadProfile(cmd *cobra.Command, path string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	profile := make(map[string]map[string]string)
+	if err = yaml.Unmarshal(data, &profile); err != nil {
+		return false, fmt.Errorf("invalid profile %s: %s", path, err)
+	}
+	for name, value := range profile[cmd.Name()] {
+		f := cmd.LocalFlags().Lookup(name)
+		if f == nil || f.Changed || f.Annotations[secretAnnotation] != nil {
+			continue
+		}
+		if err = f.Value.Set(value); err != nil {
+			return false, fmt.Errorf("invalid value '%s' of option '%s' in profile %s: %s", value, name, path, err)
+		}
+	}
+	return true, nil
+}
+
+// SaveProfile write options of command different from default value to profile, secret options are never saved
+func SaveProfile(cmd *cobra.Command, path string) error {
+	items := make(map[string]string)
+	cmd.LocalFlags().VisitAll(func(f *flag.Flag) {
+		if f.Annotations[secretAnnotation] == nil && f.Value.String() != f.DefValue {
+			items[f.Name] = f.Value.String()
+		}
+	})
+	data, err := yaml.Marshal(map[string]map[string]string{cmd.Name(): items})
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,48 @@
+package options
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newProfileTestCommand(store *ExchangeOptions) *cobra.Command {
+	cmd := &cobra.Command{Use: "exchange"}
+	SetOptions(cmd, cmd.Flags(), store, []OptionConfig{
+		{Target: "Mode", DefaultValue: "selector"},
+		{Target: "Expose", DefaultValue: ""},
+		{Target: "RecoverWaitTime", DefaultValue: 120},
+		{Target: "ApprovalWebhook", DefaultValue: "", Secret: true},
+	})
+	return cmd
+}
+
+func TestSaveAndLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prod", "orders.yaml")
+	store := &ExchangeOptions{}
+	cmd := newProfileTestCommand(store)
+	require.NoError(t, cmd.Flags().Parse([]string{"--mode", "ephemeral", "--expose", "8080:8080",
+		"--approvalWebhook", "https://ops.example.com/approve?token=abc"}))
+	require.NoError(t, SaveProfile(cmd, path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "token")
+	require.NotContains(t, string(data), "recoverWaitTime")
+
+	store = &ExchangeOptions{}
+	cmd = newProfileTestCommand(store)
+	require.NoError(t, cmd.Flags().Parse([]string{"--expose", "9090"}))
+	loaded, err := LoadProfile(cmd, path)
+	require.NoError(t, err)
+	require.True(t, loaded)
+	require.Equal(t, "ephemeral", store.Mode)
+	require.Equal(t, "9090", store.Expose, "explicit option should take precedence")
+	require.Equal(t, "", store.ApprovalWebhook)
+	require.Equal(t, 120, store.RecoverWaitTime)
+
+	loaded, err = LoadProfile(cmd, filepath.Join(t.TempDir(), "not-exist.yaml"))
+	require.NoError(t, err)
+	require.False(t, loaded)
+}
@@ -0,0 +1,79 @@
+package options
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+// secretAnnotation flag annotation marking option whose value could be a secret reference
+const secretAnnotation = "kt-secret"
+
+// SecretResolver fetch value of sensitive option from where it is stored
+type SecretResolver interface {
+	// Resolve get secret value by reference, which is the part after '<scheme>:' prefix
+	Resolve(ref string) (string, error)
+}
+
+// secretResolvers resolvers of secret reference, key is scheme
+var secretResolvers = map[string]SecretResolver{
+	"env":  envSecretResolver{},
+	"file": fileSecretResolver{},
+}
+
+// RegisterSecretResolver let references with specified scheme resolved by the resolver
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolvers[scheme] = resolver
+}
+
+// IsSecretReference whether value refers to a secret instead of being the secret itself
+func IsSecretReference(value string) bool {
+	scheme, _, found := strings.Cut(value, ":")
+	_, exists := secretResolvers[scheme]
+	return found && exists
+}
+
+// ResolveSecretOptions replace secret references in sensitive options of command with values they refer to,
+// must be called after options printed, so that resolved secrets never appear in output
+func ResolveSecretOptions(cmd *cobra.Command) error {
+	var err error
+	cmd.Flags().VisitAll(func(f *flag.Flag) {
+		if err != nil || f.Annotations[secretAnnotation] == nil || !IsSecretReference(f.Value.String()) {
+			return
+		}
+		scheme, ref, _ := strings.Cut(f.Value.String(), ":")
+		value, err2 := secretResolvers[scheme].Resolve(ref)
+		if err2 != nil {
+			err = fmt.Errorf("failed to resolve secret of option '--%s': %s", f.Name, err2)
+			return
+		}
+		err = f.Value.Set(value)
+	})
+	return err
+}
+
+// envSecretResolver read secret from environment variable, e.g. 'env:APPROVAL_WEBHOOK'
+type envSecretResolver struct{}
+
+func (r envSecretResolver) Resolve(ref string) (string, error) {
+	value, exists := os.LookupEnv(ref)
+	if !exists {
+		return "", fmt.Errorf("environment variable '%s' is not set", ref)
+	}
+	return value, nil
+}
+
+// fileSecretResolver read secret from content of file, e.g. 'file:/run/secrets/approval-webhook'
+type fileSecretResolver struct{}
+
+func (r fileSecretResolver) Resolve(ref string) (string, error) {
+	data, err := ioutil.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,34 @@
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecretOptions(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "webhook")
+	require.Nil(t, os.WriteFile(secretFile, []byte("https://ops.example.com/approve?token=abc\n"), 0600))
+	t.Setenv("KT_TEST_WEBHOOK", "https://ops.example.com/approve?token=def")
+	cases := map[string]string{
+		"env:KT_TEST_WEBHOOK":          "https://ops.example.com/approve?token=def",
+		"file:" + secretFile:           "https://ops.example.com/approve?token=abc",
+		"https://ops.example.com/hook": "https://ops.example.com/hook",
+		"":                             "",
+	}
+	for input, expected := range cases {
+		store := &ExchangeOptions{ApprovalWebhook: input}
+		cmd := &cobra.Command{Use: "exchange"}
+		SetOptions(cmd, cmd.Flags(), store, []OptionConfig{{Target: "ApprovalWebhook", DefaultValue: "", Secret: true}})
+		require.Nil(t, ResolveSecretOptions(cmd))
+		require.Equal(t, expected, store.ApprovalWebhook, "resolved value of '%s'", input)
+	}
+
+	store := &ExchangeOptions{ApprovalWebhook: "env:KT_TEST_NOT_EXIST"}
+	cmd := &cobra.Command{Use: "exchange"}
+	SetOptions(cmd, cmd.Flags(), store, []OptionConfig{{Target: "ApprovalWebhook", DefaultValue: "", Secret: true}})
+	require.NotNil(t, ResolveSecretOptions(cmd))
+}
@@ -0,0 +1,12 @@
+package options
+
+func StatusFlags() []OptionConfig {
+	flags := []OptionConfig{
+		{
+			Target:       "Session",
+			DefaultValue: "",
+			Description:  "Only show instance of specified session id",
+		},
+	}
+	return flags
+}
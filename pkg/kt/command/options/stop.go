@@ -0,0 +1,12 @@
+package options
+
+func StopFlags() []OptionConfig {
+	flags := []OptionConfig{
+		{
+			Target:       "Pid",
+			DefaultValue: 0,
+			Description:  "Process id of the instance to stop, required when multiple instances are running",
+		},
+	}
+	return flags
+}
@@ -1,6 +1,7 @@
 package options
 
 import (
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -11,6 +12,8 @@ var Store = &RuntimeStore{}
 type RuntimeStore struct {
 	// Clientset for kubernetes operation
 	Clientset kubernetes.Interface
+	// DynamicClient for custom resource operation
+	DynamicClient dynamic.Interface
 	// RestConfig kubectl config
 	RestConfig *rest.Config
 	// Version ktctl version
@@ -23,12 +26,20 @@ type RuntimeStore struct {
 	Router string
 	// Mesh version of mesh pod
 	Mesh string
+	// RoutingBackend routing implementation used by auto mesh
+	RoutingBackend string
 	// Origin the origin deployment or service name
 	Origin string
+	// OriginKind kind of origin workload scaled by exchange, empty for deployment
+	OriginKind string
 	// Replicas the origin replicas
 	Replicas int32
 	// Service exposed service name
 	Service string
+	// Passthrough pod preserving original deployment in scale mode
+	Passthrough string
 	// isIpv6Cluster
 	Ipv6Cluster bool
+	// Session unique id of current ktctl instance
+	Session string
 }
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewPauseCommand return new pause command
+func NewPauseCommand() *cobra.Command {
+	return newControlCommand(general.CommandPause,
+		"Temporarily route traffic of a running exchange back to original pods")
+}
+
+// NewResumeCommand return new resume command
+func NewResumeCommand() *cobra.Command {
+	return newControlCommand(general.CommandResume,
+		"Route traffic of a paused exchange to local again")
+}
+
+func newControlCommand(command, usage string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   command,
+		Short: usage,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("name of component to %s is required", command)
+			} else if len(args) > 2 {
+				return fmt.Errorf("too many arguments specified (%s), should be component and optional resource name",
+					strings.Join(args, ","))
+			}
+			general.SetupLogger()
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceName := ""
+			if len(args) > 1 {
+				resourceName = args[1]
+			}
+			return SendControl(command, args[0], resourceName)
+		},
+		Example: fmt.Sprintf("ktctl %s <component> [resource-name]", command),
+	}
+	cmd.Long = cmd.Short
+	cmd.SetUsageTemplate(general.UsageTemplate(false))
+	return cmd
+}
+
+// SendControl send control command to the only running instance of component on specified resource
+func SendControl(command, component, resourceName string) error {
+	signalFiles := general.FindSignalFiles(component, resourceName)
+	if len(signalFiles) == 0 {
+		return fmt.Errorf("no running %s found", component)
+	} else if len(signalFiles) > 1 {
+		return fmt.Errorf("%d running %s found, please specify the resource name", len(signalFiles), component)
+	}
+	if err := general.SendControlCommand(signalFiles[0], command); err != nil {
+		return err
+	}
+	log.Info().Msgf("Command '%s' sent to %s, check its log for result", command, component)
+	return nil
+}
@@ -1,14 +1,14 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"time"
 
 	"github.com/alibaba/kt-connect/pkg/kt/command/general"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/command/preview"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -26,9 +26,15 @@ func NewPreviewCommand() *cobra.Command {
 			} else if len(args) > 1 {
 				return fmt.Errorf("too many service names are spcified (%s), should be one", strings.Join(args, ","))
 			}
-			return general.Prepare()
+			if err := general.Prepare(); err != nil {
+				return err
+			}
+			return general.LoadServiceProfile(cmd, args)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opt.Get().Global.ValidateOnly {
+				return validatePreview(args[0])
+			}
 			return Preview(args[0])
 		},
 		Example: "ktctl preview <service-name> [command options]",
@@ -41,24 +47,56 @@ func NewPreviewCommand() *cobra.Command {
 
 // Preview create a new service in cluster
 func Preview(serviceName string) error {
-	ch, err := general.SetupProcess(util.ComponentPreview)
+	ctx, ch, err := general.SetupProcess(util.ComponentPreview)
 	if err != nil {
 		return err
 	}
+	if err = resolvePreviewExpose(); err != nil {
+		return err
+	}
 
 	// Setup signal file watcher
-	signalFile := filepath.Join(os.TempDir(), fmt.Sprintf("ktctl-preview-signal-%d", os.Getpid()))
-	go watchPreviewSignalFile(signalFile, ch)
+	signalFile := general.SignalFilePath(util.ComponentPreview, serviceName)
+	go general.WatchSignalFile(signalFile, serviceName, ch)
+	pipeName := general.WatchControlPipe(util.ComponentPreview, ch)
 
-	if opt.Get().Mesh.SkipPortChecking {
-		if port := util.FindBrokenLocalPort(opt.Get().Preview.Expose); port != "" {
+	if err = general.CheckLocalAddr(opt.Get().Preview.LocalAddr); err != nil {
+		os.RemoveAll(signalFile)
+		return err
+	}
+	if err = preview.CheckLocalReadiness(); err != nil {
+		os.RemoveAll(signalFile)
+		return err
+	}
+	if err = preview.CheckExposePorts(); err != nil {
+		os.RemoveAll(signalFile)
+		return err
+	}
+	if !opt.Get().Preview.SkipPortChecking {
+		if err = general.CheckLocalPorts(opt.Get().Preview.Expose, opt.Get().Preview.LocalAddr); err != nil {
 			// Clean up signal file
 			os.RemoveAll(signalFile)
-			return fmt.Errorf("no application is running on port %s", port)
+			return err
 		}
 	}
 
-	if err = preview.Expose(serviceName); err != nil {
+	if err = general.CheckTlsOptions(opt.Get().Preview.TlsTerminate, opt.Get().Preview.TlsCert, opt.Get().Preview.TlsKey); err == nil {
+		if opt.Get().Preview.TlsTerminate {
+			err = general.SetupTlsTermination(serviceName, opt.Get().Preview.TlsCert, opt.Get().Preview.TlsKey)
+		}
+	}
+	if err != nil {
+		// Clean up signal file
+		os.RemoveAll(signalFile)
+		return err
+	}
+
+	var result *general.SetupResult
+	err = general.RunSetupWithTimeout(ctx, util.ComponentPreview, func(ctx context.Context) (setupErr error) {
+		result, setupErr = preview.Expose(serviceName)
+		return
+	})
+	if err != nil {
 		// Clean up signal file
 		os.RemoveAll(signalFile)
 		return err
@@ -66,39 +104,79 @@ func Preview(serviceName string) error {
 
 	// Move signal file cleanup to deferred function to ensure it's only cleaned up at the end
 	defer os.RemoveAll(signalFile)
+	if opt.Get().Global.DryRun {
+		general.PrintDryRunSummary(result)
+		return nil
+	}
 
 	log.Info().Msg("---------------------------------------------------------------")
 	log.Info().Msgf(" Now you can access your local service in cluster by name '%s'", serviceName)
 	log.Info().Msg("---------------------------------------------------------------")
-
-	if util.IsWindows() {
-		log.Info().Msgf("You can stop the preview by creating a signal file:")
-		log.Info().Msgf("PowerShell:   \"stop\" | Out-File -FilePath %s -Encoding ASCII", signalFile)
-		log.Info().Msgf("Command Prompt: echo stop > %s", signalFile)
-	} else {
-		log.Info().Msgf("You can stop the preview by creating a signal file: echo stop > %s", signalFile)
+	if opt.Get().Preview.ExecProbe {
+		if err = preview.Probe(serviceName); err != nil {
+			log.Error().Msgf("Probe failed, %s", err.Error())
+		}
 	}
 
+	general.MarkReady(result, signalFile, pipeName)
+	general.PrintSetupResult(result, signalFile, pipeName)
+	general.PrintStopHint("preview", signalFile, pipeName)
+
 	// watch background process, clean the workspace and exit if background process occur exception
-	s := <-ch
-	log.Info().Msgf("Terminal Signal is %s", s)
+	general.WaitForStop(ctx, ch)
+	general.WaitConnectionsDrained(opt.Get().Preview.DrainTimeout)
 	return nil
 }
 
-func watchPreviewSignalFile(signalFile string, ch chan os.Signal) {
-	// Create the signal file to indicate preview is ready
-	os.Create(signalFile)
-
-	for {
-		time.Sleep(1 * time.Second)
-
-		// Check if signal file contains "stop"
-		if content, err := os.ReadFile(signalFile); err == nil {
-			if strings.TrimSpace(string(content)) == "stop" {
-				// Send interrupt signal to the main routine
-				ch <- os.Interrupt
-				return
-			}
+// resolvePreviewExpose detect port to expose from local listening ports if '--expose' is not specified
+func resolvePreviewExpose() error {
+	pv := opt.Get().Preview
+	if pv.Expose == "" && pv.AutoExpose {
+		port, err := general.DetectExposePort()
+		if err != nil {
+			return err
 		}
+		pv.Expose = port
 	}
+	if pv.Expose == "" {
+		return fmt.Errorf("either --expose or --autoExpose is required")
+	}
+	return nil
+}
+
+func validatePreview(serviceName string) error {
+	return general.RunChecks([]general.Check{
+		{Name: "Preview options", Run: func() error {
+			if err := resolvePreviewExpose(); err != nil {
+				return err
+			}
+			if err := general.CheckTlsOptions(opt.Get().Preview.TlsTerminate, opt.Get().Preview.TlsCert,
+				opt.Get().Preview.TlsKey); err != nil {
+				return err
+			}
+			if err := general.CheckLocalAddr(opt.Get().Preview.LocalAddr); err != nil {
+				return err
+			}
+			if err := preview.CheckLocalReadiness(); err != nil {
+				return err
+			}
+			return preview.CheckExposePorts()
+		}},
+		{Name: "Local ports", Run: func() error {
+			if opt.Get().Preview.SkipPortChecking {
+				return nil
+			}
+			return general.CheckLocalPorts(opt.Get().Preview.Expose, opt.Get().Preview.LocalAddr)
+		}},
+		{Name: "Service name", Run: func() error {
+			_, err := preview.CheckExistingService(serviceName)
+			return err
+		}},
+		{Name: "Permissions", Run: func() error {
+			return general.CheckPermissions(append(general.ShadowPermissions(),
+				cluster.PermissionRule{Verb: "create", Resource: "services"},
+				cluster.PermissionRule{Verb: "update", Resource: "services"},
+				cluster.PermissionRule{Verb: "delete", Resource: "services"}))
+		}},
+	})
 }
@@ -2,6 +2,7 @@ package preview
 
 import (
 	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
 	"github.com/alibaba/kt-connect/pkg/kt/transmission"
@@ -11,7 +12,7 @@ import (
 )
 
 // Expose create a new service in cluster
-func Expose(serviceName string) error {
+func Expose(serviceName string) (*general.SetupResult, error) {
 	version := strings.ToLower(util.RandomString(5))
 	shadowPodName := fmt.Sprintf("%s-kt-%s", serviceName, version)
 	labels := map[string]string{
@@ -22,14 +23,39 @@ func Expose(serviceName string) error {
 		util.KtConfig: fmt.Sprintf("service=%s", serviceName),
 	}
 
-	return exposeLocalService(serviceName, shadowPodName, labels, annotations)
+	if err := exposeLocalService(serviceName, shadowPodName, labels, annotations); err != nil {
+		return nil, err
+	}
+	return general.NewSetupResult([]string{serviceName}, "", opt.Get().Preview.Expose), nil
+}
+
+// CheckExposePorts verify ports to expose are valid, and each of them become a distinct port of preview service
+func CheckExposePorts() error {
+	exposePorts := opt.Get().Preview.Expose
+	if err := general.CheckExposePorts(exposePorts); err != nil {
+		return err
+	}
+	remotePorts := make(map[int]string)
+	for _, exposePort := range strings.Split(exposePorts, ",") {
+		_, remotePort, _ := util.ParsePortMapping(exposePort)
+		if previous, exists := remotePorts[remotePort]; exists {
+			// service port is named after its port number
+			return fmt.Errorf("port '%s' and '%s' collide on service port kt-%d", previous, exposePort, remotePort)
+		}
+		remotePorts[remotePort] = exposePort
+	}
+	return nil
 }
 
 // exposeLocalService create shadow and expose service if need
 func exposeLocalService(serviceName, shadowPodName string, labels, annotations map[string]string) error {
+	existing, err := CheckExistingService(serviceName)
+	if err != nil {
+		return err
+	}
 
 	envs := make(map[string]string)
-	_, podName, privateKeyPath, err := cluster.Ins().GetOrCreateShadow(shadowPodName, labels, annotations, envs,
+	podIp, podName, privateKeyPath, err := cluster.Ins().GetOrCreateShadow(shadowPodName, labels, annotations, envs,
 		opt.Get().Preview.Expose, map[int]string{})
 	if err != nil {
 		return err
@@ -41,12 +67,49 @@ func exposeLocalService(serviceName, shadowPodName string, labels, annotations m
 	for _, exposePort := range portPairs {
 		_, remotePort, err2 := util.ParsePortMapping(exposePort)
 		if err2 != nil {
-			return err
+			return err2
 		}
 		// service port to target port
 		ports[remotePort] = remotePort
 	}
-	if _, err = cluster.Ins().CreateService(&cluster.SvcMetaAndSpec{
+	if existing != nil {
+		log.Info().Msgf("Taking over existing service %s", serviceName)
+		if err = takeOverService(serviceName, labels); err != nil {
+			return err
+		}
+	} else if err = createPreviewService(serviceName, labels, ports); err != nil {
+		return err
+	}
+	if opt.Get().Global.DryRun {
+		return nil
+	}
+
+	localAddr := opt.Get().Preview.LocalAddr
+	if _, err = transmission.ForwardPodToLocal(opt.Get().Preview.Expose, podName, privateKeyPath, localAddr); err != nil {
+		return err
+	}
+
+	if localAddr == "" {
+		localAddr = "127.0.0.1"
+	}
+	for _, exposePort := range portPairs {
+		localPort, remotePort, _ := util.ParsePortMapping(exposePort)
+		log.Info().Msgf("Forward remote %s:%d -> %s:%d", podName, remotePort, localAddr, localPort)
+	}
+	if opt.Get().Preview.WaitLocalReady {
+		return waitLocalReady(serviceName, podIp)
+	}
+	return nil
+}
+
+// createPreviewService create a new service selecting shadow pod
+func createPreviewService(serviceName string, labels map[string]string, ports map[int]int) error {
+	selectors := labels
+	if opt.Get().Preview.WaitLocalReady {
+		// service without selector, its endpoints are published after local app ready
+		selectors = nil
+	}
+	if _, err := cluster.Ins().CreateService(&cluster.SvcMetaAndSpec{
 		Meta: &cluster.ResourceMeta{
 			Name:        serviceName,
 			Namespace:   opt.Get().Global.Namespace,
@@ -55,16 +118,10 @@ func exposeLocalService(serviceName, shadowPodName string, labels, annotations m
 		},
 		External:  opt.Get().Preview.External,
 		Ports:     ports,
-		Selectors: labels,
+		Selectors: selectors,
 	}); err != nil {
 		return err
 	}
 	opt.Store.Service = serviceName
-
-	if _, err = transmission.ForwardPodToLocal(opt.Get().Preview.Expose, podName, privateKeyPath); err != nil {
-		return err
-	}
-
-	log.Info().Msgf("Forward remote %s:%v -> 127.0.0.1:%v", podName, opt.Get().Preview.Expose, opt.Get().Preview.Expose)
 	return nil
 }
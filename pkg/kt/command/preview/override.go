@@ -0,0 +1,60 @@
+package preview
+
+import (
+	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	coreV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// CheckExistingService refuse to use name of an existing service, unless it is not created by kt and '--override'
+// specified, return the service to take over, or nil if not exists
+func CheckExistingService(serviceName string) (*coreV1.Service, error) {
+	svc, err := general.GetServiceWithRetry(serviceName, opt.Get().Global.Namespace)
+	if k8sErrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if svc.Labels[util.ControlBy] == util.KubernetesToolkit {
+		return nil, fmt.Errorf("service '%s' already exists, which is created by another preview or mesh, "+
+			"please stop it first, or use 'ktctl clean' if it is residual", serviceName)
+	}
+	if !opt.Get().Preview.Override {
+		return nil, fmt.Errorf("service '%s' already exists and is not created by kt, "+
+			"use '--override' to take it over during preview", serviceName)
+	}
+	if svc.Annotations[util.KtSelector] != "" {
+		return nil, fmt.Errorf("service '%s' is being exchanged or meshed, cannot be taken over", serviceName)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service '%s' has no selector, cannot be taken over", serviceName)
+	}
+	if opt.Get().Preview.WaitLocalReady {
+		return nil, fmt.Errorf("'--waitLocalReady' cannot be used when taking over existing service")
+	}
+	if port := util.FindInvalidRemotePort(opt.Get().Preview.Expose, general.GetTargetPorts(svc)); port != "" {
+		return nil, fmt.Errorf("target port %s not exists in service %s", port, serviceName)
+	}
+	return svc, nil
+}
+
+// takeOverService let existing service select shadow pod, its original selector is recorded in annotation,
+// and recovered when preview stopped
+func takeOverService(serviceName string, labels map[string]string) error {
+	svc, err := general.LockService(serviceName, opt.Get().Global.Namespace, 0)
+	if err != nil {
+		return err
+	}
+	defer general.UnlockService(svc.Name, opt.Get().Global.Namespace)
+	if svc.Annotations[util.KtSelector] != "" {
+		return fmt.Errorf("service '%s' is being exchanged or meshed, cannot be taken over", serviceName)
+	}
+	if opt.Get().Preview.External && svc.Spec.Type != coreV1.ServiceTypeLoadBalancer {
+		return fmt.Errorf("service '%s' is not a LoadBalancer service, cannot be taken over with '--external'", serviceName)
+	}
+	opt.Store.Origin = serviceName
+	return general.UpdateServiceSelector(serviceName, opt.Get().Global.Namespace, labels)
+}
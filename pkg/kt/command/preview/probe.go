@@ -0,0 +1,18 @@
+package preview
+
+import (
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"strings"
+)
+
+// Probe verify requests to preview service are forwarded to local
+func Probe(serviceName string) error {
+	localPort, remotePort, err := util.ParsePortMapping(strings.Split(opt.Get().Preview.Expose, ",")[0])
+	if err != nil {
+		return err
+	}
+	// preview service port is always same as remote port
+	return general.ExecProbe(opt.Store.Shadow, serviceName, remotePort, localPort)
+}
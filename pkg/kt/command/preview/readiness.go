@@ -0,0 +1,133 @@
+package preview
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
+	"github.com/alibaba/kt-connect/pkg/kt/service/sshchannel"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// localReadyCheckTimeout seconds to wait for response of each readiness check
+const localReadyCheckTimeout = 2 * time.Second
+
+// waitLocalReady publish endpoints of preview service to shadow pod only after local app is ready,
+// then keep withdrawing and republishing them according to readiness of local app
+func waitLocalReady(serviceName, podIp string) error {
+	subsets, err := getShadowEndpointSubsets(podIp)
+	if err != nil {
+		return err
+	}
+	wait := time.Duration(opt.Get().Preview.LocalReadyWait) * time.Second
+	log.Info().Msgf("Waiting for local app to be ready before publishing service %s ...", serviceName)
+	reason := ""
+	for start := time.Now(); ; time.Sleep(time.Second) {
+		var ready bool
+		if ready, reason = checkLocalReady(); ready {
+			break
+		}
+		if wait > 0 && time.Since(start) > wait {
+			return fmt.Errorf("local app is not ready in %s: %s", wait, reason)
+		}
+		log.Debug().Msgf("Local app is not ready yet: %s", reason)
+	}
+	if err = cluster.Ins().SetServiceEndpoints(serviceName, opt.Get().Global.Namespace, subsets); err != nil {
+		return err
+	}
+	log.Info().Msgf("Local app is ready, endpoints of service %s published", serviceName)
+	go keepEndpointsWithReadiness(serviceName, subsets)
+	return nil
+}
+
+// keepEndpointsWithReadiness withdraw endpoints when local app goes down, and publish them again when it recovers
+func keepEndpointsWithReadiness(serviceName string, subsets []coreV1.EndpointSubset) {
+	published := true
+	for {
+		time.Sleep(time.Second)
+		ready, reason := checkLocalReady()
+		if ready == published {
+			continue
+		}
+		var err error
+		if ready {
+			err = cluster.Ins().SetServiceEndpoints(serviceName, opt.Get().Global.Namespace, subsets)
+		} else {
+			err = cluster.Ins().SetServiceEndpoints(serviceName, opt.Get().Global.Namespace, nil)
+		}
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed to update endpoints of service %s", serviceName)
+			continue
+		}
+		published = ready
+		if ready {
+			log.Info().Msgf("Local app is ready again, endpoints of service %s published", serviceName)
+		} else {
+			log.Warn().Msgf("Local app is not ready (%s), endpoints of service %s withdrawn", reason, serviceName)
+		}
+	}
+}
+
+// checkLocalReady all local ports are listened, and the first one responds 2xx on ready path if specified
+func checkLocalReady() (bool, string) {
+	for i, exposePort := range strings.Split(opt.Get().Preview.Expose, ",") {
+		localPort, _, err := util.ParsePortMapping(exposePort)
+		if err != nil {
+			return false, err.Error()
+		}
+		protocol := util.ExposeProtocolTcp
+		if i == 0 && opt.Get().Preview.LocalReadyPath != "" {
+			protocol = util.ExposeProtocolHttp
+		}
+		if ready, reason := sshchannel.CheckLocalReady(opt.Get().Preview.LocalAddr, localPort, protocol,
+			opt.Get().Preview.LocalReadyPath, localReadyCheckTimeout); !ready {
+			return false, reason
+		}
+	}
+	return true, ""
+}
+
+// getShadowEndpointSubsets generate endpoint subsets pointing to ports of shadow pod, port names same as preview service
+func getShadowEndpointSubsets(podIp string) ([]coreV1.EndpointSubset, error) {
+	var ports []coreV1.EndpointPort
+	for _, exposePort := range strings.Split(opt.Get().Preview.Expose, ",") {
+		_, remotePort, err := util.ParsePortMapping(exposePort)
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, coreV1.EndpointPort{Name: fmt.Sprintf("kt-%d", remotePort), Port: int32(remotePort),
+			Protocol: coreV1.ProtocolTCP})
+	}
+	return []coreV1.EndpointSubset{{
+		Addresses: []coreV1.EndpointAddress{{IP: podIp}},
+		Ports:     ports,
+	}}, nil
+}
+
+// CheckLocalReadiness verify options of waiting local app ready
+func CheckLocalReadiness() error {
+	pv := opt.Get().Preview
+	if !pv.WaitLocalReady {
+		if pv.LocalReadyPath != "" {
+			return fmt.Errorf("--localReadyPath of preview requires --waitLocalReady")
+		}
+		return nil
+	}
+	if pv.LocalReadyWait < 0 {
+		return fmt.Errorf("local ready wait should not be negative, but got %d", pv.LocalReadyWait)
+	}
+	if pv.LocalReadyPath == "" {
+		return nil
+	}
+	if !strings.HasPrefix(pv.LocalReadyPath, "/") {
+		return fmt.Errorf("local ready path should start with '/', but got '%s'", pv.LocalReadyPath)
+	}
+	if _, err := url.ParseRequestURI(pv.LocalReadyPath); err != nil {
+		return fmt.Errorf("invalid local ready path '%s': %s", pv.LocalReadyPath, err)
+	}
+	return nil
+}
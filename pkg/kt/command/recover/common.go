@@ -2,6 +2,7 @@ package recover
 
 import (
 	"fmt"
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
 	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
@@ -37,6 +38,9 @@ func HandleExchangedByScaleService(svc *coreV1.Service, deployment *appV1.Deploy
 	app := config["app"]
 	if replica > 0 && app != "" {
 		originReplica := int32(replica)
+		if config["kind"] == general.KindStatefulSet {
+			return cluster.Ins().ScaleStatefulSetTo(app, svc.Namespace, &originReplica)
+		}
 		return cluster.Ins().ScaleTo(app, svc.Namespace, &originReplica)
 	}
 	return nil
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewStatusCommand return new status command
+func NewStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show exchange, mesh and preview running on this machine",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("too many options specified (%s)", strings.Join(args, ","))
+			}
+			general.SetupLogger()
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Status()
+		},
+		Example: "ktctl status [command options]",
+	}
+	cmd.Long = cmd.Short
+	cmd.SetUsageTemplate(general.UsageTemplate(false))
+	opt.SetOptions(cmd, cmd.Flags(), opt.Get().Status, opt.StatusFlags())
+	return cmd
+}
+
+// Status list instances of exchange, mesh and preview found via their signal files
+func Status() error {
+	infos := make([]general.SignalFileInfo, 0)
+	for _, info := range general.ListSignalFiles(util.ComponentExchange, util.ComponentMesh, util.ComponentPreview) {
+		if isStatusSessionMatched(info) {
+			infos = append(infos, info)
+		}
+	}
+	if len(infos) == 0 {
+		if opt.Get().Status.Session != "" {
+			log.Info().Msgf("No exchange, mesh or preview of session %s is running", opt.Get().Status.Session)
+		} else {
+			log.Info().Msg("No exchange, mesh or preview is running")
+		}
+		return nil
+	}
+	orphaned := 0
+	log.Info().Msgf("%-10s %-8s %-32s %-16s %-12s %-10s %s", "COMPONENT", "PID", "SERVICE", "NAMESPACE", "SESSION",
+		"AGE", "STATUS")
+	for _, info := range infos {
+		status := "running"
+		if !info.Alive {
+			status = "orphaned"
+			orphaned++
+		} else if !general.IsReady(info.Path) {
+			status = "starting"
+		} else if general.IsPaused(info.Path) {
+			status = "paused"
+		}
+		log.Info().Msgf("%-10s %-8d %-32s %-16s %-12s %-10s %s", info.Component, info.Pid, valueOrDash(info.Service),
+			valueOrDash(info.Namespace), valueOrDash(info.Session), formatAge(info.Started), status)
+	}
+	if orphaned > 0 {
+		log.Info().Msgf("%d orphaned signal files left by exited process, run 'ktctl clean --localOnly' to remove them", orphaned)
+	}
+	return nil
+}
+
+// isStatusSessionMatched whether instance belongs to session specified by '--session', always true if not specified
+func isStatusSessionMatched(info general.SignalFileInfo) bool {
+	return opt.Get().Status.Session == "" || info.Session == opt.Get().Status.Session
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// formatAge show elapsed time since start in the largest unit, e.g. 45s, 12m, 3h, 2d
+func formatAge(started time.Time) string {
+	if started.IsZero() {
+		return "-"
+	}
+	age := time.Since(started)
+	if age < time.Minute {
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	} else if age < time.Hour {
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	} else if age < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/alibaba/kt-connect/pkg/kt/command/general"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewStopCommand return new stop command
+func NewStopCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop a running exchange, mesh or preview",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("name of component to stop is required")
+			} else if len(args) > 2 {
+				return fmt.Errorf("too many arguments specified (%s), should be component and optional resource name",
+					strings.Join(args, ","))
+			} else if !util.Contains([]string{util.ComponentExchange, util.ComponentMesh, util.ComponentPreview}, args[0]) {
+				return fmt.Errorf("invalid component '%s', supported are %s, %s and %s", args[0],
+					util.ComponentExchange, util.ComponentMesh, util.ComponentPreview)
+			}
+			general.SetupLogger()
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceName := ""
+			if len(args) > 1 {
+				resourceName = args[1]
+			}
+			return StopComponent(args[0], resourceName, opt.Get().Stop.Pid)
+		},
+		Example: "ktctl stop <component> [resource-name] [command options]",
+	}
+	cmd.Long = cmd.Short
+	cmd.SetUsageTemplate(general.UsageTemplate(false))
+	opt.SetOptions(cmd, cmd.Flags(), opt.Get().Stop, opt.StopFlags())
+	return cmd
+}
+
+// StopComponent send stop command to running instance of component on specified resource,
+// list the instances if more than one matched and pid not specified
+func StopComponent(component, resourceName string, pid int) error {
+	var matched []string
+	for _, signalFile := range general.FindSignalFiles(component, resourceName) {
+		if _, _, p, _ := general.ParseSignalFileName(filepath.Base(signalFile)); pid <= 0 || p == pid {
+			matched = append(matched, signalFile)
+		}
+	}
+	if len(matched) == 0 {
+		if pid > 0 {
+			return fmt.Errorf("no running %s found with pid %d", component, pid)
+		}
+		return fmt.Errorf("no running %s found", component)
+	} else if len(matched) > 1 {
+		log.Info().Msgf("Running %s instances:", component)
+		for _, signalFile := range matched {
+			_, resource, p, _ := general.ParseSignalFileName(filepath.Base(signalFile))
+			log.Info().Msgf("> pid %d: %s", p, resource)
+		}
+		return fmt.Errorf("%d running %s found, please specify one with --pid", len(matched), component)
+	}
+	if err := general.SendControlCommand(matched[0], general.CommandStop); err != nil {
+		return err
+	}
+	_, _, p, _ := general.ParseSignalFileName(filepath.Base(matched[0]))
+	log.Info().Msgf("Stop command sent to %s at pid %d", component, p)
+	return nil
+}
@@ -1,7 +1,6 @@
 package cluster
 
 import (
-	"context"
 	"fmt"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
@@ -163,12 +162,12 @@ func isPartOfRange(ipRange string, subIpRange string) bool {
 }
 
 func getPodIps(k kubernetes.Interface, namespace string) []string {
-	podList, err := k.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
+	podList, err := k.CoreV1().Pods("").List(requestContext(), metav1.ListOptions{
 		Limit:          1000,
 		TimeoutSeconds: &apiTimeout,
 	})
 	if err != nil {
-		podList, err = k.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		podList, err = k.CoreV1().Pods(namespace).List(requestContext(), metav1.ListOptions{
 			Limit:          1000,
 			TimeoutSeconds: &apiTimeout,
 		})
@@ -189,12 +188,12 @@ func getPodIps(k kubernetes.Interface, namespace string) []string {
 }
 
 func getServiceIps(k kubernetes.Interface, namespace string) []string {
-	serviceList, err := k.CoreV1().Services("").List(context.TODO(), metav1.ListOptions{
+	serviceList, err := k.CoreV1().Services("").List(requestContext(), metav1.ListOptions{
 		Limit:          1000,
 		TimeoutSeconds: &apiTimeout,
 	})
 	if err != nil {
-		serviceList, err = k.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{
+		serviceList, err = k.CoreV1().Services(namespace).List(requestContext(), metav1.ListOptions{
 			Limit:          1000,
 			TimeoutSeconds: &apiTimeout,
 		})
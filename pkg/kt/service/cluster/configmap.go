@@ -1,7 +1,7 @@
 package cluster
 
 import (
-	"context"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
 	coreV1 "k8s.io/api/core/v1"
@@ -12,12 +12,12 @@ import (
 
 // GetConfigMap get configmap
 func (k *Kubernetes) GetConfigMap(name, namespace string) (*coreV1.ConfigMap, error) {
-	return k.Clientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	return k.Clientset.CoreV1().ConfigMaps(namespace).Get(requestContext(), name, metav1.GetOptions{})
 }
 
 // GetConfigMapsByLabel get deployments by label
 func (k *Kubernetes) GetConfigMapsByLabel(labels map[string]string, namespace string) (pods *coreV1.ConfigMapList, err error) {
-	return k.Clientset.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{
+	return k.Clientset.CoreV1().ConfigMaps(namespace).List(requestContext(), metav1.ListOptions{
 		LabelSelector:  labelApi.SelectorFromSet(labels).String(),
 		TimeoutSeconds: &apiTimeout,
 	})
@@ -26,15 +26,18 @@ func (k *Kubernetes) GetConfigMapsByLabel(labels map[string]string, namespace st
 // RemoveConfigMap remove ConfigMap instance
 func (k *Kubernetes) RemoveConfigMap(name, namespace string) (err error) {
 	deletePolicy := metav1.DeletePropagationBackground
-	return k.Clientset.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{
+	return k.Clientset.CoreV1().ConfigMaps(namespace).Delete(requestContext(), name, metav1.DeleteOptions{
 		PropagationPolicy: &deletePolicy,
 	})
 }
 
 func (k *Kubernetes) UpdateConfigMapHeartBeat(name, namespace string) {
+	if isDryRun() {
+		return
+	}
 	key := "configmap_" + name
 	if _, err := k.Clientset.CoreV1().ConfigMaps(namespace).
-		Patch(context.TODO(), name, types.JSONPatchType, []byte(resourceHeartbeatPatch()), metav1.PatchOptions{}); err != nil {
+		Patch(requestContext(), name, types.JSONPatchType, []byte(resourceHeartbeatPatch()), metav1.PatchOptions{}); err != nil {
 		if healthy, exists := LastHeartBeatStatus.Get(key); healthy || !exists {
 			log.Warn().Err(err).Msgf("Failed to update heart beat of config map %s", name)
 		} else {
@@ -52,16 +55,26 @@ func (k *Kubernetes) createConfigMapWithSshKey(labels map[string]string, sshcm s
 	SetupHeartBeat(sshcm, namespace, k.UpdateConfigMapHeartBeat)
 
 	labels = util.MergeMap(labels, map[string]string{util.ControlBy: util.KubernetesToolkit})
-	return k.Clientset.CoreV1().ConfigMaps(namespace).Create(context.TODO(), &coreV1.ConfigMap{
+	configMap = &coreV1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        sshcm,
-			Namespace:   namespace,
-			Labels:      labels,
-			Annotations: map[string]string{util.KtLastHeartBeat: util.GetTimestamp()},
+			Name:      sshcm,
+			Namespace: namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				util.KtLastHeartBeat: util.GetTimestamp(),
+				util.KtSession:       opt.Store.Session,
+				util.KtOwner:         util.GetOwner(),
+			},
 		},
 		Data: map[string]string{
 			util.SshAuthKey:        string(generator.PublicKey),
 			util.SshAuthPrivateKey: string(generator.PrivateKey),
 		},
-	}, metav1.CreateOptions{})
+	}
+	if isDryRun() {
+		// private key never leave local machine in dry run mode, shadow pod only mount the public key
+		delete(configMap.Data, util.SshAuthPrivateKey)
+		return configMap, printManifest(configMap)
+	}
+	return k.Clientset.CoreV1().ConfigMaps(namespace).Create(requestContext(), configMap, metav1.CreateOptions{})
 }
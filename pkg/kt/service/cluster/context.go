@@ -0,0 +1,38 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var requestCtx context.Context
+var requestCtxLock sync.RWMutex
+
+// SetRequestContext bound following api requests and waiting for pods with ctx, e.g. to let setup phase abort itself
+// when it runs out of time, set nil to remove the bound again before cleanup
+func SetRequestContext(ctx context.Context) {
+	requestCtxLock.Lock()
+	defer requestCtxLock.Unlock()
+	requestCtx = ctx
+}
+
+// requestContext context to use for api requests
+func requestContext() context.Context {
+	requestCtxLock.RLock()
+	defer requestCtxLock.RUnlock()
+	if requestCtx == nil {
+		return context.TODO()
+	}
+	return requestCtx
+}
+
+// sleepOrAbort wait for specified duration, return early with error when request context is done
+func sleepOrAbort(duration time.Duration) error {
+	select {
+	case <-time.After(duration):
+		return nil
+	case <-requestContext().Done():
+		return requestContext().Err()
+	}
+}
@@ -0,0 +1,54 @@
+package cluster
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// IsResourceInstalled check whether specified resource is served by api server, e.g. installed via crd
+func (k *Kubernetes) IsResourceInstalled(gvr schema.GroupVersionResource) bool {
+	resources, err := k.Clientset.Discovery().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil {
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == gvr.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCustomResource get custom resource by name
+func (k *Kubernetes) GetCustomResource(gvr schema.GroupVersionResource, name, namespace string) (*unstructured.Unstructured, error) {
+	return k.DynamicClient.Resource(gvr).Namespace(namespace).Get(requestContext(), name, metav1.GetOptions{})
+}
+
+// GetCustomResourcesInNamespace get all custom resources of specified kind in namespace
+func (k *Kubernetes) GetCustomResourcesInNamespace(gvr schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+	return k.DynamicClient.Resource(gvr).Namespace(namespace).List(requestContext(), metav1.ListOptions{
+		TimeoutSeconds: &apiTimeout,
+	})
+}
+
+// CreateCustomResource create custom resource
+func (k *Kubernetes) CreateCustomResource(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if isDryRun() {
+		return obj, printManifest(obj)
+	}
+	return k.DynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(requestContext(), obj, metav1.CreateOptions{})
+}
+
+// UpdateCustomResource update custom resource
+func (k *Kubernetes) UpdateCustomResource(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if isDryRun() {
+		return obj, printManifest(obj)
+	}
+	return k.DynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(requestContext(), obj, metav1.UpdateOptions{})
+}
+
+// RemoveCustomResource remove custom resource
+func (k *Kubernetes) RemoveCustomResource(gvr schema.GroupVersionResource, name, namespace string) error {
+	return k.DynamicClient.Resource(gvr).Namespace(namespace).Delete(requestContext(), name, metav1.DeleteOptions{})
+}
@@ -1,7 +1,6 @@
 package cluster
 
 import (
-	"context"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
 	appV1 "k8s.io/api/apps/v1"
@@ -13,12 +12,12 @@ import (
 
 // GetDeployment ...
 func (k *Kubernetes) GetDeployment(name string, namespace string) (*appV1.Deployment, error) {
-	return k.Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	return k.Clientset.AppsV1().Deployments(namespace).Get(requestContext(), name, metav1.GetOptions{})
 }
 
 // GetDeploymentsByLabel get deployments by label
 func (k *Kubernetes) GetDeploymentsByLabel(labels map[string]string, namespace string) (pods *appV1.DeploymentList, err error) {
-	return k.Clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{
+	return k.Clientset.AppsV1().Deployments(namespace).List(requestContext(), metav1.ListOptions{
 		LabelSelector:  labelApi.SelectorFromSet(labels).String(),
 		TimeoutSeconds: &apiTimeout,
 	})
@@ -26,20 +25,23 @@ func (k *Kubernetes) GetDeploymentsByLabel(labels map[string]string, namespace s
 
 // GetAllDeploymentInNamespace get all deployment in specified namespace
 func (k *Kubernetes) GetAllDeploymentInNamespace(namespace string) (*appV1.DeploymentList, error) {
-	return k.Clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{
+	return k.Clientset.AppsV1().Deployments(namespace).List(requestContext(), metav1.ListOptions{
 		TimeoutSeconds: &apiTimeout,
 	})
 }
 
 // UpdateDeployment ...
 func (k *Kubernetes) UpdateDeployment(deployment *appV1.Deployment) (*appV1.Deployment, error) {
-	return k.Clientset.AppsV1().Deployments(deployment.Namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
+	if isDryRun() {
+		return deployment, printManifest(deployment)
+	}
+	return k.Clientset.AppsV1().Deployments(deployment.Namespace).Update(requestContext(), deployment, metav1.UpdateOptions{})
 }
 
 // RemoveDeployment remove deployment instances
 func (k *Kubernetes) RemoveDeployment(name, namespace string) (err error) {
 	deletePolicy := metav1.DeletePropagationBackground
-	return k.Clientset.AppsV1().Deployments(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{
+	return k.Clientset.AppsV1().Deployments(namespace).Delete(requestContext(), name, metav1.DeleteOptions{
 		PropagationPolicy: &deletePolicy,
 	})
 }
@@ -86,9 +88,12 @@ func (k *Kubernetes) DecreaseDeploymentRef(name string, namespace string) (clean
 }
 
 func (k *Kubernetes) UpdateDeploymentHeartBeat(name, namespace string) {
+	if isDryRun() {
+		return
+	}
 	key := "deployment_" + name
 	if _, err := k.Clientset.AppsV1().Deployments(namespace).
-		Patch(context.TODO(), name, types.JSONPatchType, []byte(resourceHeartbeatPatch()), metav1.PatchOptions{}); err != nil {
+		Patch(requestContext(), name, types.JSONPatchType, []byte(resourceHeartbeatPatch()), metav1.PatchOptions{}); err != nil {
 		if healthy, exists := LastHeartBeatStatus.Get(key); healthy || !exists {
 			log.Warn().Err(err).Msgf("Failed to update heart beat of deployment %s", name)
 		} else {
@@ -116,6 +121,10 @@ func (k *Kubernetes) ScaleTo(name, namespace string, replicas *int32) (err error
 
 	log.Info().Msgf("Scaling deployment %s from %d to %d", deployment.Name, *deployment.Spec.Replicas, *replicas)
 	deployment.Spec.Replicas = replicas
+	if *replicas > 0 {
+		// origin replicas kept by previous exchange no longer need to be recovered
+		delete(deployment.Annotations, util.KtReplicas)
+	}
 
 	if _, err = k.UpdateDeployment(deployment); err != nil {
 		log.Error().Err(err).Msgf("Failed to scale deployment %s", deployment.Name)
@@ -0,0 +1,58 @@
+package cluster
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	appV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// printManifest print resource as an applyable yaml document to stdout, instead of submitting it to cluster
+func printManifest(obj runtime.Object) error {
+	obj = obj.DeepCopyObject()
+	switch o := obj.(type) {
+	case *coreV1.Pod:
+		o.SetGroupVersionKind(coreV1.SchemeGroupVersion.WithKind("Pod"))
+		o.Status = coreV1.PodStatus{}
+	case *coreV1.Service:
+		o.SetGroupVersionKind(coreV1.SchemeGroupVersion.WithKind("Service"))
+		o.Status = coreV1.ServiceStatus{}
+	case *coreV1.ConfigMap:
+		o.SetGroupVersionKind(coreV1.SchemeGroupVersion.WithKind("ConfigMap"))
+	case *coreV1.Endpoints:
+		o.SetGroupVersionKind(coreV1.SchemeGroupVersion.WithKind("Endpoints"))
+	case *appV1.Deployment:
+		o.SetGroupVersionKind(appV1.SchemeGroupVersion.WithKind("Deployment"))
+		o.Status = appV1.DeploymentStatus{}
+	case *appV1.StatefulSet:
+		o.SetGroupVersionKind(appV1.SchemeGroupVersion.WithKind("StatefulSet"))
+		o.Status = appV1.StatefulSetStatus{}
+	}
+	// server generated fields should not appear in manifest
+	if custom, ok := obj.(*unstructured.Unstructured); ok {
+		for _, field := range []string{"uid", "resourceVersion", "generation", "selfLink", "creationTimestamp", "managedFields"} {
+			unstructured.RemoveNestedField(custom.Object, "metadata", field)
+		}
+	} else if meta, ok := obj.(metav1.Object); ok {
+		meta.SetUID("")
+		meta.SetResourceVersion("")
+		meta.SetGeneration(0)
+		meta.SetSelfLink("")
+		meta.SetCreationTimestamp(metav1.Time{})
+		meta.SetManagedFields(nil)
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to generate manifest: %s", err)
+	}
+	fmt.Printf("---\n%s", data)
+	return nil
+}
+
+func isDryRun() bool {
+	return opt.Get().Global.DryRun
+}
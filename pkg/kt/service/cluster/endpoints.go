@@ -0,0 +1,62 @@
+package cluster
+
+import (
+	coreV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetServiceEndpoints let endpoints of service point to specified addresses,
+// only take effect on service without selector
+func (k *Kubernetes) SetServiceEndpoints(name, namespace string, subsets []coreV1.EndpointSubset) error {
+	ep, err := k.Clientset.CoreV1().Endpoints(namespace).Get(requestContext(), name, metav1.GetOptions{})
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		ep = &coreV1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		}
+	}
+	ep.Subsets = subsets
+	if isDryRun() {
+		return printManifest(ep)
+	}
+	if ep.ResourceVersion == "" {
+		_, err = k.Clientset.CoreV1().Endpoints(namespace).Create(requestContext(), ep, metav1.CreateOptions{})
+	} else {
+		_, err = k.Clientset.CoreV1().Endpoints(namespace).Update(requestContext(), ep, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// CountReadyEndpoints number of ready addresses behind service, 0 if its endpoints not exist
+func (k *Kubernetes) CountReadyEndpoints(name, namespace string) (int, error) {
+	ep, err := k.Clientset.CoreV1().Endpoints(namespace).Get(requestContext(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	count := 0
+	for _, subset := range ep.Subsets {
+		count += len(subset.Addresses)
+	}
+	return count, nil
+}
+
+// GetServiceEndpoints current endpoint subsets of service, empty if its endpoints not exist
+func (k *Kubernetes) GetServiceEndpoints(name, namespace string) ([]coreV1.EndpointSubset, error) {
+	ep, err := k.Clientset.CoreV1().Endpoints(namespace).Get(requestContext(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ep.Subsets, nil
+}
@@ -0,0 +1,34 @@
+package cluster
+
+import (
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestKubernetes_CountReadyEndpoints(t *testing.T) {
+	k := &Kubernetes{
+		Clientset: testclient.NewSimpleClientset(&coreV1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "tomcat", Namespace: "default"},
+			Subsets: []coreV1.EndpointSubset{
+				{
+					Addresses:         []coreV1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+					NotReadyAddresses: []coreV1.EndpointAddress{{IP: "10.0.0.3"}},
+				},
+				{
+					NotReadyAddresses: []coreV1.EndpointAddress{{IP: "10.0.0.4"}},
+				},
+			},
+		}, &coreV1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Name: "scaled-down", Namespace: "default"},
+		}),
+	}
+	cases := map[string]int{"tomcat": 2, "scaled-down": 0, "not-exist": 0}
+	for name, expected := range cases {
+		count, err := k.CountReadyEndpoints(name, "default")
+		require.NoError(t, err)
+		require.Equal(t, expected, count, "ready endpoints of %s", name)
+	}
+}
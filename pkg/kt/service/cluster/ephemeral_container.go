@@ -1,7 +1,6 @@
 package cluster
 
 import (
-	"context"
 	"encoding/base64"
 	"fmt"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
@@ -29,9 +28,12 @@ func (k *Kubernetes) AddEphemeralContainer(containerName string, name string,
 		return "", fmt.Errorf("found shadow pod but no configMap. Please delete the pod %s", pod.Name)
 	}
 
-	err = util.WritePrivateKey(generator.PrivateKeyPath, []byte(configMap.Data[util.SshAuthPrivateKey]))
-
-	privateKey := base64.StdEncoding.EncodeToString([]byte(configMap.Data[util.SshAuthPrivateKey]))
+	privateKey := ""
+	if !isDryRun() {
+		// private key never leave local machine in dry run mode, the generated one is kept
+		err = util.WritePrivateKey(generator.PrivateKeyPath, []byte(configMap.Data[util.SshAuthPrivateKey]))
+		privateKey = base64.StdEncoding.EncodeToString([]byte(configMap.Data[util.SshAuthPrivateKey]))
+	}
 
 	ec := coreV1.EphemeralContainer{
 		EphemeralContainerCommon: coreV1.EphemeralContainerCommon{
@@ -51,8 +53,11 @@ func (k *Kubernetes) AddEphemeralContainer(containerName string, name string,
 	}
 
 	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, ec)
+	if isDryRun() {
+		return privateKeyPath, printManifest(pod)
+	}
 
-	pod, err = k.Clientset.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(context.TODO(), pod.Name, pod, metav1.UpdateOptions{})
+	pod, err = k.Clientset.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(requestContext(), pod.Name, pod, metav1.UpdateOptions{})
 	return privateKeyPath, err
 }
 
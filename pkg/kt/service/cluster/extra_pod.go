@@ -1,9 +1,9 @@
 package cluster
 
 import (
-	"context"
 	"fmt"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
 	coreV1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,8 +22,11 @@ func (k *Kubernetes) CreateRouterPod(name string, labels, annotations map[string
 		Annotations: annotations,
 	}, opt.Get().Mesh.RouterImage, map[string]string{}, targetPorts, true}
 	pod := createPod(metaAndSpec)
+	if isDryRun() {
+		return pod, printManifest(pod)
+	}
 	if _, err := k.Clientset.CoreV1().Pods(metaAndSpec.Meta.Namespace).
-		Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		Create(requestContext(), pod, metav1.CreateOptions{}); err != nil {
 		return nil, err
 	}
 	SetupHeartBeat(metaAndSpec.Meta.Name, metaAndSpec.Meta.Namespace, k.UpdatePodHeartBeat)
@@ -42,9 +45,34 @@ func (k *Kubernetes) CreateRectifierPod(name string) (*coreV1.Pod, error) {
 	pod := createPod(metaAndSpec)
 	pod.Spec.Containers[0].Command = []string{"tail", "-f", "/dev/null"}
 	if _, err := k.Clientset.CoreV1().Pods(metaAndSpec.Meta.Namespace).
-		Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		Create(requestContext(), pod, metav1.CreateOptions{}); err != nil {
 		return nil, err
 	}
 	log.Debug().Msgf("Rectify pod %s created", name)
 	return k.WaitPodReady(name, opt.Get().Global.Namespace, opt.Get().Global.PodCreationTimeout)
 }
+
+// CreatePassthroughPod create pod with spec of original workload, but not selected by any service
+func (k *Kubernetes) CreatePassthroughPod(name string, labels, annotations map[string]string, spec coreV1.PodSpec) (*coreV1.Pod, error) {
+	annotations = util.MapPut(annotations, util.KtLastHeartBeat, util.GetTimestamp())
+	annotations = util.MapPut(annotations, util.KtSession, opt.Store.Session)
+	annotations = util.MapPut(annotations, util.KtOwner, util.GetOwner())
+	pod := &coreV1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   opt.Get().Global.Namespace,
+			Labels:      util.MergeMap(labels, map[string]string{util.ControlBy: util.KubernetesToolkit}),
+			Annotations: annotations,
+		},
+		Spec: *spec.DeepCopy(),
+	}
+	if isDryRun() {
+		return pod, printManifest(pod)
+	}
+	if _, err := k.Clientset.CoreV1().Pods(pod.Namespace).Create(requestContext(), pod, metav1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+	SetupHeartBeat(name, pod.Namespace, k.UpdatePodHeartBeat)
+	log.Info().Msgf("Passthrough pod %s created", name)
+	return k.WaitPodReady(name, pod.Namespace, opt.Get().Global.PodCreationTimeout)
+}
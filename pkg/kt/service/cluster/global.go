@@ -1,7 +1,6 @@
 package cluster
 
 import (
-	"context"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	appV1 "k8s.io/api/apps/v1"
 	coreV1 "k8s.io/api/core/v1"
@@ -32,7 +31,7 @@ type SSHkeyMeta struct {
 
 // GetAllNamespaces get all namespaces
 func (k *Kubernetes) GetAllNamespaces() (*coreV1.NamespaceList, error) {
-	return k.Clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{
+	return k.Clientset.CoreV1().Namespaces().List(requestContext(), metav1.ListOptions{
 		TimeoutSeconds: &apiTimeout,
 	})
 }
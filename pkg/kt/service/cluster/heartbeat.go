@@ -69,6 +69,9 @@ func SetupTimeDifference() error {
 
 // SetupHeartBeat setup heartbeat watcher
 func SetupHeartBeat(name, namespace string, updater func(string, string)) {
+	if isDryRun() {
+		return
+	}
 	ticker := time.NewTicker(time.Minute*util.ResourceHeartBeatIntervalMinus - util.RandomSeconds(0, 10))
 	go func() {
 		for range ticker.C {
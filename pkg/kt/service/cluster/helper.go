@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+	"strings"
 )
 
 func getKubernetesClient(kubeConfig string) (clientset *kubernetes.Clientset, err error) {
@@ -24,6 +25,8 @@ func getKubernetesClient(kubeConfig string) (clientset *kubernetes.Clientset, er
 func createService(metaAndSpec *SvcMetaAndSpec) *coreV1.Service {
 	var servicePorts []coreV1.ServicePort
 	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtLastHeartBeat, util.GetTimestamp())
+	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtSession, opt.Store.Session)
+	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtOwner, util.GetOwner())
 	metaAndSpec.Meta.Labels = util.MergeMap(metaAndSpec.Meta.Labels, map[string]string{util.ControlBy: util.KubernetesToolkit})
 
 	for srcPort, targetPort := range metaAndSpec.Ports {
@@ -56,6 +59,8 @@ func createService(metaAndSpec *SvcMetaAndSpec) *coreV1.Service {
 func createDeployment(metaAndSpec *PodMetaAndSpec) *appV1.Deployment {
 	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtRefCount, "1")
 	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtLastHeartBeat, util.GetTimestamp())
+	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtSession, opt.Store.Session)
+	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtOwner, util.GetOwner())
 
 	var originLabels = make(map[string]string, 0)
 	for k, v := range metaAndSpec.Meta.Labels {
@@ -87,6 +92,8 @@ func createDeployment(metaAndSpec *PodMetaAndSpec) *appV1.Deployment {
 func createPod(metaAndSpec *PodMetaAndSpec) *coreV1.Pod {
 	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtRefCount, "1")
 	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtLastHeartBeat, util.GetTimestamp())
+	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtSession, opt.Store.Session)
+	metaAndSpec.Meta.Annotations = util.MapPut(metaAndSpec.Meta.Annotations, util.KtOwner, util.GetOwner())
 	metaAndSpec.Meta.Labels = util.MergeMap(metaAndSpec.Meta.Labels, map[string]string{util.ControlBy: util.KubernetesToolkit})
 
 	pod := &coreV1.Pod{
@@ -112,6 +119,11 @@ func createPod(metaAndSpec *PodMetaAndSpec) *coreV1.Pod {
 		pod.Spec.NodeSelector = util.String2Map(opt.Get().Global.NodeSelector)
 	}
 
+	if opt.Get().Global.ShadowToleration != "" {
+		// already validated when preparing
+		pod.Spec.Tolerations, _ = ParseTolerations(opt.Get().Global.ShadowToleration)
+	}
+
 	return pod
 }
 
@@ -157,3 +169,51 @@ func createContainer(image string, args []string, envs map[string]string, ports
 	}
 	return container
 }
+
+// ParseInitContainer parse init container in '<image> [command...]' format, command is split by whitespace
+func ParseInitContainer(text string) (*coreV1.Container, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("image of init container should not be empty")
+	}
+	if strings.HasPrefix(fields[0], "-") || strings.ContainsAny(fields[0], "=,") {
+		return nil, fmt.Errorf("invalid image '%s' of init container, should be in '<image> [command...]' format", fields[0])
+	}
+	container := &coreV1.Container{
+		Name:  util.ShadowInitContainer,
+		Image: fields[0],
+	}
+	if len(fields) > 1 {
+		container.Command = fields[1:]
+	}
+	return container, nil
+}
+
+// ParseTolerations parse comma separated tolerations in 'key=value:effect' or 'key:effect' format
+func ParseTolerations(text string) ([]coreV1.Toleration, error) {
+	var tolerations []coreV1.Toleration
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		index := strings.LastIndex(item, ":")
+		if index <= 0 {
+			return nil, fmt.Errorf("invalid toleration '%s', should be in 'key=value:effect' or 'key:effect' format", item)
+		}
+		effect := coreV1.TaintEffect(item[index+1:])
+		if effect != coreV1.TaintEffectNoSchedule && effect != coreV1.TaintEffectPreferNoSchedule &&
+			effect != coreV1.TaintEffectNoExecute {
+			return nil, fmt.Errorf("invalid effect '%s' of toleration '%s', supportted are %s, %s, %s", effect, item,
+				coreV1.TaintEffectNoSchedule, coreV1.TaintEffectPreferNoSchedule, coreV1.TaintEffectNoExecute)
+		}
+		toleration := coreV1.Toleration{Key: item[:index], Operator: coreV1.TolerationOpExists, Effect: effect}
+		if kv := strings.SplitN(item[:index], "=", 2); len(kv) == 2 {
+			toleration.Key = kv[0]
+			toleration.Operator = coreV1.TolerationOpEqual
+			toleration.Value = kv[1]
+		}
+		if toleration.Key == "" {
+			return nil, fmt.Errorf("invalid toleration '%s', key should not be empty", item)
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"reflect"
 	"testing"
@@ -38,3 +39,42 @@ func Test_getKubernetesClient(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTolerations(t *testing.T) {
+	tolerations, err := ParseTolerations("dedicated=debug:NoSchedule, gpu:NoExecute")
+	if err != nil {
+		t.Fatalf("ParseTolerations() error = %v", err)
+	}
+	want := []coreV1.Toleration{
+		{Key: "dedicated", Operator: coreV1.TolerationOpEqual, Value: "debug", Effect: coreV1.TaintEffectNoSchedule},
+		{Key: "gpu", Operator: coreV1.TolerationOpExists, Effect: coreV1.TaintEffectNoExecute},
+	}
+	if !reflect.DeepEqual(tolerations, want) {
+		t.Errorf("ParseTolerations() got = %v, want %v", tolerations, want)
+	}
+	for _, invalid := range []string{"dedicated=debug", "dedicated=debug:Never", ":NoSchedule", "=debug:NoSchedule"} {
+		if _, err = ParseTolerations(invalid); err == nil {
+			t.Errorf("ParseTolerations(%s) should fail", invalid)
+		}
+	}
+}
+
+func TestParseInitContainer(t *testing.T) {
+	container, err := ParseInitContainer("registry/cert-fetcher:1.0  /bin/fetch --out /certs")
+	if err != nil {
+		t.Fatalf("ParseInitContainer() error = %v", err)
+	}
+	want := &coreV1.Container{Name: "kt-init", Image: "registry/cert-fetcher:1.0",
+		Command: []string{"/bin/fetch", "--out", "/certs"}}
+	if !reflect.DeepEqual(container, want) {
+		t.Errorf("ParseInitContainer() got = %v, want %v", container, want)
+	}
+	if container, _ = ParseInitContainer("busybox"); container.Image != "busybox" || container.Command != nil {
+		t.Errorf("ParseInitContainer() got = %v, want image only", container)
+	}
+	for _, invalid := range []string{"", "  ", "--image busybox", "a=b"} {
+		if _, err = ParseInitContainer(invalid); err == nil {
+			t.Errorf("ParseInitContainer(%s) should fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package cluster
+
+import (
+	authV1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PermissionRule a kind of operation on kubernetes resource, e.g. 'create pods' or 'create pods/exec'
+type PermissionRule struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+}
+
+// CanI check whether current user is allowed to perform specified operation in namespace
+func (k *Kubernetes) CanI(rule PermissionRule, namespace string) (bool, error) {
+	review, err := k.Clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(requestContext(), &authV1.SelfSubjectAccessReview{
+		Spec: authV1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authV1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        rule.Verb,
+				Group:       rule.Group,
+				Resource:    rule.Resource,
+				Subresource: rule.Subresource,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
@@ -2,7 +2,6 @@ package cluster
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
@@ -34,12 +33,12 @@ type PodMetaAndSpec struct {
 
 // GetPod ...
 func (k *Kubernetes) GetPod(name string, namespace string) (*coreV1.Pod, error) {
-	return k.Clientset.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	return k.Clientset.CoreV1().Pods(namespace).Get(requestContext(), name, metav1.GetOptions{})
 }
 
 // GetPodsByLabel get pods by label
 func (k *Kubernetes) GetPodsByLabel(labels map[string]string, namespace string) (*coreV1.PodList, error) {
-	return k.Clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+	return k.Clientset.CoreV1().Pods(namespace).List(requestContext(), metav1.ListOptions{
 		LabelSelector:  labelApi.SelectorFromSet(labels).String(),
 		TimeoutSeconds: &apiTimeout,
 	})
@@ -47,13 +46,16 @@ func (k *Kubernetes) GetPodsByLabel(labels map[string]string, namespace string)
 
 // UpdatePod ...
 func (k *Kubernetes) UpdatePod(pod *coreV1.Pod) (*coreV1.Pod, error) {
-	return k.Clientset.CoreV1().Pods(pod.Namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
+	if isDryRun() {
+		return pod, printManifest(pod)
+	}
+	return k.Clientset.CoreV1().Pods(pod.Namespace).Update(requestContext(), pod, metav1.UpdateOptions{})
 }
 
 // RemovePod remove pod instances
 func (k *Kubernetes) RemovePod(name, namespace string) (err error) {
 	deletePolicy := metav1.DeletePropagationBackground
-	return k.Clientset.CoreV1().Pods(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{
+	return k.Clientset.CoreV1().Pods(namespace).Delete(requestContext(), name, metav1.DeleteOptions{
 		PropagationPolicy: &deletePolicy,
 	})
 }
@@ -73,9 +75,12 @@ func (k *Kubernetes) WaitPodTerminate(name, namespace string) (*coreV1.Pod, erro
 }
 
 func (k *Kubernetes) UpdatePodHeartBeat(name, namespace string) {
+	if isDryRun() {
+		return
+	}
 	key := "pod_" + name
 	if _, err := k.Clientset.CoreV1().Pods(namespace).
-		Patch(context.TODO(), name, types.JSONPatchType, []byte(resourceHeartbeatPatch()), metav1.PatchOptions{}); err != nil {
+		Patch(requestContext(), name, types.JSONPatchType, []byte(resourceHeartbeatPatch()), metav1.PatchOptions{}); err != nil {
 		if healthy, exists := LastHeartBeatStatus.Get(key); healthy || !exists {
 			log.Warn().Err(err).Msgf("Failed to update heart beat of pod %s", name)
 		} else {
@@ -104,6 +109,10 @@ func (k *Kubernetes) WatchPod(name, namespace string, fAdd, fDel, fMod func(*cor
 }
 
 func (k *Kubernetes) ExecInPod(containerName, podName, namespace string, cmd ...string) (string, string, error) {
+	if isDryRun() {
+		log.Info().Msgf("Dry run, skip executing '%s' in pod %s", strings.Join(cmd, " "), podName)
+		return "", "", nil
+	}
 	req := k.Clientset.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(podName).
@@ -203,7 +212,9 @@ func (k *Kubernetes) waitPodsReady(labels map[string]string, namespace string, t
 		return runningPods, nil
 	}
 	log.Info().Msgf("Waiting for shadow pod ...")
-	time.Sleep(1 * time.Second)
+	if err = sleepOrAbort(1 * time.Second); err != nil {
+		return nil, err
+	}
 	return k.waitPodsReady(labels, namespace, timeoutSec, times+1)
 }
 
@@ -222,7 +233,9 @@ func (k *Kubernetes) waitPodReady(name, namespace string, timeoutSec int, times
 		} else {
 			log.Info().Msgf("Waiting for pod %s ...", name)
 		}
-		time.Sleep(interval * time.Second)
+		if err = sleepOrAbort(interval * time.Second); err != nil {
+			return nil, err
+		}
 		return k.waitPodReady(name, namespace, timeoutSec, times+1)
 	}
 	if !strings.HasPrefix(name, util.RectifierPodPrefix) {
@@ -237,7 +250,9 @@ func (k *Kubernetes) waitPodTerminate(name, namespace string, times int) (*coreV
 		return nil, fmt.Errorf("pod '%s' still terminating, please try again later", name)
 	}
 	log.Info().Msgf("Pod '%s' not finished yet, waiting ...", name)
-	time.Sleep(interval * time.Second)
+	if err := sleepOrAbort(interval * time.Second); err != nil {
+		return nil, err
+	}
 	routerPod, err := k.GetPod(name, namespace)
 	if err != nil {
 		// Note: will return a Not Found error when pod finally terminated
@@ -0,0 +1,84 @@
+package cluster
+
+import (
+	"fmt"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// checkResourceQuota verify whether creating specified pod would exceed resource quota of its namespace
+func (k *Kubernetes) checkResourceQuota(pod *coreV1.Pod) error {
+	quotas, err := k.Clientset.CoreV1().ResourceQuotas(pod.Namespace).List(requestContext(), metav1.ListOptions{})
+	if err != nil {
+		log.Debug().Err(err).Msgf("Failed to list resource quota of namespace %s, skip quota checking", pod.Namespace)
+		return nil
+	}
+	required := getPodRequiredResources(pod)
+	for _, quota := range quotas.Items {
+		hardLimits := quota.Status.Hard
+		if len(hardLimits) == 0 {
+			hardLimits = quota.Spec.Hard
+		}
+		for name, hard := range hardLimits {
+			quantity, exists := required[name]
+			if !exists {
+				if isComputeResource(name) {
+					log.Warn().Msgf("Resource quota '%s' limits '%s', but shadow pod does not specify it, "+
+						"use '--podQuota' option if shadow pod failed to create", quota.Name, name)
+				}
+				continue
+			}
+			used := quota.Status.Used[name]
+			total := used.DeepCopy()
+			total.Add(quantity)
+			if total.Cmp(hard) > 0 {
+				return fmt.Errorf("creating pod %s would exceed '%s' of resource quota '%s' (used %s, require %s, hard %s)",
+					pod.Name, name, quota.Name, used.String(), quantity.String(), hard.String())
+			}
+		}
+	}
+	return nil
+}
+
+func getPodRequiredResources(pod *coreV1.Pod) coreV1.ResourceList {
+	required := coreV1.ResourceList{
+		coreV1.ResourcePods: resource.MustParse("1"),
+	}
+	dimensions := map[coreV1.ResourceName][]coreV1.ResourceName{
+		coreV1.ResourceCPU:    {coreV1.ResourceCPU, coreV1.ResourceRequestsCPU},
+		coreV1.ResourceMemory: {coreV1.ResourceMemory, coreV1.ResourceRequestsMemory},
+	}
+	for _, container := range pod.Spec.Containers {
+		for resourceName, quotaNames := range dimensions {
+			if quantity, exists := container.Resources.Requests[resourceName]; exists {
+				for _, quotaName := range quotaNames {
+					addQuantity(required, quotaName, quantity)
+				}
+			}
+		}
+		if quantity, exists := container.Resources.Limits[coreV1.ResourceCPU]; exists {
+			addQuantity(required, coreV1.ResourceLimitsCPU, quantity)
+		}
+		if quantity, exists := container.Resources.Limits[coreV1.ResourceMemory]; exists {
+			addQuantity(required, coreV1.ResourceLimitsMemory, quantity)
+		}
+	}
+	return required
+}
+
+func addQuantity(resources coreV1.ResourceList, name coreV1.ResourceName, quantity resource.Quantity) {
+	total := resources[name]
+	total.Add(quantity)
+	resources[name] = total
+}
+
+func isComputeResource(name coreV1.ResourceName) bool {
+	switch name {
+	case coreV1.ResourceCPU, coreV1.ResourceMemory, coreV1.ResourceRequestsCPU, coreV1.ResourceRequestsMemory,
+		coreV1.ResourceLimitsCPU, coreV1.ResourceLimitsMemory:
+		return true
+	}
+	return false
+}
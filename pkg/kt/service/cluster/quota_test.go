@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestKubernetes_checkResourceQuota(t *testing.T) {
+	quota := &coreV1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "default"},
+		Status: coreV1.ResourceQuotaStatus{
+			Hard: coreV1.ResourceList{
+				coreV1.ResourcePods:           resource.MustParse("10"),
+				coreV1.ResourceRequestsCPU:    resource.MustParse("2"),
+				coreV1.ResourceRequestsMemory: resource.MustParse("1Gi"),
+			},
+			Used: coreV1.ResourceList{
+				coreV1.ResourcePods:           resource.MustParse("9"),
+				coreV1.ResourceRequestsCPU:    resource.MustParse("1500m"),
+				coreV1.ResourceRequestsMemory: resource.MustParse("512Mi"),
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		cpu     string
+		memory  string
+		wantErr bool
+	}{
+		{name: "shouldPassWhenQuotaIsEnough", cpu: "500m", memory: "512Mi", wantErr: false},
+		{name: "shouldFailWhenCpuExceeded", cpu: "600m", memory: "256Mi", wantErr: true},
+		{name: "shouldFailWhenMemoryExceeded", cpu: "100m", memory: "1Gi", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &Kubernetes{
+				Clientset: testclient.NewSimpleClientset(quota),
+			}
+			pod := &coreV1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "shadow", Namespace: "default"},
+				Spec: coreV1.PodSpec{
+					Containers: []coreV1.Container{{
+						Resources: coreV1.ResourceRequirements{
+							Requests: coreV1.ResourceList{
+								coreV1.ResourceCPU:    resource.MustParse(tt.cpu),
+								coreV1.ResourceMemory: resource.MustParse(tt.memory),
+							},
+						},
+					}},
+				},
+			}
+			err := k.checkResourceQuota(pod)
+			require.Equal(t, tt.wantErr, err != nil, "unexpected quota check result: %v", err)
+		})
+	}
+}
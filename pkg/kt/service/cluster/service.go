@@ -1,7 +1,6 @@
 package cluster
 
 import (
-	"context"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
 	coreV1 "k8s.io/api/core/v1"
@@ -20,7 +19,7 @@ type SvcMetaAndSpec struct {
 
 // GetService get service
 func (k *Kubernetes) GetService(name, namespace string) (*coreV1.Service, error) {
-	return k.Clientset.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	return k.Clientset.CoreV1().Services(namespace).Get(requestContext(), name, metav1.GetOptions{})
 }
 
 // GetServicesBySelector get services by selector
@@ -40,7 +39,7 @@ func (k *Kubernetes) GetServicesBySelector(matchLabels map[string]string, namesp
 
 // GetServicesByLabel get services by label
 func (k *Kubernetes) GetServicesByLabel(labels map[string]string, namespace string) (svcs *coreV1.ServiceList, err error) {
-	return k.Clientset.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{
+	return k.Clientset.CoreV1().Services(namespace).List(requestContext(), metav1.ListOptions{
 		LabelSelector:  labelApi.SelectorFromSet(labels).String(),
 		TimeoutSeconds: &apiTimeout,
 	})
@@ -48,7 +47,7 @@ func (k *Kubernetes) GetServicesByLabel(labels map[string]string, namespace stri
 
 // GetAllServiceInNamespace get all services in specified namespace
 func (k *Kubernetes) GetAllServiceInNamespace(namespace string) (*coreV1.ServiceList, error) {
-	return k.Clientset.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{
+	return k.Clientset.CoreV1().Services(namespace).List(requestContext(), metav1.ListOptions{
 		TimeoutSeconds: &apiTimeout,
 	})
 }
@@ -56,27 +55,37 @@ func (k *Kubernetes) GetAllServiceInNamespace(namespace string) (*coreV1.Service
 // CreateService create kubernetes service
 func (k *Kubernetes) CreateService(metaAndSpec *SvcMetaAndSpec) (*coreV1.Service, error) {
 	SetupHeartBeat(metaAndSpec.Meta.Name, metaAndSpec.Meta.Namespace, k.UpdateServiceHeartBeat)
+	svc := createService(metaAndSpec)
+	if isDryRun() {
+		return svc, printManifest(svc)
+	}
 	return k.Clientset.CoreV1().Services(metaAndSpec.Meta.Namespace).
-		Create(context.TODO(), createService(metaAndSpec), metav1.CreateOptions{})
+		Create(requestContext(), svc, metav1.CreateOptions{})
 }
 
 // UpdateService ...
 func (k *Kubernetes) UpdateService(svc *coreV1.Service) (*coreV1.Service, error) {
-	return k.Clientset.CoreV1().Services(svc.Namespace).Update(context.TODO(), svc, metav1.UpdateOptions{})
+	if isDryRun() {
+		return svc, printManifest(svc)
+	}
+	return k.Clientset.CoreV1().Services(svc.Namespace).Update(requestContext(), svc, metav1.UpdateOptions{})
 }
 
 // RemoveService remove service
 func (k *Kubernetes) RemoveService(name, namespace string) (err error) {
 	deletePolicy := metav1.DeletePropagationBackground
-	return k.Clientset.CoreV1().Services(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{
+	return k.Clientset.CoreV1().Services(namespace).Delete(requestContext(), name, metav1.DeleteOptions{
 		PropagationPolicy: &deletePolicy,
 	})
 }
 
 func (k *Kubernetes) UpdateServiceHeartBeat(name, namespace string) {
+	if isDryRun() {
+		return
+	}
 	key := "service_" + name
 	if _, err := k.Clientset.CoreV1().Services(namespace).
-		Patch(context.TODO(), name, types.JSONPatchType, []byte(resourceHeartbeatPatch()), metav1.PatchOptions{}); err != nil {
+		Patch(requestContext(), name, types.JSONPatchType, []byte(resourceHeartbeatPatch()), metav1.PatchOptions{}); err != nil {
 		if healthy, exists := LastHeartBeatStatus.Get(key); healthy || !exists {
 			log.Warn().Err(err).Msgf("Failed to update heart beat of service %s", name)
 		} else {
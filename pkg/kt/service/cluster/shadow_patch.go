@@ -0,0 +1,162 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+// ParseShadowPodPatch read patch from file if it exists, otherwise treat it as inline yaml, return patch in json
+func ParseShadowPodPatch(text string) ([]byte, error) {
+	source := "inline patch"
+	content := []byte(text)
+	if data, err := os.ReadFile(text); err == nil {
+		source, content = fmt.Sprintf("patch file %s", text), data
+	}
+	var patch map[string]interface{}
+	if err := yaml.Unmarshal(content, &patch); err != nil {
+		return nil, fmt.Errorf("invalid yaml in %s of shadow pod: %s", source, err)
+	}
+	if len(patch) == 0 {
+		return nil, fmt.Errorf("%s of shadow pod should be a non-empty yaml object, e.g. 'spec: {priorityClassName: high}'", source)
+	}
+	return json.Marshal(patch)
+}
+
+// patchShadowPod apply '--shadowPodPatch' to metadata and spec of shadow pod as strategic merge patch
+func patchShadowPod(meta *metav1.ObjectMeta, spec *coreV1.PodSpec) error {
+	if opt.Get().Global.ShadowPodPatch == "" {
+		return nil
+	}
+	patch, err := ParseShadowPodPatch(opt.Get().Global.ShadowPodPatch)
+	if err != nil {
+		return err
+	}
+	// compare with origin pod after same json round trip, in which empty fields are dropped
+	origin, err := applyPodPatch(&coreV1.Pod{ObjectMeta: *meta, Spec: *spec}, []byte("{}"))
+	if err != nil {
+		return err
+	}
+	patched, err := applyPodPatch(origin, patch)
+	if err != nil {
+		return err
+	}
+	if err = checkPatchedPod(origin, patched); err != nil {
+		return err
+	}
+	*meta, *spec = patched.ObjectMeta, patched.Spec
+	return nil
+}
+
+func applyPodPatch(origin *coreV1.Pod, patch []byte) (*coreV1.Pod, error) {
+	originJson, err := json.Marshal(origin)
+	if err != nil {
+		return nil, err
+	}
+	patchedJson, err := strategicpatch.StrategicMergePatch(originJson, patch, coreV1.Pod{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patch to shadow pod: %s", err)
+	}
+	patched := &coreV1.Pod{}
+	if err = json.Unmarshal(patchedJson, patched); err != nil {
+		return nil, fmt.Errorf("failed to apply patch to shadow pod: %s", err)
+	}
+	return patched, nil
+}
+
+// checkPatchedPod make sure patch only adds to shadow pod, without changing what tunnel relies on
+func checkPatchedPod(origin, patched *coreV1.Pod) error {
+	if patched.Name != origin.Name || patched.Namespace != origin.Namespace {
+		return fmt.Errorf("patch should not change name or namespace of shadow pod")
+	}
+	for k, v := range origin.Labels {
+		if patched.Labels[k] != v {
+			return fmt.Errorf("patch should not change label '%s' of shadow pod", k)
+		}
+	}
+	for k, v := range origin.Annotations {
+		if patched.Annotations[k] != v {
+			return fmt.Errorf("patch should not change annotation '%s' of shadow pod", k)
+		}
+	}
+	var container *coreV1.Container
+	for i, c := range patched.Spec.Containers {
+		if c.Name == util.DefaultContainer {
+			container = &patched.Spec.Containers[i]
+		}
+	}
+	if container == nil {
+		return fmt.Errorf("patch should not remove container '%s' of shadow pod", util.DefaultContainer)
+	}
+	kt := origin.Spec.Containers[0]
+	if container.Image != kt.Image || !reflect.DeepEqual(container.Command, kt.Command) ||
+		!reflect.DeepEqual(container.Args, kt.Args) || !reflect.DeepEqual(container.SecurityContext, kt.SecurityContext) {
+		return fmt.Errorf("patch should not change image, command, args or security context of container '%s'",
+			util.DefaultContainer)
+	}
+	for _, env := range kt.Env {
+		if !containsEnv(container.Env, env) {
+			return fmt.Errorf("patch should not change env '%s' of container '%s'", env.Name, util.DefaultContainer)
+		}
+	}
+	for _, port := range kt.Ports {
+		if !containsPort(container.Ports, port) {
+			return fmt.Errorf("patch should not change port '%s' of container '%s'", port.Name, util.DefaultContainer)
+		}
+	}
+	for _, mount := range kt.VolumeMounts {
+		if !containsVolumeMount(container.VolumeMounts, mount) {
+			return fmt.Errorf("patch should not change volume mount '%s' of container '%s'", mount.Name, util.DefaultContainer)
+		}
+	}
+	for _, volume := range origin.Spec.Volumes {
+		if !containsVolume(patched.Spec.Volumes, volume) {
+			return fmt.Errorf("patch should not change volume '%s' of shadow pod", volume.Name)
+		}
+	}
+	return nil
+}
+
+func containsEnv(envs []coreV1.EnvVar, env coreV1.EnvVar) bool {
+	for _, e := range envs {
+		if reflect.DeepEqual(e, env) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPort(ports []coreV1.ContainerPort, port coreV1.ContainerPort) bool {
+	for _, p := range ports {
+		if reflect.DeepEqual(p, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVolumeMount(mounts []coreV1.VolumeMount, mount coreV1.VolumeMount) bool {
+	for _, m := range mounts {
+		if reflect.DeepEqual(m, mount) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVolume(volumes []coreV1.Volume, volume coreV1.Volume) bool {
+	for _, v := range volumes {
+		if reflect.DeepEqual(v, volume) {
+			return true
+		}
+	}
+	return false
+}
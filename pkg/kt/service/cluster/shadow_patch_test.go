@@ -0,0 +1,46 @@
+package cluster
+
+import (
+	"testing"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_patchShadowPod(t *testing.T) {
+	defer func(patch string) { opt.Get().Global.ShadowPodPatch = patch }(opt.Get().Global.ShadowPodPatch)
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr bool
+	}{
+		{name: "shouldAddFields", patch: "spec: {priorityClassName: high, dnsPolicy: Default}\nmetadata: {labels: {team: a}}"},
+		{name: "shouldPatchResources", patch: "spec: {containers: [{name: standalone, resources: {limits: {cpu: '1'}}}]}"},
+		{name: "shouldFailWhenImageChanged", patch: "spec: {containers: [{name: standalone, image: busybox}]}", wantErr: true},
+		{name: "shouldFailWhenContainerRemoved", patch: "spec: {containers: [{name: standalone, $patch: delete}]}", wantErr: true},
+		{name: "shouldFailWhenEnvChanged", patch: "spec: {containers: [{name: standalone, env: [{name: A, value: c}]}]}", wantErr: true},
+		{name: "shouldFailWhenLabelChanged", patch: "metadata: {labels: {app: b}}", wantErr: true},
+		{name: "shouldFailWhenVolumeRemoved", patch: "spec: {volumes: [{name: ssh-public-key, $patch: delete}]}", wantErr: true},
+		{name: "shouldFailWhenYamlInvalid", patch: "spec: [", wantErr: true},
+		{name: "shouldFailWhenNotObject", patch: "high", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := createPod(&PodMetaAndSpec{
+				Meta:  &ResourceMeta{Name: "shadow", Namespace: "default", Labels: map[string]string{"app": "a"}},
+				Image: "kt-shadow",
+				Envs:  map[string]string{"A": "b"},
+				Ports: map[string]int{"http": 80},
+			})
+			(&Kubernetes{}).appendSshVolume(&pod.Spec, "kt-ssh")
+			opt.Get().Global.ShadowPodPatch = tt.patch
+			err := patchShadowPod(&pod.ObjectMeta, &pod.Spec)
+			require.Equal(t, tt.wantErr, err != nil, "unexpected patch result: %v", err)
+			if tt.name == "shouldAddFields" {
+				require.Equal(t, "high", pod.Spec.PriorityClassName)
+				require.Equal(t, "a", pod.Labels["team"])
+				require.Equal(t, "kt-shadow", pod.Spec.Containers[0].Image)
+			}
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package cluster
 
 import (
-	"context"
 	"fmt"
+	"github.com/alibaba/kt-connect/pkg/common"
 	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
@@ -10,6 +10,7 @@ import (
 	coreV1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
 	"strings"
 )
 
@@ -56,7 +57,7 @@ func (k *Kubernetes) GetOrCreateShadow(name string, labels, annotations, envs ma
 		}
 	}
 
-	if opt.Store.Component == util.ComponentConnect && opt.Get().Connect.ShareShadow {
+	if IsShadowShared() {
 		pod, generator, err2 := k.tryGetExistingShadows(&resourceMeta, &sshKeyMeta)
 		if err2 != nil {
 			return "", "", "", err2
@@ -75,9 +76,19 @@ func (k *Kubernetes) GetOrCreateShadow(name string, labels, annotations, envs ma
 	return k.createShadow(&podMeta, &sshKeyMeta)
 }
 
+// IsShadowShared whether shadow pod of current process could be shared with other processes
+func IsShadowShared() bool {
+	return (opt.Store.Component == util.ComponentConnect && opt.Get().Connect.ShareShadow) ||
+		(opt.Store.Component == util.ComponentExchange && opt.Get().Exchange.SharedShadow != "")
+}
+
 func (k *Kubernetes) createShadow(metaAndSpec *PodMetaAndSpec, sshKeyMeta *SSHkeyMeta) (
 	podIP string, podName string, privateKeyPath string, err error) {
 
+	if err = k.checkResourceQuota(createPod(metaAndSpec)); err != nil {
+		return
+	}
+
 	generator, err := util.Generate(sshKeyMeta.PrivateKeyPath)
 	if err != nil {
 		return
@@ -97,6 +108,26 @@ func (k *Kubernetes) createShadow(metaAndSpec *PodMetaAndSpec, sshKeyMeta *SSHke
 }
 
 func (k *Kubernetes) createAndGetPod(metaAndSpec *PodMetaAndSpec, sshcm string) (*coreV1.Pod, error) {
+	if isDryRun() {
+		if opt.Get().Global.UseShadowDeployment {
+			deployment := createDeployment(metaAndSpec)
+			k.appendSshVolume(&deployment.Spec.Template.Spec, sshcm)
+			setGracePeriod(&deployment.Spec.Template.Spec)
+			setInitContainer(&deployment.Spec.Template.Spec)
+			if err := patchShadowPod(&deployment.Spec.Template.ObjectMeta, &deployment.Spec.Template.Spec); err != nil {
+				return nil, err
+			}
+			return &coreV1.Pod{ObjectMeta: deployment.ObjectMeta}, printManifest(deployment)
+		}
+		pod := createPod(metaAndSpec)
+		k.appendSshVolume(&pod.Spec, sshcm)
+		setGracePeriod(&pod.Spec)
+		setInitContainer(&pod.Spec)
+		if err := patchShadowPod(&pod.ObjectMeta, &pod.Spec); err != nil {
+			return nil, err
+		}
+		return pod, printManifest(pod)
+	}
 	if opt.Get().Global.UseShadowDeployment {
 		if err := k.createShadowDeployment(metaAndSpec, sshcm); err != nil {
 			return nil, err
@@ -131,8 +162,13 @@ func filterRunningPods(pods []coreV1.Pod) []coreV1.Pod {
 func (k *Kubernetes) createShadowDeployment(metaAndSpec *PodMetaAndSpec, sshcm string) error {
 	deployment := createDeployment(metaAndSpec)
 	k.appendSshVolume(&deployment.Spec.Template.Spec, sshcm)
+	setGracePeriod(&deployment.Spec.Template.Spec)
+	setInitContainer(&deployment.Spec.Template.Spec)
+	if err := patchShadowPod(&deployment.Spec.Template.ObjectMeta, &deployment.Spec.Template.Spec); err != nil {
+		return err
+	}
 	if _, err := k.Clientset.AppsV1().Deployments(metaAndSpec.Meta.Namespace).
-		Create(context.TODO(), deployment, metav1.CreateOptions{}); err != nil {
+		Create(requestContext(), deployment, metav1.CreateOptions{}); err != nil {
 		return err
 	}
 	SetupHeartBeat(metaAndSpec.Meta.Name, metaAndSpec.Meta.Namespace, k.UpdateDeploymentHeartBeat)
@@ -143,14 +179,41 @@ func (k *Kubernetes) createShadowDeployment(metaAndSpec *PodMetaAndSpec, sshcm s
 func (k *Kubernetes) createShadowPod(metaAndSpec *PodMetaAndSpec, sshcm string) error {
 	pod := createPod(metaAndSpec)
 	k.appendSshVolume(&pod.Spec, sshcm)
+	setGracePeriod(&pod.Spec)
+	setInitContainer(&pod.Spec)
+	if err := patchShadowPod(&pod.ObjectMeta, &pod.Spec); err != nil {
+		return err
+	}
 	if _, err := k.Clientset.CoreV1().Pods(metaAndSpec.Meta.Namespace).
-		Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		Create(requestContext(), pod, metav1.CreateOptions{}); err != nil {
 		return err
 	}
 	SetupHeartBeat(metaAndSpec.Meta.Name, metaAndSpec.Meta.Namespace, k.UpdatePodHeartBeat)
 	return nil
 }
 
+// setGracePeriod let shadow pod drain existing connections within specified seconds before terminated
+func setGracePeriod(podSpec *coreV1.PodSpec) {
+	gracePeriod := opt.Get().Global.ShadowGracePeriod
+	if gracePeriod <= 0 {
+		return
+	}
+	seconds := int64(gracePeriod)
+	podSpec.TerminationGracePeriodSeconds = &seconds
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env,
+		coreV1.EnvVar{Name: common.EnvVarGracePeriod, Value: strconv.Itoa(gracePeriod)})
+}
+
+// setInitContainer run specified init container before shadow container starts
+func setInitContainer(podSpec *coreV1.PodSpec) {
+	if opt.Get().Global.ShadowInitContainer == "" {
+		return
+	}
+	// already validated when preparing
+	container, _ := ParseInitContainer(opt.Get().Global.ShadowInitContainer)
+	podSpec.InitContainers = append(podSpec.InitContainers, *container)
+}
+
 func (k *Kubernetes) appendSshVolume(podSpec *coreV1.PodSpec, sshcm string) {
 	podSpec.Containers[0].VolumeMounts = []coreV1.VolumeMount{
 		{
@@ -223,6 +286,8 @@ func (k *Kubernetes) tryGetExistingShadows(resourceMeta *ResourceMeta, sshKeyMet
 		if err = k.IncreasePodRef(resourceMeta.Name, resourceMeta.Namespace); err != nil {
 			return nil, nil, err
 		}
+		// keep shared pod alive even if the process created it exits first
+		SetupHeartBeat(resourceMeta.Name, resourceMeta.Namespace, k.UpdatePodHeartBeat)
 	}
 	return pod, generator, nil
 }
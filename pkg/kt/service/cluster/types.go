@@ -5,6 +5,9 @@ import (
 	appV1 "k8s.io/api/apps/v1"
 	coreV1 "k8s.io/api/core/v1"
 	extV1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -17,6 +20,7 @@ type KubernetesInterface interface {
 	GetOrCreateShadow(name string, labels, annotations, envs map[string]string, portsToExpose string, portNameDict map[int]string) (string, string, string, error)
 	CreateRouterPod(name string, labels, annotations map[string]string, ports map[int]int) (*coreV1.Pod, error)
 	CreateRectifierPod(name string) (*coreV1.Pod, error)
+	CreatePassthroughPod(name string, labels, annotations map[string]string, spec coreV1.PodSpec) (*coreV1.Pod, error)
 	UpdatePodHeartBeat(name, namespace string)
 	WaitPodReady(name, namespace string, timeoutSec int) (*coreV1.Pod, error)
 	WaitPodTerminate(name, namespace string) (*coreV1.Pod, error)
@@ -36,6 +40,13 @@ type KubernetesInterface interface {
 	DecreaseDeploymentRef(name, namespace string) (bool, error)
 	ScaleTo(deployment, namespace string, replicas *int32) (err error)
 
+	GetStatefulSet(name string, namespace string) (*appV1.StatefulSet, error)
+	GetAllStatefulSetInNamespace(namespace string) (*appV1.StatefulSetList, error)
+	UpdateStatefulSet(statefulSet *appV1.StatefulSet) (*appV1.StatefulSet, error)
+	ScaleStatefulSetTo(name, namespace string, replicas *int32) (err error)
+	GetDaemonSet(name string, namespace string) (*appV1.DaemonSet, error)
+	GetAllDaemonSetInNamespace(namespace string) (*appV1.DaemonSetList, error)
+
 	GetService(name, namespace string) (*coreV1.Service, error)
 	GetServicesBySelector(matchLabels map[string]string, namespace string) ([]coreV1.Service, error)
 	GetAllServiceInNamespace(namespace string) (*coreV1.ServiceList, error)
@@ -45,6 +56,9 @@ type KubernetesInterface interface {
 	RemoveService(name, namespace string) (err error)
 	UpdateServiceHeartBeat(name, namespace string)
 	WatchService(name, namespace string, fAdd, fDel, fMod func(*coreV1.Service))
+	SetServiceEndpoints(name, namespace string, subsets []coreV1.EndpointSubset) error
+	CountReadyEndpoints(name, namespace string) (int, error)
+	GetServiceEndpoints(name, namespace string) ([]coreV1.EndpointSubset, error)
 
 	GetConfigMap(name, namespace string) (*coreV1.ConfigMap, error)
 	GetConfigMapsByLabel(labels map[string]string, namespace string) (*coreV1.ConfigMapList, error)
@@ -53,14 +67,23 @@ type KubernetesInterface interface {
 
 	GetAllIngressInNamespace(namespace string) (*extV1.IngressList, error)
 
+	IsResourceInstalled(gvr schema.GroupVersionResource) bool
+	GetCustomResource(gvr schema.GroupVersionResource, name, namespace string) (*unstructured.Unstructured, error)
+	GetCustomResourcesInNamespace(gvr schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error)
+	CreateCustomResource(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	UpdateCustomResource(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	RemoveCustomResource(gvr schema.GroupVersionResource, name, namespace string) error
+
 	GetKtResources(namespace string) ([]coreV1.Pod, []coreV1.ConfigMap, []appV1.Deployment, []coreV1.Service, error)
 	GetAllNamespaces() (*coreV1.NamespaceList, error)
+	CanI(rule PermissionRule, namespace string) (bool, error)
 	ClusterCidr(namespace string) (cidr []string, excludeCidr []string)
 }
 
 // Kubernetes implements KubernetesInterface
 type Kubernetes struct {
-	Clientset kubernetes.Interface
+	Clientset     kubernetes.Interface
+	DynamicClient dynamic.Interface
 }
 
 // Cli the singleton type
@@ -70,7 +93,8 @@ var instance *Kubernetes
 func Ins() KubernetesInterface {
 	if instance == nil {
 		instance = &Kubernetes{
-			Clientset: opt.Store.Clientset,
+			Clientset:     opt.Store.Clientset,
+			DynamicClient: opt.Store.DynamicClient,
 		}
 	}
 	return instance
@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+	appV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetStatefulSet ...
+func (k *Kubernetes) GetStatefulSet(name string, namespace string) (*appV1.StatefulSet, error) {
+	return k.Clientset.AppsV1().StatefulSets(namespace).Get(requestContext(), name, metav1.GetOptions{})
+}
+
+// GetAllStatefulSetInNamespace get all statefulset in specified namespace
+func (k *Kubernetes) GetAllStatefulSetInNamespace(namespace string) (*appV1.StatefulSetList, error) {
+	return k.Clientset.AppsV1().StatefulSets(namespace).List(requestContext(), metav1.ListOptions{
+		TimeoutSeconds: &apiTimeout,
+	})
+}
+
+// UpdateStatefulSet ...
+func (k *Kubernetes) UpdateStatefulSet(statefulSet *appV1.StatefulSet) (*appV1.StatefulSet, error) {
+	if isDryRun() {
+		return statefulSet, printManifest(statefulSet)
+	}
+	return k.Clientset.AppsV1().StatefulSets(statefulSet.Namespace).Update(requestContext(), statefulSet, metav1.UpdateOptions{})
+}
+
+// ScaleStatefulSetTo scale statefulset to specified replicas
+func (k *Kubernetes) ScaleStatefulSetTo(name, namespace string, replicas *int32) (err error) {
+	statefulSet, err := k.GetStatefulSet(name, namespace)
+	if err != nil {
+		return
+	}
+
+	if statefulSet.Spec.Replicas != nil && *statefulSet.Spec.Replicas == *replicas {
+		log.Warn().Msgf("Statefulset %s already having %d replicas, not need to scale", name, *replicas)
+		return nil
+	}
+
+	current := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		current = *statefulSet.Spec.Replicas
+	}
+	log.Info().Msgf("Scaling statefulset %s from %d to %d", statefulSet.Name, current, *replicas)
+	statefulSet.Spec.Replicas = replicas
+	if *replicas > 0 {
+		// origin replicas kept by previous exchange no longer need to be recovered
+		delete(statefulSet.Annotations, util.KtReplicas)
+	}
+
+	if _, err = k.UpdateStatefulSet(statefulSet); err != nil {
+		log.Error().Err(err).Msgf("Failed to scale statefulset %s", statefulSet.Name)
+		return
+	}
+	log.Info().Msgf("Statefulset %s successfully scaled to %d replicas", name, *replicas)
+	return
+}
+
+// GetDaemonSet ...
+func (k *Kubernetes) GetDaemonSet(name string, namespace string) (*appV1.DaemonSet, error) {
+	return k.Clientset.AppsV1().DaemonSets(namespace).Get(requestContext(), name, metav1.GetOptions{})
+}
+
+// GetAllDaemonSetInNamespace get all daemonset in specified namespace
+func (k *Kubernetes) GetAllDaemonSetInNamespace(namespace string) (*appV1.DaemonSetList, error) {
+	return k.Clientset.AppsV1().DaemonSets(namespace).List(requestContext(), metav1.ListOptions{
+		TimeoutSeconds: &apiTimeout,
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"os/signal"
 	"strconv"
 	"strings"
@@ -86,6 +87,19 @@ func RestoreNameServer() {
 	}
 }
 
+// FlushDnsCache clear dns cache of system resolver, so that records resolved during connect are not used any more
+func FlushDnsCache() {
+	if _, _, err := util.RunAndWait(exec.Command("dscacheutil", "-flushcache")); err != nil {
+		log.Warn().Err(err).Msgf("Failed to flush dns cache via dscacheutil")
+		return
+	}
+	if _, _, err := util.RunAndWait(exec.Command("killall", "-HUP", "mDNSResponder")); err != nil {
+		log.Warn().Err(err).Msgf("Failed to reload mDNSResponder")
+		return
+	}
+	log.Info().Msgf("Dns cache flushed")
+}
+
 func createResolverFile(postfix, domain, dnsIp, dnsPort string) {
 	resolverFile := fmt.Sprintf("%s/%s%s", resolverDir, ktResolverPrefix, postfix)
 	if _, err := os.Stat(resolverFile); err == nil {
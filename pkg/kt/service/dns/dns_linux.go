@@ -58,6 +58,22 @@ func RestoreNameServer() {
 	restoreIptables()
 }
 
+// FlushDnsCache clear dns cache of systemd-resolved if it is used, so that records resolved during connect are not used any more
+func FlushDnsCache() {
+	for _, cmd := range [][]string{{"resolvectl", "flush-caches"}, {"systemd-resolve", "--flush-caches"}} {
+		if _, err := exec.LookPath(cmd[0]); err != nil {
+			continue
+		}
+		if _, stderr, err := util.RunAndWait(exec.Command(cmd[0], cmd[1:]...)); err != nil {
+			log.Debug().Err(err).Msgf("Failed to flush dns cache via %s: %s", cmd[0], stderr)
+			continue
+		}
+		log.Info().Msgf("Dns cache flushed")
+		return
+	}
+	log.Debug().Msgf("No systemd-resolved available, skip flushing dns cache")
+}
+
 func setupResolvConf(dnsServer string) error {
 	f, err := os.Open(util.ResolvConf)
 	if err != nil {
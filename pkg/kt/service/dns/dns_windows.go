@@ -50,6 +50,15 @@ func RestoreNameServer() {
 	// Windows dns config is set on device, so explicit removal is unnecessary
 }
 
+// FlushDnsCache clear dns cache of system resolver, so that records resolved during connect are not used any more
+func FlushDnsCache() {
+	if _, _, err := util.RunAndWait(exec.Command("ipconfig", "/flushdns")); err != nil {
+		log.Warn().Err(err).Msgf("Failed to flush dns cache via ipconfig")
+		return
+	}
+	log.Info().Msgf("Dns cache flushed")
+}
+
 // GetLocalDomains ...
 func GetLocalDomains() string {
 	return ""
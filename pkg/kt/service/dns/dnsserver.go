@@ -22,14 +22,14 @@ type DnsServer struct {
 
 func SetupLocalDns(remoteDnsPort, localDnsPort int, dnsOrder []string) error {
 	var res = make(chan error)
-	go func() {
+	util.GoWithRecover("local dns", func() {
 		upstreamDnsAddresses := getDnsAddresses(dnsOrder, GetNameServer(), remoteDnsPort)
 		// domain-name -> ip
 		extraDomains := getIngressDomains()
 		log.Info().Msgf("Setup local DNS with upstream %v", upstreamDnsAddresses)
 		HandleExtraDomainMapping(extraDomains, localDnsPort)
 		res <-common.SetupDnsServer(&DnsServer{upstreamDnsAddresses, extraDomains}, localDnsPort, "udp")
-	}()
+	})
 	select {
 	case err := <-res:
 		return err
@@ -120,6 +120,12 @@ func query(req *dns.Msg, dnsAddresses []string, extraDomains map[string]string)
 	domain := req.Question[0].Name
 	qtype := req.Question[0].Qtype
 
+	// pod ip changes frequently, never cached
+	if ip, exists := lookupPodDomain(domain); exists {
+		log.Debug().Msgf("Found domain %s (%d) of pod %s", domain, qtype, ip)
+		return toPodRecords(domain, qtype, ip)
+	}
+
 	answer := common.ReadCache(domain, qtype, int64(opt.Get().Connect.DnsCacheTtl))
 	if answer != nil {
 		log.Debug().Msgf("Found domain %s (%d) in cache", domain, qtype)
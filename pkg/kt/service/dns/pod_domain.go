@@ -0,0 +1,48 @@
+package dns
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+)
+
+// podDomains domain to ip of pods, grouped by namespace
+var podDomains = map[string]map[string]string{}
+var podDomainsLock sync.RWMutex
+
+// SetPodDomains replace domains of pods in specified namespace, domain should be fully qualified with trailing dot
+func SetPodDomains(namespace string, domains map[string]string) {
+	podDomainsLock.Lock()
+	defer podDomainsLock.Unlock()
+	podDomains[namespace] = domains
+}
+
+// lookupPodDomain find ip of pod by domain
+func lookupPodDomain(domain string) (string, bool) {
+	podDomainsLock.RLock()
+	defer podDomainsLock.RUnlock()
+	domain = strings.ToLower(domain)
+	for _, domains := range podDomains {
+		if ip, exists := domains[domain]; exists {
+			return ip, true
+		}
+	}
+	return "", false
+}
+
+// toPodRecords answer of pod domain, empty if query type does not match ip version of pod
+func toPodRecords(domain string, qtype uint16, ip string) []dns.RR {
+	isIpv4 := net.ParseIP(ip).To4() != nil
+	if qtype == dns.TypeA && isIpv4 {
+		return []dns.RR{toARecord(domain, ip)}
+	}
+	if qtype == dns.TypeAAAA && !isIpv4 {
+		return []dns.RR{&dns.AAAA{
+			Hdr:  dns.RR_Header{Name: domain, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 5},
+			AAAA: net.ParseIP(ip),
+		}}
+	}
+	return []dns.RR{}
+}
@@ -0,0 +1,33 @@
+package sshchannel
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// activeConnections count of connections received via reverse tunnels and not closed yet
+var activeConnections int64
+
+// ActiveConnectionCount connections currently being handled by reverse tunnels of current process
+func ActiveConnectionCount() int64 {
+	return atomic.LoadInt64(&activeConnections)
+}
+
+// countedConn connection counted as active until closed
+type countedConn struct {
+	net.Conn
+	closeOnce sync.Once
+}
+
+func newCountedConn(conn net.Conn) net.Conn {
+	atomic.AddInt64(&activeConnections, 1)
+	return &countedConn{Conn: conn}
+}
+
+func (c *countedConn) Close() error {
+	c.closeOnce.Do(func() {
+		atomic.AddInt64(&activeConnections, -1)
+	})
+	return c.Conn.Close()
+}
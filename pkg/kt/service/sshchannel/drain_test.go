@@ -0,0 +1,19 @@
+package sshchannel
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveConnectionCount(t *testing.T) {
+	origin := ActiveConnectionCount()
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	conn := newCountedConn(c1)
+	require.Equal(t, origin+1, ActiveConnectionCount())
+	_ = conn.Close()
+	_ = conn.Close()
+	require.Equal(t, origin, ActiveConnectionCount())
+}
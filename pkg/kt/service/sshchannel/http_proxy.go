@@ -0,0 +1,103 @@
+package sshchannel
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// hopHeaders headers only meaningful to proxy connection, should not be forwarded
+var hopHeaders = []string{"Proxy-Connection", "Proxy-Authorization", "Proxy-Authenticate", "Connection", "Keep-Alive",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade"}
+
+// httpProxy http proxy tunneling CONNECT requests and forwarding plain http requests via specified dialer
+type httpProxy struct {
+	dial      dialFunc
+	transport *http.Transport
+}
+
+func newHttpProxy(dial dialFunc) *httpProxy {
+	return &httpProxy{
+		dial:      dial,
+		transport: &http.Transport{DialContext: dial, Proxy: nil},
+	}
+}
+
+func (p *httpProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.handleConnect(w, r)
+		return
+	}
+	if r.URL.Host == "" {
+		http.Error(w, "only proxy requests are accepted", http.StatusBadRequest)
+		return
+	}
+	req := r.Clone(r.Context())
+	req.RequestURI = ""
+	for _, h := range hopHeaders {
+		req.Header.Del(h)
+	}
+	resp, err := p.transport.RoundTrip(req)
+	if err != nil {
+		log.Debug().Err(err).Msgf("Failed to proxy request to %s", r.URL.Host)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for _, h := range hopHeaders {
+		resp.Header.Del(h)
+	}
+	for key, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// handleConnect establish tunnel to requested host, then copy data in both directions
+func (p *httpProxy) handleConnect(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if !strings.Contains(host, ":") {
+		host = net.JoinHostPort(host, "443")
+	}
+	remote, err := p.dial(r.Context(), "tcp", host)
+	if err != nil {
+		log.Debug().Err(err).Msgf("Failed to connect %s via proxy", host)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		_ = remote.Close()
+		http.Error(w, "connection hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	client, _, err := hijacker.Hijack()
+	if err != nil {
+		_ = remote.Close()
+		return
+	}
+	if _, err = client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		_ = client.Close()
+		_ = remote.Close()
+		return
+	}
+	// tunnel is opaque, copy data as is in both directions
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = copyBuffer(remote, client)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = copyBuffer(client, remote)
+		done <- struct{}{}
+	}()
+	<-done
+	_ = client.Close()
+	_ = remote.Close()
+}
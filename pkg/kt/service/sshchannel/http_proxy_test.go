@@ -0,0 +1,50 @@
+package sshchannel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_httpProxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "hello %s", r.URL.Path)
+	}))
+	defer target.Close()
+	proxy := httptest.NewServer(newHttpProxy((&net.Dialer{}).DialContext))
+	defer proxy.Close()
+
+	// plain http request via proxy
+	proxyUrl, _ := url.Parse(proxy.URL)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyUrl)}}
+	resp, err := client.Get(target.URL + "/plain")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, "hello /plain", string(body))
+
+	// tunnel via CONNECT method
+	conn, err := net.Dial("tcp", proxyUrl.Host)
+	require.NoError(t, err)
+	defer conn.Close()
+	targetHost := target.Listener.Addr().String()
+	_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", targetHost, targetHost)
+	require.NoError(t, err)
+	reader := bufio.NewReader(conn)
+	resp, err = http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = fmt.Fprintf(conn, "GET /tunnel HTTP/1.1\r\nHost: %s\r\n\r\n", targetHost)
+	require.NoError(t, err)
+	resp, err = http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	require.Equal(t, "hello /tunnel", string(body))
+}
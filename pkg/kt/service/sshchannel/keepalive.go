@@ -0,0 +1,81 @@
+package sshchannel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/ssh"
+)
+
+// KeepAliveConfig how keepalive requests are sent via ssh connection to detect half-open tunnel
+type KeepAliveConfig struct {
+	// Interval time between two keepalive requests, also the timeout of each request, zero to disable keepalive
+	Interval time.Duration
+	// MaxRetries consecutive failed requests before ssh connection regarded as broken
+	MaxRetries int
+}
+
+// DefaultKeepAlive broken ssh connection is detected in less than 10 seconds
+var DefaultKeepAlive = KeepAliveConfig{Interval: 3 * time.Second, MaxRetries: 3}
+
+// keepAliveRequest request type answered by openssh server, and replied with failure by other servers
+const keepAliveRequest = "keepalive@openssh.com"
+
+// keepAlive send keepalive request via ssh client periodically, close the client and call onDead
+// when too many requests failed in a row, until done channel closed
+func keepAlive(client *ssh.Client, config KeepAliveConfig, done <-chan struct{}, onDead func()) {
+	send := func() error {
+		// any reply (even a failure reply) proves the connection is alive
+		_, _, err := client.SendRequest(keepAliveRequest, true, nil)
+		return err
+	}
+	if watchKeepAlive(send, config, done) {
+		log.Warn().Msgf("Ssh connection to %s has no response, closing it", client.RemoteAddr())
+		_ = client.Close()
+		if onDead != nil {
+			onDead()
+		}
+	}
+}
+
+// watchKeepAlive send keepalive request every interval, return true if MaxRetries requests failed or timeout
+// in a row, or false when done channel closed
+func watchKeepAlive(send func() error, config KeepAliveConfig, done <-chan struct{}) bool {
+	if config.Interval <= 0 {
+		return false
+	}
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		select {
+		case <-done:
+			return false
+		case <-ticker.C:
+		}
+		if err := sendWithTimeout(send, config.Interval); err != nil {
+			failures++
+			log.Debug().Err(err).Msgf("Keepalive request failed (%d/%d)", failures, config.MaxRetries)
+			if failures >= config.MaxRetries {
+				return true
+			}
+		} else {
+			failures = 0
+		}
+	}
+}
+
+// sendWithTimeout request via half-open connection may block forever, give up waiting after timeout
+func sendWithTimeout(send func() error, timeout time.Duration) error {
+	res := make(chan error, 1)
+	go func() {
+		res <- send()
+	}()
+	select {
+	case err := <-res:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("no reply in %s", timeout)
+	}
+}
@@ -0,0 +1,41 @@
+package sshchannel
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_watchKeepAlive(t *testing.T) {
+	config := KeepAliveConfig{Interval: 10 * time.Millisecond, MaxRetries: 3}
+	cases := []struct {
+		name    string
+		results []error
+		dead    bool
+	}{
+		{"always fail", []error{errors.New("eof")}, true},
+		{"always succeed", []error{nil}, false},
+		{"recover before max retries", []error{errors.New("eof"), errors.New("eof"), nil}, false},
+		{"no reply", []error{errors.New("block")}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var count int32
+			send := func() error {
+				err := c.results[int(atomic.AddInt32(&count, 1)-1)%len(c.results)]
+				if err != nil && err.Error() == "block" {
+					time.Sleep(time.Second)
+				}
+				return err
+			}
+			done := make(chan struct{})
+			timer := time.AfterFunc(200*time.Millisecond, func() { close(done) })
+			defer timer.Stop()
+			require.Equal(t, c.dead, watchKeepAlive(send, config, done))
+		})
+	}
+	require.False(t, watchKeepAlive(func() error { return errors.New("eof") }, KeepAliveConfig{}, nil))
+}
@@ -0,0 +1,102 @@
+package sshchannel
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// localLimiter limit rate of connections forwarded to local
+type localLimiter struct {
+	limiter       *rate.Limiter
+	shed          bool
+	fallbackHosts []string
+	admitted      int64
+	shedded       int64
+	fellBack      int64
+	next          uint32
+}
+
+// localLimit rate limiter of current process, nil means no limit
+var localLimit *localLimiter
+
+// SetupLocalRateLimit limit connections forwarded to local per second,
+// excess connections are queued, or shed when shed is true
+func SetupLocalRateLimit(connPerSecond int, shed bool) {
+	localLimit = &localLimiter{
+		limiter: rate.NewLimiter(rate.Limit(connPerSecond), connPerSecond),
+		shed:    shed,
+	}
+	go localLimit.reportStats(30 * time.Second)
+}
+
+// SetOverloadFallback let shed connections forward to specified hosts instead of being closed
+func SetOverloadFallback(hosts []string) {
+	if localLimit != nil {
+		localLimit.fallbackHosts = hosts
+	}
+}
+
+// handleLimitedRequest forward connection to local if rate limit allowed
+func handleLimitedRequest(client net.Conn, remoteEndpoint, localEndpoint string, dial dialFunc) {
+	if localLimit.shed {
+		if !localLimit.limiter.Allow() {
+			atomic.AddInt64(&localLimit.shedded, 1)
+			localLimit.fallback(client, remoteEndpoint, dial)
+			return
+		}
+	} else if err := localLimit.limiter.Wait(context.Background()); err != nil {
+		log.Warn().Err(err).Msgf("Failed to wait for local rate limit")
+		_ = client.Close()
+		return
+	}
+	atomic.AddInt64(&localLimit.admitted, 1)
+
+	local, err := net.Dial("tcp", localEndpoint)
+	if err != nil {
+		_ = client.Close()
+		log.Error().Err(err).Msgf("Local service error")
+		return
+	}
+	handleClient(terminateTls(client), local)
+}
+
+// fallback forward shed connection to one of fallback hosts in round-robin, or close it if unavailable
+func (l *localLimiter) fallback(client net.Conn, remoteEndpoint string, dial dialFunc) {
+	if len(l.fallbackHosts) == 0 {
+		_ = client.Close()
+		return
+	}
+	host := l.fallbackHosts[int(atomic.AddUint32(&l.next, 1))%len(l.fallbackHosts)]
+	atomic.AddInt64(&l.fellBack, 1)
+	if err := forwardToOrigin(client, host, remoteEndpoint, dial); err != nil {
+		atomic.AddInt64(&l.fellBack, -1)
+		log.Debug().Err(err).Msgf("Failed to fallback shed connection to %s", host)
+	}
+}
+
+func (l *localLimiter) reportStats(interval time.Duration) {
+	var lastAdmitted, lastShedded, lastFellBack int64
+	for range time.Tick(interval) {
+		admitted := atomic.LoadInt64(&l.admitted)
+		shedded := atomic.LoadInt64(&l.shedded)
+		fellBack := atomic.LoadInt64(&l.fellBack)
+		if admitted == lastAdmitted && shedded == lastShedded {
+			continue
+		}
+		rps := float64(admitted-lastAdmitted+shedded-lastShedded) / interval.Seconds()
+		if shedded > lastShedded {
+			log.Info().Msgf("Local rate limit: %.1f connections/s observed, %d shed (%d fallen back to origin) in last %v, %d shed in total",
+				rps, shedded-lastShedded, fellBack-lastFellBack, interval, shedded)
+		} else {
+			log.Debug().Msgf("Local rate limit: %.1f connections/s observed in last %v", rps, interval)
+		}
+		lastAdmitted, lastShedded, lastFellBack = admitted, shedded, fellBack
+	}
+}
@@ -0,0 +1,51 @@
+package sshchannel
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+// passthroughHosts original pod ips for remote ports not forwarded to local, key is port number
+var passthroughHosts = map[string][]string{}
+var passthroughNext uint32
+
+// SetPassthrough let connections received on specified remote port go back to original pods instead of local,
+// must be called before reverse tunnel established
+func SetPassthrough(port int, hosts []string) {
+	passthroughHosts[strconv.Itoa(port)] = hosts
+}
+
+// getPassthroughHosts get original pod ips if remote endpoint is a passthrough port
+func getPassthroughHosts(remoteEndpoint string) ([]string, bool) {
+	hosts, exists := passthroughHosts[endpointPort(remoteEndpoint)]
+	return hosts, exists
+}
+
+// handlePassthroughRequest forward connection to one of original pods in round-robin, via ssh connection of shadow pod
+func handlePassthroughRequest(client net.Conn, remoteEndpoint string, hosts []string, dial dialFunc) {
+	host := hosts[int(atomic.AddUint32(&passthroughNext, 1))%len(hosts)]
+	if err := forwardToOrigin(client, host, remoteEndpoint, dial); err != nil {
+		log.Warn().Err(err).Msgf("Failed to pass connection through to %s", host)
+	}
+}
+
+// forwardToOrigin connect specified host from inside shadow pod, on same port as remote endpoint
+func forwardToOrigin(client net.Conn, host, remoteEndpoint string, dial dialFunc) error {
+	origin, err := dial(context.Background(), "tcp", net.JoinHostPort(host, endpointPort(remoteEndpoint)))
+	if err != nil {
+		_ = client.Close()
+		return err
+	}
+	handleClient(client, origin)
+	return nil
+}
+
+// endpointPort port part of endpoint address
+func endpointPort(endpoint string) string {
+	return endpoint[strings.LastIndex(endpoint, ":")+1:]
+}
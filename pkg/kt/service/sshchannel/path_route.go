@@ -0,0 +1,128 @@
+package sshchannel
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+)
+
+// pathRouter dispatch http requests to local ports according to path prefix
+type pathRouter struct {
+	prefixes    []string
+	ports       map[string]int
+	defaultPort int
+	localAddr   string
+	proxy       *httputil.ReverseProxy
+}
+
+// pathRouters path routers of current process, key is remote port
+var pathRouters = map[string]*pathRouter{}
+
+// SetupPathRoutes dispatch http requests received on remote port to local ports by longest matched path prefix,
+// requests not matching any prefix go to default local port, must be called before reverse tunnel established
+func SetupPathRoutes(remotePort int, localAddr string, defaultPort int, routes map[string]int) {
+	r := &pathRouter{
+		ports:       routes,
+		defaultPort: defaultPort,
+		localAddr:   localAddr,
+	}
+	for prefix := range routes {
+		r.prefixes = append(r.prefixes, prefix)
+	}
+	sort.Slice(r.prefixes, func(i, j int) bool {
+		return len(r.prefixes[i]) > len(r.prefixes[j])
+	})
+	r.proxy = &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "http"
+			req.URL.Host = util.LocalEndpoint(r.localAddr, r.target(req.URL.Path))
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			log.Warn().Err(err).Msgf("Failed to forward request %s to local", req.URL.Path)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	pathRouters[strconv.Itoa(remotePort)] = r
+}
+
+// getPathRouter get path router if remote endpoint has path routes
+func getPathRouter(remoteEndpoint string) (*pathRouter, bool) {
+	r, exists := pathRouters[endpointPort(remoteEndpoint)]
+	return r, exists
+}
+
+// target local port of request path
+func (r *pathRouter) target(path string) int {
+	for _, prefix := range r.prefixes {
+		if matchPathPrefix(path, prefix) {
+			return r.ports[prefix]
+		}
+	}
+	return r.defaultPort
+}
+
+// serve handle all requests of the connection, which may be kept alive for multiple requests
+func (r *pathRouter) serve(client net.Conn) {
+	if err := http.Serve(newSingleConnListener(client), r.proxy); err != nil && err != io.EOF {
+		log.Debug().Err(err).Msgf("Failed to serve path routed connection")
+	}
+}
+
+// matchPathPrefix path equals to prefix or is under it, e.g. '/api' matches '/api' and '/api/users' but not '/apis'
+func matchPathPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// singleConnListener listener only accepts the specified connection, and closes after the connection closed
+type singleConnListener struct {
+	conn net.Conn
+	once sync.Once
+	done chan struct{}
+}
+
+func newSingleConnListener(conn net.Conn) *singleConnListener {
+	return &singleConnListener{conn: conn, done: make(chan struct{})}
+}
+
+func (l *singleConnListener) Accept() (net.Conn, error) {
+	var conn net.Conn
+	l.once.Do(func() {
+		conn = &notifyOnCloseConn{Conn: l.conn, done: l.done}
+	})
+	if conn != nil {
+		return conn, nil
+	}
+	<-l.done
+	return nil, io.EOF
+}
+
+func (l *singleConnListener) Close() error {
+	return nil
+}
+
+func (l *singleConnListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+// notifyOnCloseConn connection notifies listener when closed
+type notifyOnCloseConn struct {
+	net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *notifyOnCloseConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	return c.Conn.Close()
+}
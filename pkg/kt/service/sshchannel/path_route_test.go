@@ -0,0 +1,27 @@
+package sshchannel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pathRouterTarget(t *testing.T) {
+	SetupPathRoutes(80, "", 8080, map[string]int{"/api": 8081, "/api/v2/": 8082, "/web": 8083})
+	r, ok := getPathRouter("127.0.0.1:80")
+	require.True(t, ok)
+	targets := map[string]int{
+		"/api":           8081,
+		"/api/users":     8081,
+		"/api/v2":        8082,
+		"/api/v2/users":  8082,
+		"/apis":          8080,
+		"/web/index.htm": 8083,
+		"/":              8080,
+	}
+	for path, port := range targets {
+		require.Equal(t, port, r.target(path), path)
+	}
+	_, ok = getPathRouter("127.0.0.1:81")
+	require.False(t, ok)
+}
@@ -0,0 +1,51 @@
+package sshchannel
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+// pausedHosts original pods all connections go to while paused, empty when not paused
+var pausedHosts []string
+var pausedNext uint32
+var pauseLock sync.RWMutex
+
+// Pause let all connections received by reverse tunnels go to specified hosts instead of local,
+// return false if already paused
+func Pause(hosts []string) bool {
+	pauseLock.Lock()
+	defer pauseLock.Unlock()
+	if len(pausedHosts) > 0 {
+		return false
+	}
+	pausedHosts = hosts
+	return true
+}
+
+// Resume let connections go to local again, return false if not paused
+func Resume() bool {
+	pauseLock.Lock()
+	defer pauseLock.Unlock()
+	if len(pausedHosts) == 0 {
+		return false
+	}
+	pausedHosts = nil
+	return true
+}
+
+func getPausedHosts() []string {
+	pauseLock.RLock()
+	defer pauseLock.RUnlock()
+	return pausedHosts
+}
+
+// handlePausedRequest forward connection to one of original pods in round-robin
+func handlePausedRequest(client net.Conn, remoteEndpoint string, hosts []string, dial dialFunc) {
+	host := hosts[int(atomic.AddUint32(&pausedNext, 1))%len(hosts)]
+	if err := forwardToOrigin(client, host, remoteEndpoint, dial); err != nil {
+		log.Debug().Err(err).Msgf("Failed to forward connection to %s while paused", host)
+	}
+}
@@ -0,0 +1,48 @@
+package sshchannel
+
+import (
+	"math/rand"
+	"net"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+// trafficRamp share of connections forwarded to local, the rest go back to original pods
+type trafficRamp struct {
+	percent int32
+	hosts   []string
+	next    uint32
+}
+
+// ramp traffic share of current process, nil means all connections go to local
+var ramp *trafficRamp
+
+// SetupTrafficRamp only forward specified percent of connections to local, others go to specified hosts,
+// must be called before reverse tunnel established
+func SetupTrafficRamp(percent int, hosts []string) {
+	ramp = &trafficRamp{
+		percent: int32(percent),
+		hosts:   hosts,
+	}
+}
+
+// SetTrafficPercent change percent of connections forwarded to local
+func SetTrafficPercent(percent int) {
+	if ramp != nil {
+		atomic.StoreInt32(&ramp.percent, int32(percent))
+	}
+}
+
+// isRampDiverted whether current connection should go to original pods according to traffic share
+func isRampDiverted() bool {
+	return ramp != nil && rand.Int31n(100) >= atomic.LoadInt32(&ramp.percent)
+}
+
+// handleRampDivertedRequest forward connection to one of original pods in round-robin
+func handleRampDivertedRequest(client net.Conn, remoteEndpoint string, dial dialFunc) {
+	host := ramp.hosts[int(atomic.AddUint32(&ramp.next, 1))%len(ramp.hosts)]
+	if err := forwardToOrigin(client, host, remoteEndpoint, dial); err != nil {
+		log.Debug().Err(err).Msgf("Failed to divert connection to %s", host)
+	}
+}
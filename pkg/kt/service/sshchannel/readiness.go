@@ -0,0 +1,169 @@
+package sshchannel
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+	"github.com/rs/zerolog/log"
+)
+
+// http2Preface client connection preface of http2, followed by an empty SETTINGS frame
+var http2Preface = []byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n\x00\x00\x00\x04\x00\x00\x00\x00\x00")
+
+// http2FrameSettings frame type of SETTINGS frame
+const http2FrameSettings = 0x4
+
+// localReadiness gate of connections forwarded to local, according to health of local app
+type localReadiness struct {
+	protocol      string
+	address       string
+	url           string
+	anyStatus     bool
+	client        *http.Client
+	timeout       time.Duration
+	ready         int32
+	fallbackHosts []string
+	next          uint32
+}
+
+// localReady readiness gates of current process, key is remote port, port without gate only requires local port listening
+var localReady = map[string]*localReadiness{}
+
+// SetupLocalReadiness check local port in the way of its protocol, only forward connections received on
+// specified remote ports to local when it passes, must be called before reverse tunnel established
+func SetupLocalReadiness(remotePorts []int, localAddr string, localPort int, protocol, path string, timeout time.Duration) {
+	r := newLocalReadiness(localAddr, localPort, protocol, path, timeout)
+	for _, p := range remotePorts {
+		localReady[strconv.Itoa(p)] = r
+	}
+	log.Info().Msgf("Requests to port %v will be forwarded to local only when %s is healthy", remotePorts, r.target())
+	go r.poll(time.Second)
+}
+
+// CheckLocalReady check local port once in the way of its protocol, return reason if it is not ready
+func CheckLocalReady(localAddr string, localPort int, protocol, path string, timeout time.Duration) (bool, string) {
+	return newLocalReadiness(localAddr, localPort, protocol, path, timeout).check()
+}
+
+func newLocalReadiness(localAddr string, localPort int, protocol, path string, timeout time.Duration) *localReadiness {
+	r := &localReadiness{
+		protocol: protocol,
+		address:  util.LocalEndpoint(localAddr, localPort),
+		client:   &http.Client{Timeout: timeout},
+		timeout:  timeout,
+	}
+	if r.protocol != util.ExposeProtocolGrpc && r.protocol != util.ExposeProtocolTcp {
+		// http port without ready path only requires local app responding
+		r.anyStatus = path == ""
+		if path == "" {
+			path = "/"
+		}
+		r.url = fmt.Sprintf("http://%s%s", r.address, path)
+	}
+	return r
+}
+
+// SetReadinessFallback let connections go to specified hosts while local app is not ready, instead of being closed
+func SetReadinessFallback(hosts []string) {
+	for _, r := range localReady {
+		r.fallbackHosts = hosts
+	}
+}
+
+// isLocalReady check whether local app accepts connections of remote endpoint, always true when readiness gate not set
+func isLocalReady(remoteEndpoint string) bool {
+	r, exists := localReady[endpointPort(remoteEndpoint)]
+	return !exists || atomic.LoadInt32(&r.ready) == 1
+}
+
+// handleUnreadyRequest forward connection to one of fallback hosts in round-robin, or close it if unavailable
+func handleUnreadyRequest(client net.Conn, remoteEndpoint string, dial dialFunc) {
+	r := localReady[endpointPort(remoteEndpoint)]
+	if len(r.fallbackHosts) == 0 {
+		_ = client.Close()
+		return
+	}
+	host := r.fallbackHosts[int(atomic.AddUint32(&r.next, 1))%len(r.fallbackHosts)]
+	if err := forwardToOrigin(client, host, remoteEndpoint, dial); err != nil {
+		log.Debug().Err(err).Msgf("Failed to fallback connection to %s", host)
+	}
+}
+
+func (r *localReadiness) target() string {
+	if r.url != "" {
+		return r.url
+	}
+	return fmt.Sprintf("%s://%s", r.protocol, r.address)
+}
+
+func (r *localReadiness) poll(interval time.Duration) {
+	for {
+		healthy, reason := r.check()
+		if healthy && atomic.SwapInt32(&r.ready, 1) == 0 {
+			log.Info().Msgf("Local app is ready, forwarding requests of %s to local", r.target())
+		} else if !healthy && atomic.SwapInt32(&r.ready, 0) == 1 {
+			if len(r.fallbackHosts) > 0 {
+				log.Warn().Msgf("Local app is not ready (%s), requests go to original pods", reason)
+			} else {
+				log.Warn().Msgf("Local app is not ready (%s), requests are rejected", reason)
+			}
+		}
+		time.Sleep(interval)
+	}
+}
+
+func (r *localReadiness) check() (bool, string) {
+	switch r.protocol {
+	case util.ExposeProtocolTcp:
+		return r.checkTcp()
+	case util.ExposeProtocolGrpc:
+		return r.checkGrpc()
+	}
+	resp, err := r.client.Get(r.url)
+	if err != nil {
+		return false, err.Error()
+	}
+	_ = resp.Body.Close()
+	if !r.anyStatus && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		return false, fmt.Sprintf("%s returned status %d", r.url, resp.StatusCode)
+	}
+	return true, ""
+}
+
+// checkTcp local port is listened
+func (r *localReadiness) checkTcp() (bool, string) {
+	conn, err := net.DialTimeout("tcp", r.address, r.timeout)
+	if err != nil {
+		return false, err.Error()
+	}
+	_ = conn.Close()
+	return true, ""
+}
+
+// checkGrpc local port accepts http2 connection without tls, which grpc server requires
+func (r *localReadiness) checkGrpc() (bool, string) {
+	conn, err := net.DialTimeout("tcp", r.address, r.timeout)
+	if err != nil {
+		return false, err.Error()
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(r.timeout))
+	if _, err = conn.Write(http2Preface); err != nil {
+		return false, err.Error()
+	}
+	// server must send SETTINGS frame first, frame header is 9 bytes with type at 4th byte
+	header := make([]byte, 9)
+	if _, err = io.ReadFull(conn, header); err != nil {
+		return false, fmt.Sprintf("no http2 handshake from %s: %s", r.address, err)
+	}
+	if header[3] != http2FrameSettings {
+		return false, fmt.Sprintf("%s is not serving grpc", r.address)
+	}
+	return true, ""
+}
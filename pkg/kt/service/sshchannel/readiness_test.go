@@ -0,0 +1,62 @@
+package sshchannel
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_localReadinessCheck(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/healthz", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	r := &localReadiness{url: server.URL + "/healthz", client: &http.Client{Timeout: time.Second}}
+	healthy, reason := r.check()
+	require.False(t, healthy)
+	require.Contains(t, reason, "503")
+
+	status = http.StatusOK
+	healthy, reason = r.check()
+	require.True(t, healthy)
+	require.Empty(t, reason)
+
+	server.Close()
+	healthy, _ = r.check()
+	require.False(t, healthy)
+}
+
+func Test_localReadinessCheckTcpAndGrpc(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err2 := listener.Accept()
+			if err2 != nil {
+				return
+			}
+			// plain tcp server never answers http2 handshake
+			go func() {
+				time.Sleep(2 * time.Second)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	r := &localReadiness{protocol: "tcp", address: listener.Addr().String(), timeout: time.Second}
+	healthy, _ := r.check()
+	require.True(t, healthy)
+
+	r.protocol = "grpc"
+	healthy, reason := r.check()
+	require.False(t, healthy)
+	require.Contains(t, reason, "no http2 handshake")
+}
@@ -0,0 +1,91 @@
+package sshchannel
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ReconnectPolicy how a broken ssh connection is re-established
+type ReconnectPolicy struct {
+	// MaxAttempts consecutive failed attempts before giving up, zero to never give up
+	MaxAttempts int
+	// InitialBackoff wait time before the first attempt, doubled for each following attempt
+	InitialBackoff time.Duration
+	// MaxBackoff upper bound of wait time, also a connection lasted longer than it is regarded as established
+	MaxBackoff time.Duration
+}
+
+// DefaultReconnect a transient disconnect is recovered in seconds, give up after about 3 minutes
+var DefaultReconnect = ReconnectPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: 30 * time.Second}
+
+// giveUpHandler called when reconnect policy exhausted
+var giveUpHandler func(cause string)
+
+// SetGiveUpHandler let caller know a connection is lost forever, e.g. to stop the whole process
+func SetGiveUpHandler(handler func(cause string)) {
+	giveUpHandler = handler
+}
+
+// reconnectTotal reconnect attempts of all connections since process started
+var reconnectTotal int64
+
+// ReconnectTotal count of reconnect attempts made by all connections of current process
+func ReconnectTotal() int64 {
+	return atomic.LoadInt64(&reconnectTotal)
+}
+
+// Reconnector track reconnect attempts of one connection
+type Reconnector struct {
+	name     string
+	policy   ReconnectPolicy
+	attempts int
+}
+
+// NewReconnector create reconnector of connection with specified name, following policy of channel instance
+func NewReconnector(name string) *Reconnector {
+	Ins()
+	return &Reconnector{name: name, policy: instance.Reconnect}
+}
+
+// Wait called after connection returned, wait for backoff and return true if it should be re-established,
+// or call give up handler and return false when policy exhausted
+func (r *Reconnector) Wait(err error, connectedAt time.Time) bool {
+	if time.Since(connectedAt) > r.policy.MaxBackoff {
+		// previous connection was established, start counting again
+		r.attempts = 0
+	}
+	r.attempts++
+	if r.policy.MaxAttempts > 0 && r.attempts > r.policy.MaxAttempts {
+		cause := fmt.Sprintf("%s lost after %d reconnect attempts", r.name, r.policy.MaxAttempts)
+		log.Error().Err(err).Msgf("Gave up reconnecting, %s", cause)
+		if giveUpHandler != nil {
+			giveUpHandler(cause)
+		}
+		return false
+	}
+	backoff := r.policy.backoff(r.attempts)
+	if r.policy.MaxAttempts > 0 {
+		log.Warn().Err(err).Msgf("%s interrupted, reconnect attempt %d/%d in %s",
+			r.name, r.attempts, r.policy.MaxAttempts, backoff)
+	} else {
+		log.Warn().Err(err).Msgf("%s interrupted, reconnect attempt %d in %s", r.name, r.attempts, backoff)
+	}
+	atomic.AddInt64(&reconnectTotal, 1)
+	time.Sleep(backoff)
+	return true
+}
+
+// backoff wait time before specified attempt, starts from 1
+func (p ReconnectPolicy) backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
+}
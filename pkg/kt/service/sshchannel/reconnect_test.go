@@ -0,0 +1,39 @@
+package sshchannel
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconnectPolicy_backoff(t *testing.T) {
+	policy := ReconnectPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: 30 * time.Second}
+	expected := []time.Duration{1, 2, 4, 8, 16, 30, 30}
+	for i, e := range expected {
+		require.Equal(t, e*time.Second, policy.backoff(i+1), "backoff of attempt %d", i+1)
+	}
+}
+
+func TestReconnector_Wait(t *testing.T) {
+	var cause string
+	SetGiveUpHandler(func(c string) {
+		cause = c
+	})
+	defer SetGiveUpHandler(nil)
+	policy := ReconnectPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Second}
+	r := &Reconnector{name: "Test tunnel", policy: policy}
+	err := errors.New("broken")
+	require.True(t, r.Wait(err, time.Now()))
+	require.True(t, r.Wait(err, time.Now()))
+	require.False(t, r.Wait(err, time.Now()))
+	require.Equal(t, "Test tunnel lost after 2 reconnect attempts", cause)
+
+	// a connection lasted long enough resets attempts
+	cause = ""
+	r = &Reconnector{name: "Test tunnel", policy: policy, attempts: 2}
+	require.True(t, r.Wait(err, time.Now().Add(-2*time.Second)))
+	require.Equal(t, 1, r.attempts)
+	require.Empty(t, cause)
+}
@@ -0,0 +1,87 @@
+package sshchannel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/alibaba/kt-connect/pkg/kt/util"
+)
+
+const headerForwardedFor = "X-Forwarded-For"
+
+// copyToLocal copy data of tunnel client to local connection, pass client ip to local if required
+func copyToLocal(local io.Writer, reader io.Reader, client net.Conn) error {
+	switch opt.Get().Global.PreserveSourceIp {
+	case util.SourceIpProxyProtocol:
+		if _, err := io.WriteString(local, proxyProtocolHeader(client.RemoteAddr(), client.LocalAddr())); err != nil {
+			return err
+		}
+	case util.SourceIpHttpHeader:
+		return copyWithForwardedFor(local, reader, clientIp(client.RemoteAddr()))
+	}
+	_, err := copyBuffer(local, reader)
+	return err
+}
+
+// proxyProtocolHeader generate PROXY protocol v1 header line
+func proxyProtocolHeader(src, dst net.Addr) string {
+	srcAddr, ok1 := src.(*net.TCPAddr)
+	dstAddr, ok2 := dst.(*net.TCPAddr)
+	if !ok1 || !ok2 || srcAddr.IP == nil || dstAddr.IP == nil {
+		return "PROXY UNKNOWN\r\n"
+	}
+	family := "TCP4"
+	if srcAddr.IP.To4() == nil || dstAddr.IP.To4() == nil {
+		family = "TCP6"
+	}
+	return fmt.Sprintf("PROXY %s %s %s %d %d\r\n", family, srcAddr.IP.String(), dstAddr.IP.String(),
+		srcAddr.Port, dstAddr.Port)
+}
+
+// clientIp extract ip part of remote address
+func clientIp(addr net.Addr) string {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok && tcpAddr.IP != nil {
+		return tcpAddr.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// copyWithForwardedFor read http requests from src, append client ip to X-Forwarded-For header and write to dst
+func copyWithForwardedFor(dst io.Writer, src io.Reader, ip string) error {
+	reader := bufio.NewReader(src)
+	for {
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if _, exists := req.Header["User-Agent"]; !exists {
+			// prevent request writer from adding default user agent
+			req.Header.Set("User-Agent", "")
+		}
+		if prior := req.Header.Values(headerForwardedFor); len(prior) > 0 {
+			req.Header.Set(headerForwardedFor, strings.Join(prior, ", ")+", "+ip)
+		} else {
+			req.Header.Set(headerForwardedFor, ip)
+		}
+		if err = req.Write(dst); err != nil {
+			return err
+		}
+		if strings.EqualFold(req.Header.Get("Connection"), "upgrade") || req.Header.Get("Upgrade") != "" {
+			// protocol switched (e.g. websocket), rest of stream is no longer http
+			_, err = copyBuffer(dst, reader)
+			return err
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package sshchannel
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_proxyProtocolHeader(t *testing.T) {
+	cases := []struct {
+		src    net.Addr
+		dst    net.Addr
+		header string
+	}{
+		{
+			src:    &net.TCPAddr{IP: net.ParseIP("10.0.0.9"), Port: 43210},
+			dst:    &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+			header: "PROXY TCP4 10.0.0.9 10.0.0.1 43210 8080\r\n",
+		},
+		{
+			src:    &net.TCPAddr{IP: net.ParseIP("fd00::9"), Port: 43210},
+			dst:    &net.TCPAddr{IP: net.ParseIP("fd00::1"), Port: 8080},
+			header: "PROXY TCP6 fd00::9 fd00::1 43210 8080\r\n",
+		},
+		{
+			src:    &net.UnixAddr{Name: "/tmp/sock", Net: "unix"},
+			dst:    &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+			header: "PROXY UNKNOWN\r\n",
+		},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.header, proxyProtocolHeader(c.src, c.dst))
+	}
+}
+
+func Test_copyWithForwardedFor(t *testing.T) {
+	cases := []struct {
+		input  string
+		output string
+	}{
+		{
+			input:  "GET /a HTTP/1.1\r\nHost: x\r\n\r\n",
+			output: "GET /a HTTP/1.1\r\nHost: x\r\nX-Forwarded-For: 10.0.0.9\r\n\r\n",
+		},
+		{
+			input:  "GET /a HTTP/1.1\r\nHost: x\r\nX-Forwarded-For: 1.1.1.1\r\n\r\n",
+			output: "GET /a HTTP/1.1\r\nHost: x\r\nX-Forwarded-For: 1.1.1.1, 10.0.0.9\r\n\r\n",
+		},
+		{
+			input: "POST /b HTTP/1.1\r\nHost: x\r\nContent-Length: 3\r\n\r\nabc" +
+				"GET /c HTTP/1.1\r\nHost: x\r\n\r\n",
+			output: "POST /b HTTP/1.1\r\nHost: x\r\nContent-Length: 3\r\nX-Forwarded-For: 10.0.0.9\r\n\r\nabc" +
+				"GET /c HTTP/1.1\r\nHost: x\r\nX-Forwarded-For: 10.0.0.9\r\n\r\n",
+		},
+		{
+			input: "GET /ws HTTP/1.1\r\nHost: x\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\nRAW",
+			output: "GET /ws HTTP/1.1\r\nHost: x\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n" +
+				"X-Forwarded-For: 10.0.0.9\r\n\r\nRAW",
+		},
+	}
+	for _, c := range cases {
+		var out strings.Builder
+		require.NoError(t, copyWithForwardedFor(&out, strings.NewReader(c.input), "10.0.0.9"))
+		require.Equal(t, c.output, out.String())
+	}
+}
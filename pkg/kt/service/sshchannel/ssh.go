@@ -5,10 +5,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"io"
 	"net"
-	"strings"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -18,23 +20,60 @@ import (
 
 type SocksLogger struct {}
 
+// acceptedRequests count of connections accepted by reverse tunnels
+var acceptedRequests int64
+
+// AcceptedRequestCount total connections received via reverse tunnels of current process
+func AcceptedRequestCount() int64 {
+	return atomic.LoadInt64(&acceptedRequests)
+}
+
 func (s SocksLogger) Println(v ...any) {
 	_, _ = util.BackgroundLogger.Write([]byte(fmt.Sprint(v...) + util.Eol))
 }
 
-// StartSocks5Proxy start socks5 proxy
-func (c *Cli) StartSocks5Proxy(privateKey, sshAddress, socks5Address string) (err error) {
+// StartSocks5Proxy start socks5 proxy, and http proxy with same ssh tunnel if http proxy address is not empty
+func (c *Cli) StartSocks5Proxy(privateKey, sshAddress, socks5Address, httpProxyAddress string) (err error) {
 	dialer, err := sshproxy.NewDialer(getSshTunnelAddress(privateKey, sshAddress))
 	if err != nil {
 		return err
 	}
 	defer dialer.Close()
 
+	client, err := dialer.SSHClient(context.Background())
+	if err != nil {
+		log.Debug().Err(err).Msgf("Failed to create ssh tunnel")
+		return err
+	}
+
+	listener, err := net.Listen("tcp", socks5Address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	// stop serving when ssh connection broken, so that caller could reconnect
+	done := make(chan struct{})
+	defer close(done)
+	go keepAlive(client, c.KeepAlive, done, func() { _ = listener.Close() })
+
+	if httpProxyAddress != "" {
+		httpListener, err2 := net.Listen("tcp", httpProxyAddress)
+		if err2 != nil {
+			return err2
+		}
+		defer httpListener.Close()
+		go func() {
+			err3 := http.Serve(httpListener, newHttpProxy(dialer.DialContext))
+			log.Debug().Err(err3).Msgf("Http proxy stopped")
+		}()
+	}
+
 	svc := &socks5.Server{
 		Logger:    SocksLogger{},
 		ProxyDial: dialer.DialContext,
 	}
-	return svc.ListenAndServe("tcp", socks5Address)
+	return svc.Serve(listener)
 }
 
 // RunScript run the script on remote host.
@@ -79,7 +118,7 @@ func (c *Cli) ForwardRemoteToLocal(privateKey, sshAddress, remoteEndpoint, local
 	}
 	defer dialer.Close()
 
-	_, err = dialer.SSHClient(context.Background())
+	client, err := dialer.SSHClient(context.Background())
 	if err != nil {
 		log.Debug().Err(err).Msgf("Failed to create ssh tunnel")
 		return err
@@ -94,9 +133,14 @@ func (c *Cli) ForwardRemoteToLocal(privateKey, sshAddress, remoteEndpoint, local
 	}
 	defer listener.Close()
 
+	// closing ssh client also ends the remote listener, which let caller reconnect
+	done := make(chan struct{})
+	defer close(done)
+	go keepAlive(client, c.KeepAlive, done, nil)
+
 	log.Info().Msgf("Reverse tunnel %s -> %s established", remoteEndpoint, localEndpoint)
 	for {
-		if err = handleRequest(listener, localEndpoint); errors.Is(err, io.EOF) {
+		if err = handleRequest(listener, remoteEndpoint, localEndpoint, dialer.DialContext); errors.Is(err, io.EOF) {
 			return err
 		}
 	}
@@ -107,7 +151,7 @@ func getSshTunnelAddress(privateKey string, sshAddress string) string {
 }
 
 func disconnectRemotePort(privateKey, sshAddress, remoteEndpoint string, c *Cli) {
-	remotePort := strings.Split(remoteEndpoint, ":")[1]
+	remotePort := endpointPort(remoteEndpoint)
 	out, err := c.RunScript(privateKey, sshAddress, fmt.Sprintf("/disconnect.sh %s", remotePort))
 	if out != "" {
 		_, _ = util.BackgroundLogger.Write([]byte(out + util.Eol))
@@ -117,7 +161,7 @@ func disconnectRemotePort(privateKey, sshAddress, remoteEndpoint string, c *Cli)
 	}
 }
 
-func handleRequest(listener net.Listener, localEndpoint string) error {
+func handleRequest(listener net.Listener, remoteEndpoint, localEndpoint string, dial dialFunc) error {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Error().Msgf("Failed to handle request: %v", r)
@@ -133,6 +177,37 @@ func handleRequest(listener net.Listener, localEndpoint string) error {
 		}
 		return err
 	}
+	// every handler closes the connection when finished
+	client = newCountedConn(client)
+	if hosts, ok := getPassthroughHosts(remoteEndpoint); ok {
+		// not exchanged port, send back to original pods
+		go handlePassthroughRequest(client, remoteEndpoint, hosts, dial)
+		return nil
+	}
+	atomic.AddInt64(&acceptedRequests, 1)
+	if hosts := getPausedHosts(); len(hosts) > 0 {
+		// exchange paused, send back to original pods
+		go handlePausedRequest(client, remoteEndpoint, hosts, dial)
+		return nil
+	}
+	if isRampDiverted() {
+		go handleRampDivertedRequest(client, remoteEndpoint, dial)
+		return nil
+	}
+	if !isLocalReady(remoteEndpoint) {
+		go handleUnreadyRequest(client, remoteEndpoint, dial)
+		return nil
+	}
+	if localLimit != nil {
+		// wait or shed in individual coroutine, avoid blocking other requests
+		go handleLimitedRequest(client, remoteEndpoint, localEndpoint, dial)
+		return nil
+	}
+
+	if router, ok := getPathRouter(remoteEndpoint); ok {
+		go router.serve(terminateTls(client))
+		return nil
+	}
 
 	// Open a (local) connection to localEndpoint whose content will be forwarded to remoteEndpoint
 	local, err := net.Dial("tcp", localEndpoint)
@@ -143,7 +218,7 @@ func handleRequest(listener net.Listener, localEndpoint string) error {
 	}
 
 	// Handle request in individual coroutine, current coroutine continue to accept more requests
-	go handleClient(client, local)
+	go handleClient(terminateTls(client), local)
 	return nil
 }
 
@@ -154,7 +229,7 @@ func handleClient(client net.Conn, remote net.Conn) {
 	remoteReader := util.NewInterpretableReader(remote)
 	go func() {
 		defer handleBrokenTunnel(done)
-		if _, err := io.Copy(client, remoteReader); err != nil {
+		if _, err := copyBuffer(client, remoteReader); err != nil {
 			log.Warn().Err(err).Msgf("Error while copy remote->local")
 		}
 		done<-1
@@ -164,7 +239,7 @@ func handleClient(client net.Conn, remote net.Conn) {
 	localReader := util.NewInterpretableReader(client)
 	go func() {
 		defer handleBrokenTunnel(done)
-		if _, err := io.Copy(remote, localReader); err != nil {
+		if err := copyToLocal(remote, localReader, client); err != nil {
 			log.Warn().Err(err).Msgf("Error while copy local->remote")
 		}
 		done<-1
@@ -177,6 +252,19 @@ func handleClient(client net.Conn, remote net.Conn) {
 	_ = client.Close()
 }
 
+// writerOnly hide ReadFrom method of writer, to make io.CopyBuffer really use the specified buffer
+type writerOnly struct {
+	io.Writer
+}
+
+func copyBuffer(dst io.Writer, src io.Reader) (int64, error) {
+	bufferSize := opt.Get().Global.CopyBufferSize
+	if bufferSize <= 0 {
+		bufferSize = util.DefaultCopyBufferSizeKb
+	}
+	return io.CopyBuffer(writerOnly{dst}, src, make([]byte, bufferSize*1024))
+}
+
 func handleBrokenTunnel(done chan int) {
 	if r := recover(); r != nil {
 		log.Error().Msgf("Ssh tunnel broken: %v", r)
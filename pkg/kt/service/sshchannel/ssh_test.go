@@ -0,0 +1,47 @@
+package sshchannel
+
+import (
+	"fmt"
+	opt "github.com/alibaba/kt-connect/pkg/kt/command/options"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"testing"
+)
+
+func Test_endpointPort(t *testing.T) {
+	require.Equal(t, "8080", endpointPort("0.0.0.0:8080"))
+	require.Equal(t, "8080", endpointPort("[::1]:8080"))
+	require.Equal(t, "8080", endpointPort("[fd00::a:1]:8080"))
+}
+
+func Benchmark_handleClient(b *testing.B) {
+	for _, size := range []int{4, 32, 256, 1024} {
+		b.Run(fmt.Sprintf("buffer-%dk", size), func(b *testing.B) {
+			benchmarkHandleClient(b, size)
+		})
+	}
+}
+
+func benchmarkHandleClient(b *testing.B, bufferSizeInKb int) {
+	opt.Get().Global.CopyBufferSize = bufferSizeInKb
+	localSide, client := net.Pipe()
+	remote, remoteSide := net.Pipe()
+	defer localSide.Close()
+	defer remoteSide.Close()
+	go handleClient(client, remote)
+
+	payload := make([]byte, 1024*1024)
+	b.SetBytes(int64(len(payload)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	go func() {
+		for i := 0; i < b.N; i++ {
+			_, _ = localSide.Write(payload)
+		}
+	}()
+	if _, err := io.CopyN(io.Discard, remoteSide, int64(b.N*len(payload))); err != nil {
+		b.Fatal(err)
+	}
+	b.StopTimer()
+}
@@ -0,0 +1,23 @@
+package sshchannel
+
+import (
+	"crypto/tls"
+	"net"
+)
+
+// localTlsConfig server side tls config for connections forwarded to local, nil means no tls termination
+var localTlsConfig *tls.Config
+
+// SetupTlsTermination decrypt tls connections from remote before forwarding them to local,
+// connections passed back to original pods are kept untouched
+func SetupTlsTermination(config *tls.Config) {
+	localTlsConfig = config
+}
+
+// terminateTls wrap connection from remote with tls server if termination enabled
+func terminateTls(client net.Conn) net.Conn {
+	if localTlsConfig == nil {
+		return client
+	}
+	return tls.Server(client, localTlsConfig)
+}
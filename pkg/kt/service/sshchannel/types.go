@@ -2,19 +2,23 @@ package sshchannel
 
 // Channel network channel
 type Channel interface {
-	StartSocks5Proxy(privateKey, sshAddress, socks5Address string) error
+	StartSocks5Proxy(privateKey, sshAddress, socks5Address, httpProxyAddress string) error
 	ForwardRemoteToLocal(privateKey, sshAddress, remoteEndpoint, localEndpoint string) error
 	RunScript(privateKey, sshAddress, script string) (string, error)
 }
 
 // Cli the singleton type
-type Cli struct {}
+type Cli struct {
+	KeepAlive KeepAliveConfig
+	Reconnect ReconnectPolicy
+}
+
 var instance *Cli
 
 // Ins get singleton instance
 func Ins() Channel {
 	if instance == nil {
-		instance = &Cli{}
+		instance = &Cli{KeepAlive: DefaultKeepAlive, Reconnect: DefaultReconnect}
 	}
 	return instance
 }
@@ -10,8 +10,8 @@ import (
 	"time"
 )
 
-// ForwardPodToLocal mapping pod port to local port
-func ForwardPodToLocal(exposePorts, podName, privateKey string) (int, error) {
+// ForwardPodToLocal mapping pod port to local port, on specified local address or 127.0.0.1 if empty
+func ForwardPodToLocal(exposePorts, podName, privateKey, localAddr string) (int, error) {
 	log.Info().Msgf("Forwarding pod %s to local via port %s", podName, exposePorts)
 	localSshPort := util.GetRandomTcpPort()
 
@@ -20,7 +20,7 @@ func ForwardPodToLocal(exposePorts, podName, privateKey string) (int, error) {
 		return -1, err
 	}
 
-	err := ForwardRemotePortsViaSshTunnel(exposePorts, localSshPort, privateKey)
+	err := ForwardRemotePortsViaSshTunnel(exposePorts, localSshPort, privateKey, localAddr)
 	if err != nil {
 		return -1, err
 	}
@@ -29,7 +29,7 @@ func ForwardPodToLocal(exposePorts, podName, privateKey string) (int, error) {
 }
 
 // ForwardRemotePortsViaSshTunnel forward multiple remote ports to local
-func ForwardRemotePortsViaSshTunnel(exposePorts string, localSshPort int, privateKey string) error {
+func ForwardRemotePortsViaSshTunnel(exposePorts string, localSshPort int, privateKey, localAddr string) error {
 	// supports multi port-pairs
 	portPairs := strings.Split(exposePorts, ",")
 	res := make(chan error)
@@ -38,7 +38,7 @@ func ForwardRemotePortsViaSshTunnel(exposePorts string, localSshPort int, privat
 		if err2 != nil {
 			return err2
 		}
-		forwardRemotePortViaSshTunnel(localPort, remotePort, localSshPort, privateKey, res)
+		forwardRemotePortViaSshTunnel(localPort, remotePort, localSshPort, privateKey, localAddr, res)
 	}
 	select {
 	case err := <-res:
@@ -53,28 +53,32 @@ func ForwardRemotePortsViaSshTunnel(exposePorts string, localSshPort int, privat
 }
 
 // ForwardRemotePortViaSshTunnel forward remote pod to local
-func forwardRemotePortViaSshTunnel(localPort, remotePort, localSshPort int, privateKey string, res chan error) {
+func forwardRemotePortViaSshTunnel(localPort, remotePort, localSshPort int, privateKey, localAddr string, res chan error) {
 	remoteEndpoint := fmt.Sprintf("127.0.0.1:%d", localSshPort)
 	localEndpoint := fmt.Sprintf("0.0.0.0:%d", remotePort)
-	sshAddress := fmt.Sprintf("127.0.0.1:%d", localPort)
+	sshAddress := util.LocalEndpoint(localAddr, localPort)
 	log.Debug().Msgf("Forwarding %s to local endpoint %s via %s", remoteEndpoint, localEndpoint, sshAddress)
 	sshReverseTunnel(privateKey, remoteEndpoint, localEndpoint, sshAddress, res)
 }
 
 func sshReverseTunnel(privateKey, remoteEndpoint, localEndpoint, sshAddress string, res chan error) {
 	go func() {
-		err := sshchannel.Ins().ForwardRemoteToLocal(privateKey, remoteEndpoint, localEndpoint, sshAddress)
-		if err != nil {
-			if res != nil {
+		defer util.RecoverPanic("reverse tunnel", func() {
+			sshReverseTunnel(privateKey, remoteEndpoint, localEndpoint, sshAddress, nil)
+		})
+		reconnector := sshchannel.NewReconnector(fmt.Sprintf("Reverse tunnel %s -> %s", localEndpoint, sshAddress))
+		for {
+			connectedAt := time.Now()
+			err := sshchannel.Ins().ForwardRemoteToLocal(privateKey, remoteEndpoint, localEndpoint, sshAddress)
+			if err != nil && res != nil {
 				log.Error().Err(err).Msgf("Failed to setup reverse tunnel")
 				res <-err
-			} else {
-				log.Debug().Err(err).Msgf("Reverse tunnel interrupted")
 			}
+			res = nil
+			if !reconnector.Wait(err, connectedAt) {
+				return
+			}
+			log.Info().Msgf("Reverse tunnel reconnecting ...")
 		}
-
-		time.Sleep(10 * time.Second)
-		log.Debug().Msgf("Reverse tunnel reconnecting ...")
-		sshReverseTunnel(privateKey, remoteEndpoint, localEndpoint, sshAddress, nil)
 	}()
 }
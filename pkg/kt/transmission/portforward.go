@@ -6,6 +6,7 @@ import (
 	"github.com/alibaba/kt-connect/pkg/kt/service/cluster"
 	"github.com/alibaba/kt-connect/pkg/kt/util"
 	"github.com/rs/zerolog/log"
+	spdyStream "k8s.io/apimachinery/pkg/util/httpstream/spdy"
 	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/transport/spdy"
 	"net/http"
@@ -25,6 +26,9 @@ func setupPortForwardToLocal(podName string, remotePort, localPort int, gone cha
 	ready := make(chan struct{})
 	var ticker *time.Ticker
 	go func() {
+		defer util.RecoverPanic("port forward", func() {
+			_ = setupPortForwardToLocal(podName, remotePort, localPort, gone, false)
+		})
 		stop := make(chan struct{})
 		fw, err := createPortForwarder(podName, remotePort, localPort, stop, ready)
 		if err != nil {
@@ -71,6 +75,10 @@ func createPortForwarder(podName string, remotePort, localPort int, stop, ready
 	if err != nil {
 		return nil, err
 	}
+	if rt, ok := upgrader.(*spdyStream.SpdyRoundTripper); ok && opt.Get().Connect.Fwmark > 0 && util.FwmarkSupported {
+		// spdy round tripper dials by itself instead of using dial function of rest config
+		rt.Dialer = util.FwmarkDialer(opt.Get().Connect.Fwmark)
+	}
 
 	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, apiUrl)
 	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
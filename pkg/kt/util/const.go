@@ -30,16 +30,42 @@ const (
 	ConnectModeShuttle = "sshuttle"
 	// ConnectModeTun2Socks tun2socks mode
 	ConnectModeTun2Socks = "tun2socks"
+	// ConnectModeSocks5 socks5 proxy only mode
+	ConnectModeSocks5 = "socks5"
+	// ConnectModeAuto auto select connect mode
+	ConnectModeAuto = "auto"
 	// ExchangeModeScale scale mode
 	ExchangeModeScale = "scale"
 	// ExchangeModeEphemeral ephemeral mode
 	ExchangeModeEphemeral = "ephemeral"
 	// ExchangeModeSelector selector mode
 	ExchangeModeSelector = "selector"
+	// OverloadActionQueue queue connections exceeding local rate limit
+	OverloadActionQueue = "queue"
+	// OverloadActionShed drop connections exceeding local rate limit
+	OverloadActionShed = "shed"
+	// ExposeProtocolHttp expose port serving http, checked by http probe
+	ExposeProtocolHttp = "http"
+	// ExposeProtocolGrpc expose port serving grpc, checked by http2 handshake
+	ExposeProtocolGrpc = "grpc"
+	// ExposeProtocolTcp expose port serving plain tcp, checked by connecting
+	ExposeProtocolTcp = "tcp"
 	// MeshModeAuto auto mode
 	MeshModeAuto = "auto"
 	// MeshModeManual manual mode
 	MeshModeManual = "manual"
+	// RoutingBackendAuto detect routing backend from cluster
+	RoutingBackendAuto = "auto"
+	// RoutingBackendRouter route with kt router pod
+	RoutingBackendRouter = "router"
+	// RoutingBackendIstio route with istio virtual service
+	RoutingBackendIstio = "istio"
+	// RoutingBackendGatewayApi route with gateway api http route
+	RoutingBackendGatewayApi = "gatewayapi"
+	// OutputText print hints for human only
+	OutputText = "text"
+	// OutputJson also print result of command as json
+	OutputJson = "json"
 	// DnsModeLocalDns local dns mode
 	DnsModeLocalDns = "localDNS"
 	// DnsModePodDns pod dns mode
@@ -69,9 +95,19 @@ const (
 	KtLastHeartBeat = "kt-last-heart-beat"
 	// KtLock annotation used for avoid auto mesh conflict
 	KtLock = "kt-lock"
+	// KtSession annotation used for record session id of the ktctl instance who created the resource
+	KtSession = "kt-session"
+	// KtOwner annotation used for record host name and pid of the ktctl instance who created the resource
+	KtOwner = "kt-owner"
+	// KtRouteOrigin annotation used for record origin rules of route resource redirected by exchange
+	KtRouteOrigin = "kt-route-origin"
+	// KtReplicas annotation used for record origin replicas of deployment kept scaled down after exchange
+	KtReplicas = "kt-replicas"
 
 	// PostfixRsaKey postfix of local private key name
 	PostfixRsaKey = ".key"
+	// PostfixTlsCert postfix of generated tls cert name
+	PostfixTlsCert = ".crt"
 	// RouterBin path to router executable
 	RouterBin = "/usr/sbin/router"
 	// SshBitSize ssh bit size
@@ -86,12 +122,18 @@ const (
 	KtExchangeContainer = "kt-exchange"
 	// DefaultContainer default container name
 	DefaultContainer = "standalone"
+	// ShadowInitContainer name of init container in shadow pod
+	ShadowInitContainer = "kt-init"
 	// StuntmanServiceSuffix suffix of stuntman service name
 	StuntmanServiceSuffix = "-kt-stuntman"
 	// RouterPodSuffix suffix of router pod name
 	RouterPodSuffix = "-kt-router"
+	// RouteRuleSuffix suffix of virtual service or http route name
+	RouteRuleSuffix = "-kt-route"
 	// ExchangePodInfix exchange pod name
 	ExchangePodInfix = "-kt-exchange-"
+	// PassthroughPodInfix pod preserving original workload for ports not exchanged
+	PassthroughPodInfix = "-kt-passthrough-"
 	// MeshPodInfix mesh pod and mesh service name
 	MeshPodInfix = "-kt-mesh-"
 	// RectifierPodPrefix rectifier pod name
@@ -106,6 +148,8 @@ const (
 	RolePreviewShadow = "shadow-preview"
 	// RoleRouter router role
 	RoleRouter = "router"
+	// RolePassthrough passthrough pod role
+	RolePassthrough = "passthrough"
 	// SortByName birdseye sort
 	SortByName = "name"
 	// SortByStatus birdseye sort
@@ -118,6 +162,22 @@ const (
 	TunNameMac = "utun"
 	// AlternativeDnsPort alternative port for local dns
 	AlternativeDnsPort = 10053
+	// DefaultCopyBufferSizeKb default size of buffer for copying tunnel data, same as io.Copy
+	DefaultCopyBufferSizeKb = 32
+	// MaxCopyBufferSizeKb max size of buffer for copying tunnel data
+	MaxCopyBufferSizeKb = 1024
+	// SourceIpProxyProtocol pass client ip to local via PROXY protocol header
+	SourceIpProxyProtocol = "proxy"
+	// SourceIpHttpHeader pass client ip to local via X-Forwarded-For header
+	SourceIpHttpHeader = "http"
+	// ExitCodeDeadline exit code when command stopped by deadline, same as timeout command
+	ExitCodeDeadline = 124
+	// ExitCodePanic exit code when command stopped by unexpected panic, same as go runtime
+	ExitCodePanic = 2
+	// ExitCodeTunnelLost exit code when ssh tunnel cannot be re-established
+	ExitCodeTunnelLost = 3
+	// MaxUnconfirmedSelectedServices max services exchanged via label selector without '--yes'
+	MaxUnconfirmedSelectedServices = 5
 
 	// ResourceHeartBeatIntervalMinus interval of resource heart beat
 	ResourceHeartBeatIntervalMinus = 2
@@ -132,5 +192,6 @@ var (
 	KtPidDir = fmt.Sprintf("%s/pid", KtHome)
 	KtLockDir = fmt.Sprintf("%s/lock", KtHome)
 	KtProfileDir = fmt.Sprintf("%s/profile", KtHome)
+	KtServiceProfileDir = fmt.Sprintf("%s/profiles", KtHome)
 	KtConfigFile = fmt.Sprintf("%s/config", KtHome)
 )
\ No newline at end of file
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"net"
+	"syscall"
+	"time"
+)
+
+// FwmarkSupported whether sockets can be marked with fwmark on current platform
+const FwmarkSupported = true
+
+// FwmarkDialer dialer whose sockets are marked with specified fwmark, for policy routing to pick the outgoing path
+func FwmarkDialer(mark int) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_MARK, mark)
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		},
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package util
+
+import "net"
+
+// FwmarkSupported whether sockets can be marked with fwmark on current platform
+const FwmarkSupported = false
+
+// FwmarkDialer socket mark is linux only, always nil on other platforms
+func FwmarkDialer(_ int) *net.Dialer {
+	return nil
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ListeningLocalPorts tcp ports listened by any process on current machine, in ascending order without duplication
+func ListeningLocalPorts() ([]int, error) {
+	ports, err := listeningPorts()
+	if err != nil {
+		return nil, err
+	}
+	sort.Ints(ports)
+	unique := make([]int, 0, len(ports))
+	for i, p := range ports {
+		if i == 0 || p != ports[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	return unique, nil
+}
+
+// parseProcNetTcp extract ports in LISTEN state from content of /proc/net/tcp or /proc/net/tcp6
+func parseProcNetTcp(content string) []int {
+	ports := make([]int, 0)
+	for _, line := range strings.Split(content, "\n") {
+		// e.g. "0: 00000000:1F90 00000000:0000 0A ...", state 0A means LISTEN
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[3] != "0A" {
+			continue
+		}
+		i := strings.LastIndex(fields[1], ":")
+		if port, err := strconv.ParseInt(fields[1][i+1:], 16, 32); err == nil {
+			ports = append(ports, int(port))
+		}
+	}
+	return ports
+}
+
+// parseLsofOutput extract ports from output of 'lsof -nP -iTCP -sTCP:LISTEN'
+func parseLsofOutput(output string) []int {
+	ports := make([]int, 0)
+	for _, line := range strings.Split(output, "\n") {
+		// name column looks like "*:8080 (LISTEN)", "127.0.0.1:8080 (LISTEN)" or "[::1]:8080 (LISTEN)"
+		fields := strings.Fields(line)
+		for i := 1; i < len(fields); i++ {
+			if fields[i] != "(LISTEN)" {
+				continue
+			}
+			address := fields[i-1]
+			if port, err := strconv.Atoi(address[strings.LastIndex(address, ":")+1:]); err == nil {
+				ports = append(ports, port)
+			}
+		}
+	}
+	return ports
+}
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func listeningPorts() ([]int, error) {
+	ports := make([]int, 0)
+	for _, file := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		content, err := ioutil.ReadFile(file)
+		if os.IsNotExist(err) {
+			// ipv6 could be disabled
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", file, err)
+		}
+		ports = append(ports, parseProcNetTcp(string(content))...)
+	}
+	return ports, nil
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package util
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func listeningPorts() ([]int, error) {
+	if _, err := exec.LookPath("lsof"); err != nil {
+		return nil, fmt.Errorf("lsof is required for listing listening ports: %s", err)
+	}
+	stdout, stderr, err := RunAndWait(exec.Command("lsof", "-nP", "-iTCP", "-sTCP:LISTEN"))
+	// lsof exits with 1 when no port matched
+	if err != nil && stdout == "" && stderr != "" {
+		return nil, fmt.Errorf("failed to list listening ports: %s", stderr)
+	}
+	return parseLsofOutput(stdout), nil
+}
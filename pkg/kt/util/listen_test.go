@@ -0,0 +1,38 @@
+package util
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListeningLocalPorts(t *testing.T) {
+	listened, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listened.Close()
+	ports, err := ListeningLocalPorts()
+	require.NoError(t, err)
+	require.Contains(t, ports, listened.Addr().(*net.TCPAddr).Port)
+}
+
+func Test_parseProcNetTcp(t *testing.T) {
+	content := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 12345 1
+   1: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 12346 1
+   2: 0100007F:1F90 0100007F:D2A4 01 00000000:00000000 00:00000000 00000000  1000        0 12347 1`
+	require.Equal(t, []int{8080, 3306}, parseProcNetTcp(content))
+	content6 := `  sl  local_address                         remote_address                        st
+   0: 00000000000000000000000000000000:1F91 00000000000000000000000000000000:0000 0A 00000000:00000000`
+	require.Equal(t, []int{8081}, parseProcNetTcp(content6))
+	require.Empty(t, parseProcNetTcp(""))
+}
+
+func Test_parseLsofOutput(t *testing.T) {
+	output := `COMMAND   PID USER   FD   TYPE DEVICE SIZE/OFF NODE NAME
+java    12345  tom   45u  IPv6 0x1234      0t0  TCP *:8080 (LISTEN)
+node    12346  tom   22u  IPv4 0x1235      0t0  TCP 127.0.0.1:3000 (LISTEN)
+redis   12347  tom    6u  IPv6 0x1236      0t0  TCP [::1]:6379 (LISTEN)`
+	require.Equal(t, []int{8080, 3000, 6379}, parseLsofOutput(output))
+	require.Empty(t, parseLsofOutput(""))
+}
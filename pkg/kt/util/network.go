@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/rs/zerolog/log"
 	"net"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -29,8 +30,24 @@ func GetRandomTcpPort() int {
 	return port
 }
 
-// ParsePortMapping parse <port> or <localPort>:<removePort> parameter
+// ParsePortMapping parse <port> or <localPort>:<removePort> parameter, with optional protocol suffix ignored
 func ParsePortMapping(exposePort string) (int, int, error) {
+	lp, rp, _, err := ParseExposePort(exposePort)
+	return lp, rp, err
+}
+
+// ParseExposePort parse <port>[/<protocol>] or <localPort>:<removePort>[/<protocol>] parameter,
+// protocol is empty if not specified
+func ParseExposePort(exposePort string) (int, int, string, error) {
+	protocol := ""
+	if i := strings.LastIndex(exposePort, "/"); i >= 0 {
+		protocol = exposePort[i+1:]
+		exposePort = exposePort[:i]
+		if protocol != ExposeProtocolHttp && protocol != ExposeProtocolGrpc && protocol != ExposeProtocolTcp {
+			return -1, -1, "", fmt.Errorf("invalid protocol '%s' of port '%s', supported are %s, %s, %s",
+				protocol, exposePort, ExposeProtocolHttp, ExposeProtocolGrpc, ExposeProtocolTcp)
+		}
+	}
 	localPort := exposePort
 	remotePort := exposePort
 	ports := strings.SplitN(exposePort, ":", 2)
@@ -40,28 +57,76 @@ func ParsePortMapping(exposePort string) (int, int, error) {
 	}
 	lp, err := strconv.Atoi(localPort)
 	if err != nil {
-		return -1, -1, fmt.Errorf("local port '%s' is not a number", localPort)
+		return -1, -1, "", fmt.Errorf("local port '%s' is not a number", localPort)
 	}
 	rp, err := strconv.Atoi(remotePort)
 	if err != nil {
-		return -1, -1, fmt.Errorf("remote port '%s' is not a number", remotePort)
+		return -1, -1, "", fmt.Errorf("remote port '%s' is not a number", remotePort)
 	}
-	return lp, rp, nil
+	return lp, rp, protocol, nil
 }
 
-// FindBrokenLocalPort Check if all ports has process listening to
-// Return empty string if all ports are listened, otherwise return the first broken port
-func FindBrokenLocalPort(exposePorts string) string {
-	portPairs := strings.Split(exposePorts, ",")
-	for _, exposePort := range portPairs {
-		localPort := strings.Split(exposePort, ":")[0]
-		conn, err := net.Dial("tcp", fmt.Sprintf(":%s", localPort))
+// LocalEndpoint address of local app to forward connections to, localAddr defaults to 127.0.0.1 when empty
+func LocalEndpoint(localAddr string, port int) string {
+	if localAddr == "" {
+		localAddr = "127.0.0.1"
+	}
+	return net.JoinHostPort(localAddr, strconv.Itoa(port))
+}
+
+// CheckLocalPorts Check which local ports of expose parameter have process listening to
+// Return listened ports and broken ports in order of appearance
+func CheckLocalPorts(exposePorts string) ([]int, []int, error) {
+	return CheckLocalPortsOn(exposePorts, "")
+}
+
+// CheckLocalPortsOn Check which local ports of expose parameter have process listening to,
+// on specified local address, or any local address if it's empty
+// Return listened ports and broken ports in order of appearance
+func CheckLocalPortsOn(exposePorts, localAddr string) ([]int, []int, error) {
+	ok := make([]int, 0)
+	broken := make([]int, 0)
+	for _, exposePort := range strings.Split(exposePorts, ",") {
+		localPort, _, err := ParsePortMapping(exposePort)
+		if err != nil {
+			return nil, nil, err
+		}
+		address := fmt.Sprintf(":%d", localPort)
+		if localAddr != "" {
+			address = LocalEndpoint(localAddr, localPort)
+		}
+		conn, err := net.Dial("tcp", address)
 		if err == nil {
 			_ = conn.Close()
+			ok = append(ok, localPort)
 		} else {
-			return localPort
+			broken = append(broken, localPort)
+		}
+	}
+	return ok, broken, nil
+}
+
+// FindBrokenLocalPort Check if all ports has process listening to
+// Return empty string if all ports are listened, otherwise return the first broken port
+func FindBrokenLocalPort(exposePorts string) string {
+	return FindBrokenLocalPortOn(exposePorts, "")
+}
+
+// FindBrokenLocalPortOn Check if all ports has process listening to, on specified local address if not empty
+// Return empty string if all ports are listened, otherwise return the first broken port
+func FindBrokenLocalPortOn(exposePorts, localAddr string) string {
+	_, broken, err := CheckLocalPortsOn(exposePorts, localAddr)
+	if err != nil {
+		// port not in number format is treated as broken
+		for _, exposePort := range strings.Split(exposePorts, ",") {
+			if _, _, err2 := ParsePortMapping(exposePort); err2 != nil {
+				return strings.Split(strings.SplitN(exposePort, "/", 2)[0], ":")[0]
+			}
 		}
 	}
+	if len(broken) > 0 {
+		return strconv.Itoa(broken[0])
+	}
 	return ""
 }
 
@@ -75,7 +140,7 @@ func FindInvalidRemotePort(exposePorts string, svcPorts map[int]string) string {
 
 	portPairs := strings.Split(exposePorts, ",")
 	for _, exposePort := range portPairs {
-		splitPorts := strings.Split(exposePort, ":")
+		splitPorts := strings.Split(strings.SplitN(exposePort, "/", 2)[0], ":")
 		remotePort := splitPorts[0]
 		if len(splitPorts) > 1 {
 			remotePort = splitPorts[1]
@@ -95,24 +160,43 @@ func IsValidIp(ip string) bool {
 	return false
 }
 
-// ExtractHostIp Get host ip address from url
-func ExtractHostIp(url string) string {
-	if !strings.Contains(url, ":") {
+// ExtractHostIp Get host ip address from url, ipv6 address is returned only if url host is an ipv6 literal
+// or the host has no ipv4 address
+func ExtractHostIp(address string) string {
+	if !strings.Contains(address, "://") {
+		address = "//" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return ""
+	}
+	host := u.Hostname()
+	if host == "" {
 		return ""
 	}
-	host := strings.Trim(strings.Split(url, ":")[1], "/")
-	if IsValidIp(host) {
-		return host
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
 	}
 	ips, err := net.LookupIP(host)
-	if err != nil {
+	if err != nil || len(ips) == 0 {
 		return ""
 	}
 	for _, ip := range ips {
-		// skip ipv6
-		if IsValidIp(ip.String()) {
+		// prefer ipv4
+		if ip.To4() != nil {
 			return ip.String()
 		}
 	}
-	return ""
+	return ips[0].String()
+}
+
+// GetOutboundIp get local ip address used for connecting specified host
+func GetOutboundIp(host string) (string, error) {
+	// udp dial only choose route and local address, no packet is sent
+	conn, err := net.Dial("udp", net.JoinHostPort(host, "443"))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
 }
@@ -1,7 +1,10 @@
 package util
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/require"
+	"net"
+	"strconv"
 	"testing"
 )
 
@@ -10,4 +13,75 @@ func TestExtractHostIp(t *testing.T) {
 	require.Equal(t, "1.2.3.4", ExtractHostIp("http://1.2.3.4:8080"))
 	require.Equal(t, "1.2.3.4", ExtractHostIp("http://1.2.3.4:8080/a/b/c"))
 	require.Equal(t, "127.0.0.1", ExtractHostIp("http://localhost:8080/a/b/c"))
+	require.Equal(t, "fd00::1", ExtractHostIp("https://[fd00::1]:6443"))
+	require.Equal(t, "::1", ExtractHostIp("[::1]:8080"))
+}
+
+func TestLocalEndpoint(t *testing.T) {
+	require.Equal(t, "127.0.0.1:8080", LocalEndpoint("", 8080))
+	require.Equal(t, "[::1]:8080", LocalEndpoint("::1", 8080))
+}
+
+func TestCheckLocalPorts(t *testing.T) {
+	listened, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listened.Close()
+	listenedPort := listened.Addr().(*net.TCPAddr).Port
+	closed, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	ok, broken, err := CheckLocalPorts(fmt.Sprintf("%d,%d:80", listenedPort, closedPort))
+	require.NoError(t, err)
+	require.Equal(t, []int{listenedPort}, ok)
+	require.Equal(t, []int{closedPort}, broken)
+	require.Equal(t, strconv.Itoa(closedPort), FindBrokenLocalPort(fmt.Sprintf("%d,%d:80", listenedPort, closedPort)))
+	require.Equal(t, "", FindBrokenLocalPort(fmt.Sprintf("%d:8080", listenedPort)))
+
+	_, _, err = CheckLocalPorts(fmt.Sprintf("%d,abc", listenedPort))
+	require.Error(t, err)
+	require.Equal(t, "abc", FindBrokenLocalPort(fmt.Sprintf("%d,abc", listenedPort)))
+}
+
+func TestCheckLocalPortsOnAddress(t *testing.T) {
+	listened, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listened.Close()
+	port := listened.Addr().(*net.TCPAddr).Port
+
+	require.Equal(t, "", FindBrokenLocalPortOn(strconv.Itoa(port), "127.0.0.1"))
+	// listener bound on loopback is not reachable via other address
+	require.Equal(t, strconv.Itoa(port), FindBrokenLocalPortOn(strconv.Itoa(port), "127.0.0.2"))
+	require.Equal(t, "127.0.0.1:80", LocalEndpoint("", 80))
+	require.Equal(t, "[::1]:80", LocalEndpoint("::1", 80))
+}
+
+func TestParseExposePort(t *testing.T) {
+	cases := []struct {
+		expose   string
+		local    int
+		remote   int
+		protocol string
+		failed   bool
+	}{
+		{expose: "8080", local: 8080, remote: 8080},
+		{expose: "8080:80/http", local: 8080, remote: 80, protocol: "http"},
+		{expose: "9000/grpc", local: 9000, remote: 9000, protocol: "grpc"},
+		{expose: "6379:6379/tcp", local: 6379, remote: 6379, protocol: "tcp"},
+		{expose: "6379:6379/udp", failed: true},
+		{expose: "abc/http", failed: true},
+	}
+	for _, c := range cases {
+		local, remote, protocol, err := ParseExposePort(c.expose)
+		if c.failed {
+			require.Error(t, err, c.expose)
+			continue
+		}
+		require.NoError(t, err, c.expose)
+		require.Equal(t, c.local, local, c.expose)
+		require.Equal(t, c.remote, remote, c.expose)
+		require.Equal(t, c.protocol, protocol, c.expose)
+	}
+	require.Equal(t, "90", FindInvalidRemotePort("8080:80/http,9090:90/grpc", map[int]string{80: ""}))
 }
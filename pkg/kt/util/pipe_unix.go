@@ -0,0 +1,15 @@
+//go:build !windows
+
+package util
+
+import "fmt"
+
+// ControlPipeName get name of named pipe used for controlling specified component
+func ControlPipeName(component string, pid int) string {
+	return fmt.Sprintf("ktctl-%s-%d", component, pid)
+}
+
+// ListenControlPipe named pipe control channel is only available on windows
+func ListenControlPipe(pipeName string, handler func(string)) error {
+	return fmt.Errorf("named pipe control channel is not supported on this platform")
+}
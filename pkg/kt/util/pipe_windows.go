@@ -0,0 +1,59 @@
+package util
+
+import (
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sys/windows"
+	"strings"
+)
+
+// procDisconnectNamedPipe DisconnectNamedPipe is not provided by golang.org/x/sys/windows of current version
+var procDisconnectNamedPipe = windows.NewLazySystemDLL("kernel32.dll").NewProc("DisconnectNamedPipe")
+
+// ControlPipeName get name of named pipe used for controlling specified component
+func ControlPipeName(component string, pid int) string {
+	return fmt.Sprintf(`\\.\pipe\ktctl-%s-%d`, component, pid)
+}
+
+// ListenControlPipe create a named pipe, and pass every line written to it to the handler
+func ListenControlPipe(pipeName string, handler func(string)) error {
+	name, err := windows.UTF16PtrFromString(pipeName)
+	if err != nil {
+		return err
+	}
+	pipe, err := windows.CreateNamedPipe(name, windows.PIPE_ACCESS_INBOUND,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT, 1, 512, 512, 0, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create named pipe %s: %s", pipeName, err)
+	}
+	go func() {
+		defer windows.CloseHandle(pipe)
+		for {
+			if err2 := windows.ConnectNamedPipe(pipe, nil); err2 != nil && err2 != windows.ERROR_PIPE_CONNECTED {
+				log.Debug().Err(err2).Msgf("Failed to accept client of named pipe %s", pipeName)
+				return
+			}
+			for _, line := range strings.Split(readControlPipe(pipe), "\n") {
+				if command := strings.TrimSpace(line); command != "" {
+					handler(command)
+				}
+			}
+			_, _, _ = procDisconnectNamedPipe.Call(uintptr(pipe))
+		}
+	}()
+	return nil
+}
+
+func readControlPipe(pipe windows.Handle) string {
+	var content strings.Builder
+	buf := make([]byte, 512)
+	for {
+		var n uint32
+		err := windows.ReadFile(pipe, buf, &n, nil)
+		content.Write(buf[:n])
+		if err != nil {
+			// ERROR_BROKEN_PIPE means client finished writing
+			return content.String()
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package util
+
+import (
+	"os"
+	"syscall"
+)
+
+// RestartProcess replace current process with a new run of same command, using specified environment variables
+func RestartProcess(env []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(executable, os.Args, env)
+}
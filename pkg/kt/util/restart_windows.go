@@ -0,0 +1,30 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// RestartProcess run same command in a new process using specified environment variables,
+// current process exits with its exit code, because windows cannot replace process in place
+func RestartProcess(env []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/user"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -65,6 +66,28 @@ func WritePidFile(componentName string, ch chan os.Signal) error {
 	return nil
 }
 
+// WriteFileAtomic write data to a temporary file in same directory, then rename it to target path
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 func watchPidFile(pidFile string, ch chan os.Signal) {
 	watcher, err := fs.NewWatcher()
 	if err != nil {
@@ -127,6 +150,23 @@ func ParseTimestamp(timestamp string) int64 {
 	return unixTime
 }
 
+// GetOwner identity of current ktctl process, in '<hostname>/<pid>' format
+func GetOwner() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s/%d", hostname, os.Getpid())
+}
+
+// IsOwnerAlive check whether the ktctl process of specified owner identity is running on current host
+func IsOwnerAlive(owner string) bool {
+	sep := strings.LastIndex(owner, "/")
+	if sep < 0 {
+		return false
+	}
+	hostname, _ := os.Hostname()
+	pid, err := strconv.Atoi(owner[sep+1:])
+	return err == nil && owner[:sep] == hostname && IsProcessExist(pid)
+}
+
 // FormattedTime get timestamp to print
 func FormattedTime() string {
 	return time.Now().Format(common.YyyyMmDdHhMmSs)
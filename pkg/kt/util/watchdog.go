@@ -0,0 +1,57 @@
+package util
+
+import (
+	"os"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxAutoRestart max times of restarting panicked goroutines in one process
+const maxAutoRestart = 5
+
+var panicCleanup func()
+var autoRestart bool
+var restartCount int32
+
+// SetupWatchdog specify action to clean up before exit when a goroutine panics,
+// or let the panicked goroutine restart instead if autoRestart is true
+func SetupWatchdog(restart bool, cleanup func()) {
+	autoRestart = restart
+	panicCleanup = cleanup
+}
+
+// GoWithRecover run function in new goroutine, clean up and exit, or restart it on panic
+func GoWithRecover(name string, f func()) {
+	go func() {
+		defer RecoverPanic(name, func() {
+			GoWithRecover(name, f)
+		})
+		f()
+	}()
+}
+
+// RecoverPanic should be deferred, log stack of panic, then call restart if auto restart enabled,
+// otherwise clean up and exit with non-zero code
+func RecoverPanic(name string, restart func()) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Error().Msgf("Unexpected panic in %s: %v\n%s", name, r, debug.Stack())
+	if autoRestart && restart != nil {
+		if count := atomic.AddInt32(&restartCount, 1); count <= maxAutoRestart {
+			log.Warn().Msgf("Restarting %s (%d/%d) ...", name, count, maxAutoRestart)
+			time.Sleep(time.Second)
+			restart()
+			return
+		}
+		log.Error().Msgf("Too many panics, giving up restarting")
+	}
+	if panicCleanup != nil {
+		panicCleanup()
+	}
+	os.Exit(ExitCodePanic)
+}
@@ -1,8 +1,75 @@
 package router
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 type KtConf struct {
-	Service  string
-	Ports    [][]string
-	Header   string
-	Versions []string
+	Service   string
+	Ports     [][]string
+	Header    string
+	Versions  []string
+	Fallbacks map[string][]string
+	Cookies   map[string]string
+	Weights   map[string]int
+}
+
+// HeaderVariable nginx variable of version header, e.g. $http_x_tenant_id for header 'x-tenant-id'
+func (c *KtConf) HeaderVariable() string {
+	return "$http_" + strings.ReplaceAll(strings.ToLower(c.Header), "-", "_")
+}
+
+// WeightedVersion version receiving a share of requests without version header, empty if no such version
+func (c *KtConf) WeightedVersion() string {
+	for version, weight := range c.Weights {
+		if weight > 0 {
+			return version
+		}
+	}
+	return ""
+}
+
+// Weight percentage of requests without version header routed to weighted version
+func (c *KtConf) Weight() int {
+	return c.Weights[c.WeightedVersion()]
+}
+
+// CookieCondition generate nginx condition to match requests of specified version by cookie, empty if not required
+func (c *KtConf) CookieCondition(version string) string {
+	cookie, exists := c.Cookies[version]
+	if !exists || cookie == "" {
+		return ""
+	}
+	parts := strings.SplitN(cookie, "=", 2)
+	if len(parts) == 1 {
+		return fmt.Sprintf("$cookie_%s != \"\"", parts[0])
+	}
+	return fmt.Sprintf("$cookie_%s = \"%s\"", parts[0], parts[1])
+}
+
+// HasFallback check whether requests of specified version should fall back to original service on error response
+func (c *KtConf) HasFallback(version string) bool {
+	_, exists := c.Fallbacks[version]
+	return exists
+}
+
+// FallbackConditions generate proxy_next_upstream conditions of all versions, empty if no fallback required
+func (c *KtConf) FallbackConditions() string {
+	if len(c.Fallbacks) == 0 {
+		return ""
+	}
+	codes := make(map[string]bool)
+	for _, statusCodes := range c.Fallbacks {
+		for _, code := range statusCodes {
+			codes[code] = true
+		}
+	}
+	conditions := make([]string, 0)
+	for code := range codes {
+		conditions = append(conditions, "http_"+code)
+	}
+	sort.Strings(conditions)
+	return strings.Join(append([]string{"error", "timeout"}, conditions...), " ")
 }